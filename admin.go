@@ -0,0 +1,270 @@
+package minio
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/dapr/components-contrib/bindings"
+	"github.com/minio/madmin-go"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/pkg/errors"
+	"net/http"
+	"strconv"
+)
+
+const (
+	// AdminOperationsKey enables the operations backed by the MinIO admin
+	// API. They need admin privileges, so they are off unless asked for.
+	AdminOperationsKey = "adminOperations"
+
+	ServerInfoOperation bindings.OperationKind = "serverInfo"
+)
+
+// adminClient is the subset of the MinIO admin API the binding uses.
+type adminClient interface {
+	ServerInfo(ctx context.Context) (madmin.InfoMessage, error)
+
+	AddUser(ctx context.Context, accessKey, secretKey string) error
+	SetUser(ctx context.Context, accessKey, secretKey string, status madmin.AccountStatus) error
+	SetUserStatus(ctx context.Context, accessKey string, status madmin.AccountStatus) error
+	RemoveUser(ctx context.Context, accessKey string) error
+	GetUserInfo(ctx context.Context, name string) (madmin.UserInfo, error)
+
+	AddCannedPolicy(ctx context.Context, policyName string, policy []byte) error
+	SetPolicy(ctx context.Context, policyName, entityName string, isGroup bool) error
+
+	AddServiceAccount(ctx context.Context, opts madmin.AddServiceAccountReq) (madmin.Credentials, error)
+	DeleteServiceAccount(ctx context.Context, serviceAccount string) error
+
+	GetBucketQuota(ctx context.Context, bucket string) (madmin.BucketQuota, error)
+	SetBucketQuota(ctx context.Context, bucket string, quota *madmin.BucketQuota) error
+
+	Heal(ctx context.Context, bucket, prefix string, healOpts madmin.HealOpts, clientToken string, forceStart, forceStop bool) (madmin.HealStartSuccess, madmin.HealTaskStatus, error)
+	BackgroundHealStatus(ctx context.Context) (madmin.BgHealState, error)
+
+	GetKeyStatus(ctx context.Context, keyID string) (*madmin.KMSKeyStatus, error)
+
+	DataUsageInfo(ctx context.Context) (madmin.DataUsageInfo, error)
+
+	AddTier(ctx context.Context, cfg *madmin.TierConfig) error
+	ListTiers(ctx context.Context) ([]*madmin.TierConfig, error)
+
+	SiteReplicationInfo(ctx context.Context) (madmin.SiteReplicationInfo, error)
+	SRStatusInfo(ctx context.Context, opts madmin.SRStatusOptions) (madmin.SRStatusInfo, error)
+}
+
+// adminOperations are only listed and dispatched when adminOperations is set.
+var adminOperations = []bindings.OperationKind{
+	ServerInfoOperation,
+	CreateUserOperation,
+	SetUserSecretOperation,
+	SetUserStatusOperation,
+	DeleteUserOperation,
+	CreatePolicyOperation,
+	AttachPolicyOperation,
+	CreateServiceAccountOperation,
+	DeleteServiceAccountOperation,
+	GetBucketQuotaOperation,
+	SetBucketQuotaOperation,
+	HealOperation,
+	HealStatusOperation,
+	KMSKeyStatusOperation,
+	DataUsageOperation,
+	AddTierOperation,
+	ListTiersOperation,
+	SiteReplicationStatusOperation,
+}
+
+// newAdminClient builds an admin client sharing the binding's endpoint,
+// credentials and transport.
+func newAdminClient(endpoint string, creds *credentials.Credentials, secure bool, transport http.RoundTripper) (adminClient, error) {
+	client, err := madmin.NewWithOptions(endpoint, &madmin.Options{
+		Creds:  creds,
+		Secure: secure,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("minio binding error. admin client: %w", err)
+	}
+	if transport != nil {
+		client.SetCustomTransport(transport)
+	}
+	return client, nil
+}
+
+func (m *Minio) adminClient() (adminClient, error) {
+	if m.admin == nil {
+		return nil, errors.Errorf("minio binding error. admin operations require %s", AdminOperationsKey)
+	}
+	return m.admin, nil
+}
+
+// targetBucket is the bucket an admin operation or makeBucket applies to.
+// They may name another bucket than the binding's, so one component can
+// manage every tenant's buckets.
+func (m *Minio) targetBucket(p map[string]string) string {
+	if bucket := p[BucketKey]; bucket != "" {
+		return bucket
+	}
+	return m.Bucket
+}
+
+func isAdminOperation(op bindings.OperationKind) bool {
+	for _, o := range adminOperations {
+		if o == op {
+			return true
+		}
+	}
+	return false
+}
+
+// invokeAdmin runs admin operations with the component's credentials, so
+// requests carrying their own are refused rather than silently escalated.
+func (m *Minio) invokeAdmin(ctx context.Context, req *bindings.InvokeRequest) (*bindings.InvokeResponse, error) {
+	p := req.Metadata
+	if p[AccessKey] != "" || p[SecretAccessKey] != "" || p[SessionTokenKey] != "" {
+		return nil, errors.Errorf("minio binding error. %s does not accept request credentials", req.Operation)
+	}
+	admin, err := m.adminClient()
+	if err != nil {
+		return nil, err
+	}
+	switch req.Operation {
+	case ServerInfoOperation:
+		return serverInfo(ctx, admin)
+	case CreateUserOperation:
+		return createUser(ctx, admin, req.Metadata)
+	case SetUserSecretOperation:
+		return setUserSecret(ctx, admin, req.Metadata)
+	case SetUserStatusOperation:
+		return setUserStatus(ctx, admin, req.Metadata)
+	case DeleteUserOperation:
+		return deleteUser(ctx, admin, req.Metadata)
+	case CreatePolicyOperation:
+		return createPolicy(ctx, admin, req)
+	case AttachPolicyOperation:
+		return attachPolicy(ctx, admin, req.Metadata)
+	case CreateServiceAccountOperation:
+		return m.createServiceAccount(ctx, admin, req)
+	case DeleteServiceAccountOperation:
+		return deleteServiceAccount(ctx, admin, req.Metadata)
+	case GetBucketQuotaOperation:
+		return m.getBucketQuota(ctx, admin, req.Metadata)
+	case SetBucketQuotaOperation:
+		return m.setBucketQuota(ctx, admin, req.Metadata)
+	case HealOperation:
+		return m.heal(ctx, admin, req.Metadata)
+	case HealStatusOperation:
+		return m.healStatus(ctx, admin, req.Metadata)
+	case KMSKeyStatusOperation:
+		return m.kmsKeyStatus(ctx, admin, req.Metadata)
+	case DataUsageOperation:
+		return m.dataUsage(ctx, admin, req.Metadata)
+	case AddTierOperation:
+		return addTier(ctx, admin, req.Data)
+	case ListTiersOperation:
+		return listTiers(ctx, admin)
+	case SiteReplicationStatusOperation:
+		return siteReplicationStatus(ctx, admin)
+	default:
+		return nil, errors.Errorf("minio binding error. unsupported operation %s", req.Operation)
+	}
+}
+
+type serverInfoResponse struct {
+	Mode          string         `json:"mode"`
+	DeploymentID  string         `json:"deploymentID"`
+	Region        string         `json:"region,omitempty"`
+	Buckets       uint64         `json:"buckets"`
+	Objects       uint64         `json:"objects"`
+	Usage         uint64         `json:"usage"`
+	OnlineDrives  int            `json:"onlineDrives"`
+	OfflineDrives int            `json:"offlineDrives"`
+	HealingDrives int            `json:"healingDrives"`
+	Servers       []serverStatus `json:"servers"`
+}
+
+type serverStatus struct {
+	Endpoint string        `json:"endpoint"`
+	State    string        `json:"state"`
+	Version  string        `json:"version"`
+	CommitID string        `json:"commitID,omitempty"`
+	Uptime   int64         `json:"uptime"`
+	Pool     int           `json:"pool"`
+	Drives   []driveStatus `json:"drives"`
+}
+
+type driveStatus struct {
+	Endpoint  string `json:"endpoint"`
+	Path      string `json:"path,omitempty"`
+	State     string `json:"state"`
+	Healing   bool   `json:"healing"`
+	Set       int    `json:"set"`
+	Total     uint64 `json:"total"`
+	Used      uint64 `json:"used"`
+	Available uint64 `json:"available"`
+}
+
+// serverInfo reports the cluster topology with the state and version of every
+// server and drive, for health dashboards.
+func serverInfo(ctx context.Context, admin adminClient) (*bindings.InvokeResponse, error) {
+	info, err := admin.ServerInfo(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("minio binding error. server info: %w", err)
+	}
+
+	resp := serverInfoResponse{
+		Mode:         info.Mode,
+		DeploymentID: info.DeploymentID,
+		Region:       info.Region,
+		Buckets:      info.Buckets.Count,
+		Objects:      info.Objects.Count,
+		Usage:        info.Usage.Size,
+		Servers:      make([]serverStatus, 0, len(info.Servers)),
+	}
+	for _, server := range info.Servers {
+		status := serverStatus{
+			Endpoint: server.Endpoint,
+			State:    server.State,
+			Version:  server.Version,
+			CommitID: server.CommitID,
+			Uptime:   server.Uptime,
+			Pool:     server.PoolNumber,
+			Drives:   make([]driveStatus, 0, len(server.Disks)),
+		}
+		for _, disk := range server.Disks {
+			switch {
+			case disk.Healing:
+				resp.HealingDrives++
+			case disk.State == madmin.DriveStateOk:
+				resp.OnlineDrives++
+			default:
+				resp.OfflineDrives++
+			}
+			status.Drives = append(status.Drives, driveStatus{
+				Endpoint:  disk.Endpoint,
+				Path:      disk.DrivePath,
+				State:     disk.State,
+				Healing:   disk.Healing,
+				Set:       disk.SetIndex,
+				Total:     disk.TotalSpace,
+				Used:      disk.UsedSpace,
+				Available: disk.AvailableSpace,
+			})
+		}
+		resp.Servers = append(resp.Servers, status)
+	}
+
+	jsonResponse, err := json.Marshal(resp)
+	if err != nil {
+		return nil, err
+	}
+	return &bindings.InvokeResponse{
+		Data: jsonResponse,
+		Metadata: map[string]string{
+			"mode":          info.Mode,
+			"onlineDrives":  strconv.Itoa(resp.OnlineDrives),
+			"offlineDrives": strconv.Itoa(resp.OfflineDrives),
+			"healingDrives": strconv.Itoa(resp.HealingDrives),
+		},
+	}, nil
+}
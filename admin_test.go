@@ -0,0 +1,274 @@
+package minio
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/dapr/components-contrib/bindings"
+	"github.com/minio/madmin-go"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// fakeAdmin is an in-memory adminClient for unit tests.
+type fakeAdmin struct {
+	info  madmin.InfoMessage
+	users map[string]madmin.UserInfo
+
+	policies map[string][]byte
+	attached map[string]string
+
+	serviceAccounts map[string]madmin.AddServiceAccountReq
+	quotas          map[string]madmin.BucketQuota
+
+	heals      []fakeHeal
+	healStatus madmin.HealTaskStatus
+	background madmin.BgHealState
+
+	keys map[string]madmin.KMSKeyStatus
+
+	usage madmin.DataUsageInfo
+	tiers []*madmin.TierConfig
+
+	siteReplication madmin.SiteReplicationInfo
+	srStatus        madmin.SRStatusInfo
+
+	// err, when set, is returned by every call
+	err error
+}
+
+func (f *fakeAdmin) ServerInfo(ctx context.Context) (madmin.InfoMessage, error) {
+	return f.info, f.err
+}
+
+func (f *fakeAdmin) AddUser(ctx context.Context, accessKey, secretKey string) error {
+	return f.SetUser(ctx, accessKey, secretKey, madmin.AccountEnabled)
+}
+
+func (f *fakeAdmin) SetUser(ctx context.Context, accessKey, secretKey string, status madmin.AccountStatus) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.users[accessKey] = madmin.UserInfo{SecretKey: secretKey, Status: status}
+	return nil
+}
+
+func (f *fakeAdmin) SetUserStatus(ctx context.Context, accessKey string, status madmin.AccountStatus) error {
+	info, err := f.GetUserInfo(ctx, accessKey)
+	if err != nil {
+		return err
+	}
+	info.Status = status
+	f.users[accessKey] = info
+	return nil
+}
+
+func (f *fakeAdmin) RemoveUser(ctx context.Context, accessKey string) error {
+	if _, err := f.GetUserInfo(ctx, accessKey); err != nil {
+		return err
+	}
+	delete(f.users, accessKey)
+	return nil
+}
+
+func (f *fakeAdmin) GetUserInfo(ctx context.Context, name string) (madmin.UserInfo, error) {
+	if f.err != nil {
+		return madmin.UserInfo{}, f.err
+	}
+	info, ok := f.users[name]
+	if !ok {
+		return madmin.UserInfo{}, madmin.ErrorResponse{Code: "XMinioAdminNoSuchUser", Message: "The specified user does not exist."}
+	}
+	return info, nil
+}
+
+func (f *fakeAdmin) AddCannedPolicy(ctx context.Context, policyName string, policy []byte) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.policies[policyName] = policy
+	return nil
+}
+
+// SetPolicy records attachments as "user:name" or "group:name".
+func (f *fakeAdmin) SetPolicy(ctx context.Context, policyName, entityName string, isGroup bool) error {
+	if f.err != nil {
+		return f.err
+	}
+	entity := "user:" + entityName
+	if isGroup {
+		entity = "group:" + entityName
+	}
+	f.attached[entity] = policyName
+	return nil
+}
+
+func (f *fakeAdmin) AddServiceAccount(ctx context.Context, opts madmin.AddServiceAccountReq) (madmin.Credentials, error) {
+	if f.err != nil {
+		return madmin.Credentials{}, f.err
+	}
+	accessKey := fmt.Sprintf("SVCACCT%d", len(f.serviceAccounts)+1)
+	f.serviceAccounts[accessKey] = opts
+	return madmin.Credentials{AccessKey: accessKey, SecretKey: "secret-" + accessKey}, nil
+}
+
+func (f *fakeAdmin) DeleteServiceAccount(ctx context.Context, serviceAccount string) error {
+	if f.err != nil {
+		return f.err
+	}
+	if _, ok := f.serviceAccounts[serviceAccount]; !ok {
+		return madmin.ErrorResponse{Code: "XMinioAdminServiceAccountNotFound", Message: "The specified service account is not found."}
+	}
+	delete(f.serviceAccounts, serviceAccount)
+	return nil
+}
+
+func (f *fakeAdmin) GetBucketQuota(ctx context.Context, bucket string) (madmin.BucketQuota, error) {
+	return f.quotas[bucket], f.err
+}
+
+func (f *fakeAdmin) SetBucketQuota(ctx context.Context, bucket string, quota *madmin.BucketQuota) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.quotas[bucket] = *quota
+	return nil
+}
+
+// fakeHeal records the arguments of a Heal call.
+type fakeHeal struct {
+	bucket, prefix, token string
+	opts                  madmin.HealOpts
+	forceStart, forceStop bool
+}
+
+func (f *fakeAdmin) Heal(ctx context.Context, bucket, prefix string, healOpts madmin.HealOpts, clientToken string, forceStart, forceStop bool) (madmin.HealStartSuccess, madmin.HealTaskStatus, error) {
+	if f.err != nil {
+		return madmin.HealStartSuccess{}, madmin.HealTaskStatus{}, f.err
+	}
+	f.heals = append(f.heals, fakeHeal{bucket, prefix, clientToken, healOpts, forceStart, forceStop})
+	if clientToken != "" {
+		return madmin.HealStartSuccess{}, f.healStatus, nil
+	}
+	return madmin.HealStartSuccess{ClientToken: fmt.Sprintf("heal-%d", len(f.heals))}, madmin.HealTaskStatus{}, nil
+}
+
+func (f *fakeAdmin) BackgroundHealStatus(ctx context.Context) (madmin.BgHealState, error) {
+	return f.background, f.err
+}
+
+// GetKeyStatus reports keys missing from f.keys as healthy.
+func (f *fakeAdmin) GetKeyStatus(ctx context.Context, keyID string) (*madmin.KMSKeyStatus, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	status, ok := f.keys[keyID]
+	if !ok {
+		status = madmin.KMSKeyStatus{KeyID: keyID}
+	}
+	return &status, nil
+}
+
+func (f *fakeAdmin) DataUsageInfo(ctx context.Context) (madmin.DataUsageInfo, error) {
+	return f.usage, f.err
+}
+
+func (f *fakeAdmin) AddTier(ctx context.Context, cfg *madmin.TierConfig) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.tiers = append(f.tiers, cfg)
+	return nil
+}
+
+func (f *fakeAdmin) ListTiers(ctx context.Context) ([]*madmin.TierConfig, error) {
+	return f.tiers, f.err
+}
+
+func (f *fakeAdmin) SiteReplicationInfo(ctx context.Context) (madmin.SiteReplicationInfo, error) {
+	return f.siteReplication, f.err
+}
+
+func (f *fakeAdmin) SRStatusInfo(ctx context.Context, opts madmin.SRStatusOptions) (madmin.SRStatusInfo, error) {
+	return f.srStatus, f.err
+}
+
+func newFakeAdminMinio() (*Minio, *fakeAdmin) {
+	m, _ := newFakeMinio()
+	admin := &fakeAdmin{
+		users:    map[string]madmin.UserInfo{},
+		policies: map[string][]byte{},
+		attached: map[string]string{},
+
+		serviceAccounts: map[string]madmin.AddServiceAccountReq{},
+		quotas:          map[string]madmin.BucketQuota{},
+	}
+	m.admin = admin
+	return m, admin
+}
+
+func TestAdminOperationsDisabled(t *testing.T) {
+	m, _ := newFakeMinio()
+	assert.NotContains(t, m.Operations(), ServerInfoOperation)
+
+	_, err := m.Invoke(&bindings.InvokeRequest{Operation: ServerInfoOperation})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), AdminOperationsKey)
+}
+
+func TestAdminOperationsRejectRequestCredentials(t *testing.T) {
+	m, admin := newFakeAdminMinio()
+	m.allowCredentialOverride = true
+
+	_, err := m.Invoke(&bindings.InvokeRequest{Operation: CreateUserOperation, Metadata: map[string]string{
+		AccessKey: "tenant", SecretAccessKey: "tenant-secret", UserAccessKeyKey: "u", UserSecretKeyKey: "u-secret",
+	}})
+	assert.EqualError(t, err, "minio binding error. createUser does not accept request credentials")
+	assert.Empty(t, admin.users)
+}
+
+func TestServerInfo(t *testing.T) {
+	m, admin := newFakeAdminMinio()
+	assert.Contains(t, m.Operations(), ServerInfoOperation)
+	admin.info = madmin.InfoMessage{
+		Mode:         "online",
+		DeploymentID: "d1",
+		Buckets:      madmin.Buckets{Count: 2},
+		Objects:      madmin.Objects{Count: 10},
+		Servers: []madmin.ServerProperties{
+			{
+				Endpoint: "minio-0:9000",
+				State:    "online",
+				Version:  "2021-10-13T00:23:17Z",
+				Disks: []madmin.Disk{
+					{Endpoint: "/data1", State: madmin.DriveStateOk, TotalSpace: 100, UsedSpace: 40, AvailableSpace: 60},
+					{Endpoint: "/data2", State: madmin.DriveStateOffline},
+					{Endpoint: "/data3", State: madmin.DriveStateOk, Healing: true, SetIndex: 1},
+				},
+			},
+		},
+	}
+
+	resp, err := m.Invoke(&bindings.InvokeRequest{Operation: ServerInfoOperation})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"mode": "online", "onlineDrives": "1", "offlineDrives": "1", "healingDrives": "1"}, resp.Metadata)
+
+	info := serverInfoResponse{}
+	assert.Nil(t, json.Unmarshal(resp.Data, &info))
+	assert.Equal(t, "d1", info.DeploymentID)
+	assert.Equal(t, uint64(2), info.Buckets)
+	assert.Equal(t, uint64(10), info.Objects)
+	assert.Len(t, info.Servers, 1)
+	assert.Equal(t, "2021-10-13T00:23:17Z", info.Servers[0].Version)
+	assert.Equal(t, driveStatus{Endpoint: "/data1", State: "ok", Total: 100, Used: 40, Available: 60}, info.Servers[0].Drives[0])
+	assert.Equal(t, 1, info.Servers[0].Drives[2].Set)
+}
+
+func TestServerInfoError(t *testing.T) {
+	m, admin := newFakeAdminMinio()
+	admin.err = errors.Errorf("connection refused")
+
+	_, err := m.Invoke(&bindings.InvokeRequest{Operation: ServerInfoOperation})
+	assert.Error(t, err)
+}
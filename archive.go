@@ -0,0 +1,219 @@
+package minio
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/dapr/components-contrib/bindings"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/encrypt"
+	"github.com/pkg/errors"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+)
+
+const (
+	// ArchiveOperation packs the objects named by a JSON array of keys in the
+	// request data, or every object under prefix, into one tar or zip. With
+	// objectName set the archive is streamed into that object, otherwise it
+	// is returned as the response data, up to maxGetSize. Objects are archived
+	// as stored, except that client-side encrypted ones are decrypted.
+	ArchiveOperation bindings.OperationKind = "archive"
+	ArchiveFormatKey                        = "archiveFormat"
+
+	ArchiveFormatTar = "tar"
+	ArchiveFormatZip = "zip"
+)
+
+// archiveWriter is the part of *tar.Writer and *zip.Writer archive writes
+// entries through.
+type archiveWriter interface {
+	add(info minio.ObjectInfo, name string, r io.Reader) error
+	Close() error
+}
+
+type tarArchive struct{ *tar.Writer }
+
+func (a tarArchive) add(info minio.ObjectInfo, name string, r io.Reader) error {
+	err := a.WriteHeader(&tar.Header{Typeflag: tar.TypeReg, Name: name, Size: info.Size, Mode: 0o644, ModTime: info.LastModified})
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(a, r)
+	return err
+}
+
+type zipArchive struct{ *zip.Writer }
+
+func (a zipArchive) add(info minio.ObjectInfo, name string, r io.Reader) error {
+	w, err := a.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: info.LastModified})
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, r)
+	return err
+}
+
+func newArchiveWriter(format string, w io.Writer) (archiveWriter, error) {
+	switch format {
+	case "", ArchiveFormatTar:
+		return tarArchive{tar.NewWriter(w)}, nil
+	case ArchiveFormatZip:
+		return zipArchive{zip.NewWriter(w)}, nil
+	default:
+		return nil, errors.Errorf("unsupported Minio archiveFormat %s", format)
+	}
+}
+
+// limitWriter fails writes past limit bytes, so an archive returned in the
+// response can't exhaust memory.
+type limitWriter struct {
+	bytes.Buffer
+	limit int64
+}
+
+func (w *limitWriter) Write(b []byte) (int, error) {
+	if w.limit > 0 && int64(w.Len()+len(b)) > w.limit {
+		return 0, &Error{Code: ErrCodeTooLarge, Operation: ArchiveOperation, Err: errors.Errorf("archive is larger than maxGetSize %d", w.limit)}
+	}
+	return w.Buffer.Write(b)
+}
+
+type archiveEntry struct {
+	key  string
+	name string
+}
+
+func (m *Minio) archive(ctx context.Context, req *bindings.InvokeRequest) (*bindings.InvokeResponse, error) {
+	p := req.Metadata
+
+	format := p[ArchiveFormatKey]
+	if format == "" {
+		format = ArchiveFormatTar
+	}
+	if _, err := newArchiveWriter(format, ioutil.Discard); err != nil {
+		return nil, err
+	}
+	client, err := m.clientFor(p)
+	if err != nil {
+		return nil, err
+	}
+	sse, err := readEncryption(m.properties, p)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := m.archiveEntries(ctx, client, p, req.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	write := func(w io.Writer) error {
+		archive, _ := newArchiveWriter(format, w)
+		for _, entry := range entries {
+			if err := m.archiveOne(ctx, client, archive, entry, sse); err != nil {
+				return fmt.Errorf("minio binding error. archive %s: %w", entry.key, err)
+			}
+		}
+		return archive.Close()
+	}
+	metadata := map[string]string{
+		"archiveFormat": format,
+		"objects":       strconv.Itoa(len(entries)),
+	}
+
+	if objectNameOf(p) == "" {
+		buf := &limitWriter{limit: m.maxGetSize}
+		if err := write(buf); err != nil {
+			return nil, err
+		}
+		metadata["size"] = strconv.Itoa(buf.Len())
+		return &bindings.InvokeResponse{Data: buf.Bytes(), Metadata: metadata}, nil
+	}
+
+	// the archive is uploaded as it is written
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(write(pw))
+	}()
+	resp, err := m.upload(ctx, p, pr, -1)
+	pr.CloseWithError(err)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range metadata {
+		resp.Metadata[k] = v
+	}
+	return resp, nil
+}
+
+// archiveEntries returns the keys to archive and their names in the archive,
+// relative to prefix when one is used.
+func (m *Minio) archiveEntries(ctx context.Context, client objectClient, p map[string]string, data []byte) ([]archiveEntry, error) {
+	prefix := p[PrefixKey]
+	if len(data) > 0 {
+		if prefix != "" {
+			return nil, errors.Errorf("minio binding error. archive takes either keys or a %s", PrefixKey)
+		}
+		var keys []string
+		if err := json.Unmarshal(data, &keys); err != nil {
+			return nil, fmt.Errorf("minio binding error. archive expects a JSON array of keys: %w", err)
+		}
+		if len(keys) == 0 {
+			return nil, errors.Errorf("missing keys field")
+		}
+		entries := make([]archiveEntry, len(keys))
+		for i, key := range keys {
+			entries[i] = archiveEntry{key: key, name: key}
+		}
+		return entries, nil
+	}
+	if prefix == "" {
+		return nil, errors.Errorf("missing keys field")
+	}
+
+	objects, err := m.listPrefix(ctx, client, m.Bucket, prefix)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]archiveEntry, 0, len(objects))
+	for _, object := range objects {
+		// folder markers have no content of their own
+		if name := strings.TrimPrefix(object.Key, prefix); name != "" && !strings.HasSuffix(name, "/") {
+			entries = append(entries, archiveEntry{key: object.Key, name: name})
+		}
+	}
+	return entries, nil
+}
+
+// archiveOne streams an object into the archive; a client-side encrypted
+// one is read whole to be decrypted.
+func (m *Minio) archiveOne(ctx context.Context, client objectClient, archive archiveWriter, entry archiveEntry, sse encrypt.ServerSide) error {
+	reader, err := client.GetObject(ctx, m.Bucket, entry.key, minio.GetObjectOptions{ServerSideEncryption: sse})
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	info, err := reader.Stat()
+	if err != nil {
+		return err
+	}
+	if !isClientEncrypted(info) {
+		return archive.add(info, entry.name, reader)
+	}
+
+	sealed, err := readByBuffer(reader, info.Size)
+	if err != nil {
+		return err
+	}
+	data, err := decryptObject(m.clientCipher, sealed)
+	if err != nil {
+		return err
+	}
+	info.Size = int64(len(data))
+	return archive.add(info, entry.name, bytes.NewReader(data))
+}
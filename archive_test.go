@@ -0,0 +1,113 @@
+package minio
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"encoding/base64"
+	"github.com/dapr/components-contrib/bindings"
+	"github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"io/ioutil"
+	"testing"
+)
+
+func readTar(t *testing.T, data []byte) map[string]string {
+	files := map[string]string{}
+	r := tar.NewReader(bytes.NewReader(data))
+	for {
+		header, err := r.Next()
+		if err == io.EOF {
+			return files
+		}
+		assert.Nil(t, err)
+		content, _ := ioutil.ReadAll(r)
+		files[header.Name] = string(content)
+	}
+}
+
+func readZip(t *testing.T, data []byte) map[string]string {
+	files := map[string]string{}
+	r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	assert.Nil(t, err)
+	for _, f := range r.File {
+		rc, err := f.Open()
+		assert.Nil(t, err)
+		content, _ := ioutil.ReadAll(rc)
+		rc.Close()
+		files[f.Name] = string(content)
+	}
+	return files
+}
+
+func TestArchivePrefixAsTar(t *testing.T) {
+	m, fake := newFakeMinio()
+	fake.put("b", "docs/a.txt", []byte("a"), minio.ObjectInfo{})
+	fake.put("b", "docs/sub/b.txt", []byte("b"), minio.ObjectInfo{})
+	fake.put("b", "docs/sub/", nil, minio.ObjectInfo{})
+	fake.put("b", "other.txt", []byte("other"), minio.ObjectInfo{})
+
+	resp, err := m.Invoke(&bindings.InvokeRequest{Operation: ArchiveOperation, Metadata: map[string]string{"prefix": "docs/"}})
+	assert.Nil(t, err)
+	assert.Equal(t, "2", resp.Metadata["objects"])
+	assert.Equal(t, map[string]string{"a.txt": "a", "sub/b.txt": "b"}, readTar(t, resp.Data))
+}
+
+func TestArchiveKeysAsZip(t *testing.T) {
+	m, fake := newFakeMinio()
+	fake.put("b", "a.txt", []byte("a"), minio.ObjectInfo{})
+	fake.put("b", "dir/b.txt", []byte("b"), minio.ObjectInfo{})
+
+	resp, err := m.Invoke(&bindings.InvokeRequest{Operation: ArchiveOperation, Data: []byte(`["a.txt","dir/b.txt"]`), Metadata: map[string]string{"archiveFormat": "zip"}})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"a.txt": "a", "dir/b.txt": "b"}, readZip(t, resp.Data))
+}
+
+func TestArchiveToObject(t *testing.T) {
+	m, fake := newFakeMinio()
+	fake.put("b", "docs/a.txt", []byte("a"), minio.ObjectInfo{})
+
+	resp, err := m.Invoke(&bindings.InvokeRequest{Operation: ArchiveOperation, Metadata: map[string]string{"prefix": "docs/", "key": "docs.zip", "archiveFormat": "zip"}})
+	assert.Nil(t, err)
+	assert.Equal(t, "docs.zip", resp.Metadata["key"])
+	assert.Equal(t, "1", resp.Metadata["objects"])
+	assert.Equal(t, map[string]string{"a.txt": "a"}, readZip(t, fake.buckets["b"]["docs.zip"].data))
+}
+
+func TestArchiveDecryptsClientEncrypted(t *testing.T) {
+	m, fake := newFakeMinio()
+	key := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, 32))
+	m.clientCipher, _ = newClientCipher(map[string]string{ClientEncryptionKeyKey: key})
+	_, err := m.Invoke(&bindings.InvokeRequest{Operation: bindings.CreateOperation, Data: []byte("secret"), Metadata: map[string]string{"key": "s.txt"}})
+	assert.Nil(t, err)
+	assert.NotEqual(t, "secret", string(fake.buckets["b"]["s.txt"].data))
+
+	resp, err := m.Invoke(&bindings.InvokeRequest{Operation: ArchiveOperation, Data: []byte(`["s.txt"]`)})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"s.txt": "secret"}, readTar(t, resp.Data))
+}
+
+func TestArchiveErrors(t *testing.T) {
+	m, fake := newFakeMinio()
+	fake.put("b", "a.txt", bytes.Repeat([]byte("a"), 2048), minio.ObjectInfo{})
+
+	_, err := m.Invoke(&bindings.InvokeRequest{Operation: ArchiveOperation})
+	assert.EqualError(t, err, "missing keys field")
+	_, err = m.Invoke(&bindings.InvokeRequest{Operation: ArchiveOperation, Data: []byte(`["a.txt"]`), Metadata: map[string]string{"archiveFormat": "rar"}})
+	assert.EqualError(t, err, "unsupported Minio archiveFormat rar")
+	_, err = m.Invoke(&bindings.InvokeRequest{Operation: ArchiveOperation, Data: []byte(`["a.txt"]`), Metadata: map[string]string{"prefix": "a"}})
+	assert.Error(t, err)
+
+	_, err = m.Invoke(&bindings.InvokeRequest{Operation: ArchiveOperation, Data: []byte(`["a.txt","gone.txt"]`)})
+	assert.Equal(t, ErrCodeNotFound, ErrorCode(err))
+
+	m.maxGetSize = 1024
+	_, err = m.Invoke(&bindings.InvokeRequest{Operation: ArchiveOperation, Data: []byte(`["a.txt"]`)})
+	assert.Equal(t, ErrCodeTooLarge, ErrorCode(err))
+
+	// nothing is left behind when an archive upload fails
+	_, err = m.Invoke(&bindings.InvokeRequest{Operation: ArchiveOperation, Data: []byte(`["a.txt","gone.txt"]`), Metadata: map[string]string{"key": "out.tar"}})
+	assert.Error(t, err)
+	assert.NotContains(t, fake.buckets["b"], "out.tar")
+}
@@ -0,0 +1,228 @@
+package minio
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/dapr/components-contrib/bindings"
+	"github.com/dapr/kit/logger"
+	"github.com/google/uuid"
+	"github.com/minio/minio-go/v7"
+	"github.com/pkg/errors"
+	"strings"
+	"time"
+)
+
+const (
+	AuditLogKey    = "auditLog"
+	AuditPrefixKey = "auditPrefix"
+
+	AuditLogLogger = "logger"
+	AuditLogBucket = "bucket"
+
+	defaultAuditPrefix  = "audit/"
+	auditRecordTimeout  = 10 * time.Second
+	auditRecordMimeType = "application/json"
+	auditQueueSize      = 1000
+)
+
+// auditedOperations are the operations that change the bucket, its quota or
+// healing, the binding's credentials or MinIO's users, policies, service
+// accounts and tiers.
+var auditedOperations = map[bindings.OperationKind]bool{
+	bindings.CreateOperation:      true,
+	bindings.DeleteOperation:      true,
+	CreateBatchOperation:          true,
+	MakeBucketOperation:           true,
+	CopyOperation:                 true,
+	MovePrefixOperation:           true,
+	SyncPrefixOperation:           true,
+	ArchiveOperation:              true,
+	UnpackOperation:               true,
+	RotateCredentialsOperation:    true,
+	CreateUserOperation:           true,
+	SetUserSecretOperation:        true,
+	SetUserStatusOperation:        true,
+	DeleteUserOperation:           true,
+	CreatePolicyOperation:         true,
+	AttachPolicyOperation:         true,
+	CreateServiceAccountOperation: true,
+	DeleteServiceAccountOperation: true,
+	SetBucketQuotaOperation:       true,
+	HealOperation:                 true,
+	AddTierOperation:              true,
+}
+
+type auditConfig struct {
+	target string
+	prefix string
+}
+
+type auditRecord struct {
+	Time           time.Time `json:"time"`
+	Principal      string    `json:"principal"`
+	Operation      string    `json:"operation"`
+	Bucket         string    `json:"bucket"`
+	Key            string    `json:"key,omitempty"`
+	VersionID      string    `json:"versionID,omitempty"`
+	User           string    `json:"user,omitempty"`
+	ServiceAccount string    `json:"serviceAccount,omitempty"`
+	Outcome        string    `json:"outcome"`
+	Error          string    `json:"error,omitempty"`
+}
+
+func parseAuditConfig(p map[string]string) (auditConfig, error) {
+	cfg := auditConfig{target: p[AuditLogKey], prefix: p[AuditPrefixKey]}
+	switch cfg.target {
+	case "", AuditLogLogger, AuditLogBucket:
+	default:
+		return cfg, errors.Errorf("unsupported Minio auditLog %s", cfg.target)
+	}
+	if cfg.prefix == "" {
+		cfg.prefix = defaultAuditPrefix
+	}
+	return cfg, nil
+}
+
+// recordAudit writes an audit record for mutating operations. Records are
+// never updated: in bucket mode each one is stored as its own object, written
+// in the background so a slow audit target doesn't hold up operations. A
+// failure to write the record is logged but doesn't fail the operation,
+// which has already taken effect.
+func (m *Minio) recordAudit(req *bindings.InvokeRequest, resp *bindings.InvokeResponse, err error, at time.Time) {
+	if m.audit.target == "" || m.minioClient == nil || !auditedOperations[req.Operation] {
+		return
+	}
+	record := m.newAuditRecord(req, resp, err, at)
+	data, merr := json.Marshal(record)
+	if merr != nil {
+		m.logger.Errorf("Minio binding cannot marshal audit record: %s", merr)
+		return
+	}
+	if m.audit.target == AuditLogLogger {
+		m.logger.Infof("Minio binding audit %s", data)
+		return
+	}
+	m.auditWriter.enqueue(auditObjectName(m.audit.prefix, at), data)
+}
+
+// auditWriter stores bucket audit records from a bounded queue. Records that
+// don't fit in the queue are logged instead of stored; the ones still queued
+// when the binding closes are written before it stops.
+type auditWriter struct {
+	client objectClient
+	bucket string
+	queue  chan auditEntry
+	logger logger.Logger
+}
+
+type auditEntry struct {
+	name string
+	data []byte
+}
+
+func newAuditWriter(client objectClient, bucket string, log logger.Logger) *auditWriter {
+	return &auditWriter{client: client, bucket: bucket, queue: make(chan auditEntry, auditQueueSize), logger: log}
+}
+
+func (w *auditWriter) enqueue(name string, data []byte) {
+	select {
+	case w.queue <- auditEntry{name: name, data: data}:
+	default:
+		w.logger.Errorf("Minio binding audit queue is full, record not written: %s", data)
+	}
+}
+
+// run writes queued records until ctx is done, then flushes the queue.
+func (w *auditWriter) run(ctx context.Context) {
+	for {
+		select {
+		case entry := <-w.queue:
+			w.write(entry)
+		case <-ctx.Done():
+			for {
+				select {
+				case entry := <-w.queue:
+					w.write(entry)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (w *auditWriter) write(entry auditEntry) {
+	ctx, cancel := context.WithTimeout(context.Background(), auditRecordTimeout)
+	defer cancel()
+	_, err := w.client.PutObject(ctx, w.bucket, entry.name, bytes.NewReader(entry.data), int64(len(entry.data)), minio.PutObjectOptions{
+		ContentType: auditRecordMimeType,
+	})
+	if err != nil {
+		w.logger.Errorf("Minio binding failed to write audit record %s: %s", entry.data, err)
+	}
+}
+
+func (m *Minio) newAuditRecord(req *bindings.InvokeRequest, resp *bindings.InvokeResponse, err error, at time.Time) auditRecord {
+	record := auditRecord{
+		Time:           at.UTC(),
+		Principal:      m.principal(req),
+		Operation:      string(req.Operation),
+		Bucket:         m.Bucket,
+		Key:            objectNameOf(req.Metadata),
+		User:           req.Metadata[UserAccessKeyKey],
+		ServiceAccount: req.Metadata[ServiceAccountKey],
+		Outcome:        "OK",
+	}
+	if isAdminOperation(req.Operation) || req.Operation == MakeBucketOperation {
+		record.Bucket = m.targetBucket(req.Metadata)
+	}
+	if resp != nil && resp.Metadata != nil {
+		if key := resp.Metadata["key"]; key != "" {
+			record.Key = key
+		}
+		record.VersionID = resp.Metadata["versionID"]
+		if account := resp.Metadata[ServiceAccountKey]; account != "" {
+			record.ServiceAccount = account
+		}
+	}
+	if err != nil {
+		record.Outcome = ErrorCode(err)
+		if record.Outcome == "" {
+			record.Outcome = "Error"
+		}
+		record.Error = err.Error()
+	}
+	return record
+}
+
+// principal is the access key the operation was signed with, or the
+// authentication mode when the key isn't known up front. Secrets are never
+// included.
+func (m *Minio) principal(req *bindings.InvokeRequest) string {
+	if req.Operation != RotateCredentialsOperation && m.allowCredentialOverride {
+		if key := req.Metadata[AccessKey]; key != "" {
+			return key
+		}
+	}
+	if propertyToBool(m.properties, AnonymousKey) {
+		return "anonymous"
+	}
+	if key := m.rotated[AccessKey]; key != "" {
+		return key
+	}
+	if key := m.properties[AccessKey]; key != "" {
+		return key
+	}
+	if mode := m.properties[AuthModeKey]; mode != "" {
+		return mode
+	}
+	return AuthModeStatic
+}
+
+// auditObjectName groups records by day and keeps them in time order when
+// listed.
+func auditObjectName(prefix string, at time.Time) string {
+	at = at.UTC()
+	return strings.TrimSuffix(prefix, "/") + "/" + at.Format("2006/01/02/150405.000000000") + "-" + uuid.New().String() + ".json"
+}
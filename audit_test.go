@@ -0,0 +1,87 @@
+package minio
+
+import (
+	"errors"
+	"github.com/dapr/components-contrib/bindings"
+	"github.com/dapr/kit/logger"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseAuditConfig(t *testing.T) {
+	cfg, err := parseAuditConfig(map[string]string{AuditLogKey: AuditLogBucket})
+	assert.Nil(t, err)
+	assert.Equal(t, auditConfig{target: AuditLogBucket, prefix: defaultAuditPrefix}, cfg)
+
+	_, err = parseAuditConfig(map[string]string{AuditLogKey: "syslog"})
+	assert.EqualError(t, err, "unsupported Minio auditLog syslog")
+}
+
+func TestNewAuditRecord(t *testing.T) {
+	m := NewMinio(logger.NewLogger("minio"))
+	m.Bucket = "b"
+	m.properties = map[string]string{AccessKey: "service", SecretAccessKey: "secret"}
+	at := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	req := &bindings.InvokeRequest{Operation: bindings.CreateOperation, Metadata: map[string]string{"tenant": "acme"}}
+	resp := &bindings.InvokeResponse{Metadata: map[string]string{"key": "tenants/acme/a.txt", "versionID": "v1"}}
+	assert.Equal(t, auditRecord{
+		Time:      at,
+		Principal: "service",
+		Operation: "create",
+		Bucket:    "b",
+		Key:       "tenants/acme/a.txt",
+		VersionID: "v1",
+		Outcome:   "OK",
+	}, m.newAuditRecord(req, resp, nil, at))
+
+	m.allowCredentialOverride = true
+	req = &bindings.InvokeRequest{Operation: bindings.DeleteOperation, Metadata: map[string]string{
+		"objectName": "a.txt", AccessKey: "tenant", SecretAccessKey: "tenant-secret",
+	}}
+	record := m.newAuditRecord(req, nil, &Error{Code: ErrCodeAccessDenied, Operation: "delete", Err: errors.New("denied")}, at)
+	assert.Equal(t, "tenant", record.Principal)
+	assert.Equal(t, "a.txt", record.Key)
+	assert.Equal(t, ErrCodeAccessDenied, record.Outcome)
+	assert.NotContains(t, record.Error, "secret")
+}
+
+func TestAuditObjectName(t *testing.T) {
+	at := time.Date(2021, 6, 1, 12, 30, 5, 0, time.UTC)
+	name := auditObjectName("audit/", at)
+	assert.True(t, strings.HasPrefix(name, "audit/2021/06/01/123005.000000000-"), name)
+	assert.True(t, strings.HasSuffix(name, ".json"), name)
+	assert.NotEqual(t, name, auditObjectName("audit/", at))
+}
+
+func TestBucketAuditIsWrittenInBackground(t *testing.T) {
+	m, fake := newFakeMinio()
+	m.audit = auditConfig{target: AuditLogBucket, prefix: defaultAuditPrefix}
+	m.auditWriter = newAuditWriter(fake, "b", m.logger)
+
+	_, err := m.Invoke(&bindings.InvokeRequest{Operation: bindings.CreateOperation, Data: []byte("x"), Metadata: map[string]string{"objectName": "a.txt"}})
+	assert.Nil(t, err)
+	// nothing is written until the writer runs
+	assert.Len(t, fake.keys("b"), 1)
+
+	m.cancel()
+	m.auditWriter.run(m.ctx)
+	keys := fake.keys("b")
+	assert.Len(t, keys, 2)
+	assert.True(t, strings.HasPrefix(keys[1], defaultAuditPrefix), keys[1])
+}
+
+func TestAuditQueueFull(t *testing.T) {
+	m, fake := newFakeMinio()
+	w := &auditWriter{client: fake, bucket: "b", queue: make(chan auditEntry, 1), logger: m.logger}
+	w.enqueue("audit/1.json", []byte("{}"))
+	w.enqueue("audit/2.json", []byte("{}"))
+	assert.Len(t, w.queue, 1)
+
+	// queued records are flushed when the binding closes
+	m.cancel()
+	w.run(m.ctx)
+	assert.Equal(t, []string{"audit/1.json"}, fake.keys("b"))
+}
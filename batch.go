@@ -0,0 +1,181 @@
+package minio
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/dapr/components-contrib/bindings"
+	"github.com/pkg/errors"
+	"sync"
+)
+
+const (
+	GetBatchOperation    bindings.OperationKind = "getBatch"
+	CreateBatchOperation bindings.OperationKind = "createBatch"
+
+	ConcurrencyKey = "concurrency"
+
+	defaultBatchConcurrency = 8
+)
+
+type batchCreateItem struct {
+	ObjectName string            `json:"objectName"`
+	Data       []byte            `json:"data"`
+	Metadata   map[string]string `json:"metadata,omitempty"`
+}
+
+type batchCreateResult struct {
+	Key       string `json:"key"`
+	Location  string `json:"location,omitempty"`
+	VersionID string `json:"versionID,omitempty"`
+	ETag      string `json:"etag,omitempty"`
+	Error     string `json:"error,omitempty"`
+	Code      string `json:"code,omitempty"`
+}
+
+type batchGetResult struct {
+	Data     []byte            `json:"data,omitempty"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+	Error    string            `json:"error,omitempty"`
+	Code     string            `json:"code,omitempty"`
+}
+
+// getBatch downloads the JSON array of keys in the request data with a
+// bounded number of concurrent gets. A failed key is reported in its result
+// rather than failing the whole batch.
+func (m *Minio) getBatch(ctx context.Context, req *bindings.InvokeRequest) (*bindings.InvokeResponse, error) {
+	var keys []string
+	if err := json.Unmarshal(req.Data, &keys); err != nil {
+		return nil, fmt.Errorf("minio binding error. getBatch expects a JSON array of keys: %w", err)
+	}
+	if len(keys) == 0 {
+		return nil, errors.Errorf("missing keys field")
+	}
+	concurrency, err := batchConcurrency(req.Metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		mu      sync.Mutex
+		results = make(map[string]batchGetResult, len(keys))
+	)
+	runBounded(concurrency, len(keys), func(i int) {
+		result := m.getOne(ctx, req.Metadata, keys[i])
+		mu.Lock()
+		results[keys[i]] = result
+		mu.Unlock()
+	})
+
+	b, err := json.Marshal(results)
+	if err != nil {
+		return nil, err
+	}
+	return &bindings.InvokeResponse{Data: b}, nil
+}
+
+func (m *Minio) getOne(ctx context.Context, metadata map[string]string, key string) batchGetResult {
+	p := make(map[string]string, len(metadata)+1)
+	for k, v := range metadata {
+		p[k] = v
+	}
+	p["objectName"] = key
+
+	resp, err := m.get(ctx, &bindings.InvokeRequest{Operation: bindings.GetOperation, Metadata: p})
+	if err != nil {
+		err = newError(bindings.GetOperation, err)
+		return batchGetResult{Error: err.Error(), Code: ErrorCode(err)}
+	}
+	return batchGetResult{Data: resp.Data, Metadata: resp.Metadata}
+}
+
+// createBatch uploads a JSON array of {objectName, data, metadata} items, with
+// data base64 encoded, using a bounded number of concurrent uploads. Results
+// are returned in request order; a failed item doesn't fail the batch. Item
+// metadata is merged over the request metadata.
+func (m *Minio) createBatch(ctx context.Context, req *bindings.InvokeRequest) (*bindings.InvokeResponse, error) {
+	var items []batchCreateItem
+	if err := json.Unmarshal(req.Data, &items); err != nil {
+		return nil, fmt.Errorf("minio binding error. createBatch expects a JSON array of objects: %w", err)
+	}
+	if len(items) == 0 {
+		return nil, errors.Errorf("missing objects field")
+	}
+	concurrency, err := batchConcurrency(req.Metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]batchCreateResult, len(items))
+	runBounded(concurrency, len(items), func(i int) {
+		results[i] = m.createOne(ctx, req.Metadata, items[i])
+	})
+
+	b, err := json.Marshal(results)
+	if err != nil {
+		return nil, err
+	}
+	return &bindings.InvokeResponse{Data: b}, nil
+}
+
+func (m *Minio) createOne(ctx context.Context, metadata map[string]string, item batchCreateItem) batchCreateResult {
+	p := make(map[string]string, len(metadata)+len(item.Metadata)+1)
+	for k, v := range metadata {
+		p[k] = v
+	}
+	for k, v := range item.Metadata {
+		p[k] = v
+	}
+	if item.ObjectName != "" {
+		p["objectName"] = item.ObjectName
+	}
+
+	resp, err := m.upload(ctx, p, bytes.NewReader(item.Data), int64(len(item.Data)))
+	if err != nil {
+		err = newError(bindings.CreateOperation, err)
+		return batchCreateResult{Key: item.ObjectName, Error: err.Error(), Code: ErrorCode(err)}
+	}
+	var created createResponse
+	if err := json.Unmarshal(resp.Data, &created); err != nil {
+		return batchCreateResult{Key: item.ObjectName, Error: err.Error()}
+	}
+	return batchCreateResult{
+		Key:       created.Key,
+		Location:  created.Location,
+		VersionID: created.VersionID,
+		ETag:      resp.Metadata["etag"],
+	}
+}
+
+func batchConcurrency(p map[string]string) (int64, error) {
+	concurrency, err := sizeProperty(p, ConcurrencyKey, defaultBatchConcurrency)
+	if err != nil {
+		return 0, err
+	}
+	if concurrency == 0 {
+		concurrency = defaultBatchConcurrency
+	}
+	return concurrency, nil
+}
+
+// runBounded calls fn for every index in [0, n) from at most concurrency
+// goroutines and waits for all of them.
+func runBounded(concurrency int64, n int, fn func(i int)) {
+	var wg sync.WaitGroup
+	work := make(chan int)
+	for w := int64(0); w < concurrency && w < int64(n); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range work {
+				fn(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		work <- i
+	}
+	close(work)
+	wg.Wait()
+}
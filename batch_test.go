@@ -0,0 +1,63 @@
+package minio
+
+import (
+	"context"
+	"github.com/dapr/components-contrib/bindings"
+	"github.com/dapr/kit/logger"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestGetBatchValidation(t *testing.T) {
+	m := NewMinio(logger.NewLogger("minio"))
+
+	_, err := m.getBatch(context.Background(), &bindings.InvokeRequest{Data: []byte(`{"key":"a"}`)})
+	assert.Error(t, err)
+
+	_, err = m.getBatch(context.Background(), &bindings.InvokeRequest{Data: []byte(`[]`)})
+	assert.EqualError(t, err, "missing keys field")
+
+	_, err = m.getBatch(context.Background(), &bindings.InvokeRequest{
+		Data:     []byte(`["a"]`),
+		Metadata: map[string]string{ConcurrencyKey: "-1"},
+	})
+	assert.EqualError(t, err, "concurrency -1 is invalid")
+}
+
+func TestCreateBatchValidation(t *testing.T) {
+	m := NewMinio(logger.NewLogger("minio"))
+
+	_, err := m.createBatch(context.Background(), &bindings.InvokeRequest{Data: []byte(`["a"]`)})
+	assert.Error(t, err)
+
+	_, err = m.createBatch(context.Background(), &bindings.InvokeRequest{Data: []byte(`[]`)})
+	assert.EqualError(t, err, "missing objects field")
+}
+
+func TestRunBounded(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		running int
+		peak    int
+		seen    = make([]bool, 20)
+	)
+	runBounded(3, len(seen), func(i int) {
+		mu.Lock()
+		running++
+		if running > peak {
+			peak = running
+		}
+		seen[i] = true
+		mu.Unlock()
+		time.Sleep(time.Millisecond)
+		mu.Lock()
+		running--
+		mu.Unlock()
+	})
+	assert.LessOrEqual(t, peak, 3)
+	for _, s := range seen {
+		assert.True(t, s)
+	}
+}
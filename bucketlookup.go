@@ -0,0 +1,38 @@
+package minio
+
+import (
+	"github.com/minio/minio-go/v7"
+	"github.com/pkg/errors"
+)
+
+const (
+	// BucketLookupKey selects how the bucket is addressed: auto, dns for
+	// virtual-host style or path for path style.
+	BucketLookupKey = "bucketLookup"
+	// PathStyleKey set to true is a shorthand for bucketLookup path.
+	PathStyleKey = "pathStyle"
+
+	BucketLookupAuto = "auto"
+	BucketLookupDNS  = "dns"
+	BucketLookupPath = "path"
+)
+
+func parseBucketLookup(p map[string]string) (minio.BucketLookupType, error) {
+	lookup := p[BucketLookupKey]
+	if propertyToBool(p, PathStyleKey) {
+		if lookup != "" && lookup != BucketLookupPath {
+			return minio.BucketLookupAuto, errors.Errorf("Minio %s conflicts with %s %s", PathStyleKey, BucketLookupKey, lookup)
+		}
+		return minio.BucketLookupPath, nil
+	}
+	switch lookup {
+	case "", BucketLookupAuto:
+		return minio.BucketLookupAuto, nil
+	case BucketLookupDNS:
+		return minio.BucketLookupDNS, nil
+	case BucketLookupPath:
+		return minio.BucketLookupPath, nil
+	default:
+		return minio.BucketLookupAuto, errors.Errorf("unsupported Minio bucketLookup %s", lookup)
+	}
+}
@@ -0,0 +1,36 @@
+package minio
+
+import (
+	"github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseBucketLookup(t *testing.T) {
+	for value, expected := range map[string]minio.BucketLookupType{
+		"":     minio.BucketLookupAuto,
+		"auto": minio.BucketLookupAuto,
+		"dns":  minio.BucketLookupDNS,
+		"path": minio.BucketLookupPath,
+	} {
+		lookup, err := parseBucketLookup(map[string]string{"bucketLookup": value})
+		assert.Nil(t, err)
+		assert.Equal(t, expected, lookup, value)
+	}
+
+	_, err := parseBucketLookup(map[string]string{"bucketLookup": "virtual"})
+	assert.Error(t, err)
+}
+
+func TestParsePathStyle(t *testing.T) {
+	lookup, err := parseBucketLookup(map[string]string{"pathStyle": "true"})
+	assert.Nil(t, err)
+	assert.Equal(t, minio.BucketLookupPath, lookup)
+
+	lookup, err = parseBucketLookup(map[string]string{"pathStyle": "false", "bucketLookup": "dns"})
+	assert.Nil(t, err)
+	assert.Equal(t, minio.BucketLookupDNS, lookup)
+
+	_, err = parseBucketLookup(map[string]string{"pathStyle": "true", "bucketLookup": "dns"})
+	assert.EqualError(t, err, "Minio pathStyle conflicts with bucketLookup dns")
+}
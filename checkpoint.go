@@ -0,0 +1,197 @@
+package minio
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/notification"
+	"sort"
+	"time"
+)
+
+const (
+	CheckpointKey         = "checkpoint"
+	CheckpointObjectKey   = "checkpointKey"
+	CheckpointIntervalKey = "checkpointInterval"
+
+	defaultCheckpointPrefix   = ".dapr/checkpoints/"
+	defaultCheckpointInterval = 5 * time.Second
+	checkpointTimeout         = 10 * time.Second
+)
+
+// Checkpoint records the last event the app handled successfully.
+type Checkpoint struct {
+	EventTime time.Time `json:"eventTime"`
+	EventName string    `json:"eventName"`
+	Key       string    `json:"key"`
+}
+
+// CheckpointStore persists the input binding's progress across restarts.
+// Load returns nil when nothing has been saved yet.
+type CheckpointStore interface {
+	Load() (*Checkpoint, error)
+	Save(checkpoint Checkpoint) error
+}
+
+// SetCheckpointStore replaces the default object-backed checkpoint store. It
+// is kept across re-Inits.
+func (m *Minio) SetCheckpointStore(store CheckpointStore) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.checkpoints = store
+}
+
+// objectCheckpointStore keeps the checkpoint as a JSON object in the bucket.
+// Init builds a new one each time, for the client and key it configures. Its
+// requests don't use the binding's context, so the checkpoint is still
+// written when Close stops a Read.
+type objectCheckpointStore struct {
+	client objectClient
+	bucket string
+	key    string
+}
+
+// newObjectCheckpointStore returns the store configured by the checkpoint
+// properties, or nil when checkpointing is off.
+func newObjectCheckpointStore(p map[string]string, name string, client objectClient, bucket string) CheckpointStore {
+	if !propertyToBool(p, CheckpointKey) && p[CheckpointObjectKey] == "" {
+		return nil
+	}
+	key := p[CheckpointObjectKey]
+	if key == "" && name != "" {
+		key = defaultCheckpointPrefix + name
+	} else if key == "" {
+		key = defaultCheckpointPrefix + "minio"
+	}
+	return &objectCheckpointStore{client: client, bucket: bucket, key: key}
+}
+
+func (s *objectCheckpointStore) Load() (*Checkpoint, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), checkpointTimeout)
+	defer cancel()
+	object, err := s.client.GetObject(ctx, s.bucket, s.key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer object.Close()
+
+	var checkpoint Checkpoint
+	if err := json.NewDecoder(object).Decode(&checkpoint); err != nil {
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &checkpoint, nil
+}
+
+func (s *objectCheckpointStore) Save(checkpoint Checkpoint) error {
+	b, err := json.Marshal(checkpoint)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), checkpointTimeout)
+	defer cancel()
+	_, err = s.client.PutObject(ctx, s.bucket, s.key, bytes.NewReader(b), int64(len(b)), minio.PutObjectOptions{
+		ContentType: "application/json",
+	})
+	return err
+}
+
+func (r *reader) checkpointObjectKey() string {
+	if s, ok := r.checkpoints.(*objectCheckpointStore); ok {
+		return s.key
+	}
+	return ""
+}
+
+// saveCheckpoint moves the checkpoint past event. It is written at most once
+// per checkpointInterval; flushCheckpoint writes the rest.
+func (r *reader) saveCheckpoint(event notification.Event) {
+	if r.checkpoints == nil || r.checkpointHeld {
+		return
+	}
+	r.pendingCheckpoint = &Checkpoint{
+		EventTime: eventTime(event),
+		EventName: event.EventName,
+		Key:       eventKey(event),
+	}
+	if time.Since(r.checkpointSaved) >= r.input.checkpointInterval {
+		r.flushCheckpoint()
+	}
+}
+
+// flushCheckpoint writes the checkpoint if it moved since the last write.
+func (r *reader) flushCheckpoint() {
+	if r.pendingCheckpoint == nil {
+		return
+	}
+	if err := r.checkpoints.Save(*r.pendingCheckpoint); err != nil {
+		r.logger.Warnf("Minio binding failed to save checkpoint: %s", err)
+		return
+	}
+	r.pendingCheckpoint = nil
+	r.checkpointSaved = time.Now()
+}
+
+// holdCheckpoint stops the checkpoint from advancing past an event that was
+// neither delivered nor dead-lettered, so that a restart replays it. Events
+// after it are still delivered, and replayed again after a restart. The hold
+// lasts until Read is called again.
+func (r *reader) holdCheckpoint(event notification.Event) {
+	if r.checkpoints == nil || r.checkpointHeld {
+		return
+	}
+	r.checkpointHeld = true
+	r.logger.Warnf("Minio binding checkpoint held before %s until restart, set %s to move failed events aside instead", eventKey(event), DeadLetterPrefixKey)
+}
+
+// catchUp replays objects written after the last checkpoint as created
+// events, oldest first. Deletions during the downtime cannot be recovered.
+// It lists what polling lists and returns the listing, so polling can start
+// from it without missing objects written in between.
+func (r *reader) catchUp(dispatch func(event notification.Event)) (objectSnapshot, error) {
+	if r.checkpoints == nil {
+		return nil, nil
+	}
+	checkpoint, err := r.checkpoints.Load()
+	if err != nil {
+		return nil, fmt.Errorf("minio binding error. load checkpoint: %w", err)
+	}
+	if checkpoint == nil {
+		return nil, nil
+	}
+
+	snapshot, err := r.snapshot()
+	if err != nil {
+		return nil, err
+	}
+	var missed []minio.ObjectInfo
+	for _, object := range snapshot {
+		if object.LastModified.After(checkpoint.EventTime) {
+			missed = append(missed, object)
+		}
+	}
+	sort.Slice(missed, func(i, j int) bool {
+		return missed[i].LastModified.Before(missed[j].LastModified)
+	})
+
+	r.logger.Infof("Minio binding resuming from checkpoint %s, replaying %d objects", checkpoint.EventTime.Format(time.RFC3339), len(missed))
+	for _, object := range missed {
+		event := newEvent(objectCreatedEvent, r.bucket, object)
+		event.EventTime = object.LastModified.UTC().Format(time.RFC3339Nano)
+		dispatch(event)
+	}
+	r.flushCheckpoint()
+	return snapshot, nil
+}
+
+func eventTime(event notification.Event) time.Time {
+	t, err := time.Parse(time.RFC3339Nano, event.EventTime)
+	if err != nil {
+		return time.Now().UTC()
+	}
+	return t
+}
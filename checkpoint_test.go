@@ -0,0 +1,131 @@
+package minio
+
+import (
+	"github.com/dapr/components-contrib/bindings"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/notification"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+type memoryCheckpointStore struct {
+	saved []Checkpoint
+}
+
+func (s *memoryCheckpointStore) Load() (*Checkpoint, error) {
+	if len(s.saved) == 0 {
+		return nil, nil
+	}
+	return &s.saved[len(s.saved)-1], nil
+}
+
+func (s *memoryCheckpointStore) Save(checkpoint Checkpoint) error {
+	s.saved = append(s.saved, checkpoint)
+	return nil
+}
+
+func TestSaveCheckpoint(t *testing.T) {
+	m, _ := newFakeMinio()
+	store := &memoryCheckpointStore{}
+	m.SetCheckpointStore(store)
+	r, err := m.newReader()
+	require.NoError(t, err)
+
+	var event notification.Event
+	event.EventName = "s3:ObjectCreated:Put"
+	event.EventTime = "2021-10-01T10:00:00.000Z"
+	event.S3.Object.Key = "a%2Fb.txt"
+	r.saveCheckpoint(event)
+
+	checkpoint, err := store.Load()
+	assert.NoError(t, err)
+	assert.Equal(t, "a/b.txt", checkpoint.Key)
+	assert.Equal(t, time.Date(2021, 10, 1, 10, 0, 0, 0, time.UTC), checkpoint.EventTime)
+	assert.Equal(t, "", r.checkpointObjectKey())
+}
+
+func TestCheckpointHeldOnFailedDelivery(t *testing.T) {
+	m, _ := newFakeMinio()
+	m.input = inputConfig{events: defaultNotificationEvents}
+	store := &memoryCheckpointStore{}
+	m.SetCheckpointStore(store)
+	r, err := m.newReader()
+	require.NoError(t, err)
+
+	handler := func(resp *bindings.ReadResponse) ([]byte, error) {
+		if resp.Metadata["key"] == "b.txt" {
+			return nil, errors.Errorf("handler failed")
+		}
+		return nil, nil
+	}
+	for _, key := range []string{"a.txt", "b.txt", "c.txt"} {
+		r.dispatch(handler, newEvent(objectCreatedEvent, "b", minio.ObjectInfo{Key: key}))
+	}
+	// without a dead letter, the checkpoint stays before the failed event
+	assert.Len(t, store.saved, 1)
+	assert.Equal(t, "a.txt", store.saved[0].Key)
+}
+
+func TestCatchUpListsPollPrefix(t *testing.T) {
+	m, fake := newFakeMinio()
+	m.input = inputConfig{events: defaultNotificationEvents, pollPrefix: "in/"}
+	m.SetCheckpointStore(&memoryCheckpointStore{saved: []Checkpoint{{EventTime: time.Now().Add(-time.Hour)}}})
+	fake.put("b", "in/a.txt", []byte("a"), minio.ObjectInfo{})
+	fake.put("b", "out/b.txt", []byte("b"), minio.ObjectInfo{})
+	r, err := m.newReader()
+	require.NoError(t, err)
+
+	var replayed []string
+	snapshot, err := r.catchUp(func(event notification.Event) {
+		replayed = append(replayed, eventKey(event))
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"in/a.txt"}, replayed)
+	// polling starts from the catch-up listing
+	assert.Contains(t, snapshot, "in/a.txt")
+	assert.Len(t, snapshot, 1)
+}
+
+func TestCheckpointWritesThrottled(t *testing.T) {
+	m, _ := newFakeMinio()
+	m.input = inputConfig{events: defaultNotificationEvents, checkpointInterval: time.Hour}
+	store := &memoryCheckpointStore{}
+	m.SetCheckpointStore(store)
+	r, err := m.newReader()
+	require.NoError(t, err)
+
+	handler := func(*bindings.ReadResponse) ([]byte, error) { return nil, nil }
+	for _, key := range []string{"a.txt", "b.txt", "c.txt"} {
+		r.dispatch(handler, newEvent(objectCreatedEvent, "b", minio.ObjectInfo{Key: key}))
+	}
+	assert.Len(t, store.saved, 1)
+
+	r.flushCheckpoint()
+	assert.Len(t, store.saved, 2)
+	assert.Equal(t, "c.txt", store.saved[1].Key)
+	r.flushCheckpoint()
+	assert.Len(t, store.saved, 2)
+}
+
+func TestInitRebuildsCheckpointStore(t *testing.T) {
+	s := newS3Server(t)
+	m := newS3Minio(t, s, map[string]string{CheckpointObjectKey: "checkpoints/a"})
+	assert.Equal(t, "checkpoints/a", m.checkpoints.(*objectCheckpointStore).key)
+
+	require.NoError(t, m.Init(bindings.Metadata{Properties: s.properties(map[string]string{CheckpointObjectKey: "checkpoints/b"})}))
+	store := m.checkpoints.(*objectCheckpointStore)
+	assert.Equal(t, "checkpoints/b", store.key)
+	assert.Equal(t, m.minioClient, store.client)
+
+	require.NoError(t, m.Init(bindings.Metadata{Properties: s.properties(nil)}))
+	assert.Nil(t, m.checkpoints)
+
+	// a store of the caller's is kept
+	custom := &memoryCheckpointStore{}
+	m.SetCheckpointStore(custom)
+	require.NoError(t, m.Init(bindings.Metadata{Properties: s.properties(map[string]string{CheckpointKey: "true"})}))
+	assert.Equal(t, custom, m.checkpoints)
+}
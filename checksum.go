@@ -0,0 +1,86 @@
+package minio
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/dapr/components-contrib/bindings"
+	"github.com/minio/minio-go/v7"
+	"github.com/pkg/errors"
+	"strconv"
+)
+
+// ChecksumOperation returns the checksums stored with an object, so a local
+// copy can be verified without downloading it again.
+const ChecksumOperation bindings.OperationKind = "checksum"
+
+// checksumResponse holds the base64 checksums S3 stored with the object, only
+// the algorithms it was uploaded with. A checksum of a multipart upload covers
+// the checksums of its parts and ends in -<parts>.
+type checksumResponse struct {
+	Key       string `json:"key"`
+	VersionID string `json:"versionID,omitempty"`
+	ETag      string `json:"etag"`
+	Size      int64  `json:"size"`
+	CRC32     string `json:"crc32,omitempty"`
+	CRC32C    string `json:"crc32c,omitempty"`
+	SHA1      string `json:"sha1,omitempty"`
+	SHA256    string `json:"sha256,omitempty"`
+}
+
+func (c checksumResponse) stored() bool {
+	return c.CRC32 != "" || c.CRC32C != "" || c.SHA1 != "" || c.SHA256 != ""
+}
+
+func (m *Minio) checksum(ctx context.Context, req *bindings.InvokeRequest) (*bindings.InvokeResponse, error) {
+	p := req.Metadata
+
+	objectName := objectNameOf(p)
+	if objectName == "" {
+		return nil, errors.Errorf("missing name field")
+	}
+	client, err := m.clientFor(p)
+	if err != nil {
+		return nil, err
+	}
+	sse, err := readEncryption(m.properties, p)
+	if err != nil {
+		return nil, err
+	}
+
+	var info minio.ObjectInfo
+	err = m.withRetry(ctx, func() error {
+		info, err = client.StatObject(ctx, m.Bucket, objectName, minio.StatObjectOptions{
+			VersionID:            p["versionID"],
+			ServerSideEncryption: sse,
+			Checksum:             true,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("minio binding error. stat: %w", err)
+	}
+
+	resp := checksumResponse{
+		Key:       objectName,
+		VersionID: info.VersionID,
+		ETag:      info.ETag,
+		Size:      info.Size,
+		CRC32:     info.ChecksumCRC32,
+		CRC32C:    info.ChecksumCRC32C,
+		SHA1:      info.ChecksumSHA1,
+		SHA256:    info.ChecksumSHA256,
+	}
+	jsonResponse, err := json.Marshal(resp)
+	if err != nil {
+		return nil, err
+	}
+	return &bindings.InvokeResponse{
+		Data: jsonResponse,
+		Metadata: map[string]string{
+			"key":    objectName,
+			"etag":   resp.ETag,
+			"stored": strconv.FormatBool(resp.stored()),
+		},
+	}, nil
+}
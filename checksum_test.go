@@ -0,0 +1,29 @@
+package minio
+
+import (
+	"github.com/dapr/components-contrib/bindings"
+	"github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestChecksum(t *testing.T) {
+	m, fake := newFakeMinio()
+	fake.put("b", "a.txt", []byte("hello"), minio.ObjectInfo{ChecksumSHA256: "LPJNul+wow4m6DsqxbninhsWHlwfp0JecwQzYpOLmCQ="})
+	fake.put("b", "b.txt", []byte("hello"), minio.ObjectInfo{})
+
+	resp, err := m.Invoke(&bindings.InvokeRequest{Operation: ChecksumOperation, Metadata: map[string]string{"key": "a.txt"}})
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"key":"a.txt","etag":"5d41402abc4b2a76b9719d911017c592","size":5,"sha256":"LPJNul+wow4m6DsqxbninhsWHlwfp0JecwQzYpOLmCQ="}`, string(resp.Data))
+	assert.Equal(t, "true", resp.Metadata["stored"])
+
+	// without stored checksums, the ETag is all there is to compare with
+	resp, err = m.Invoke(&bindings.InvokeRequest{Operation: ChecksumOperation, Metadata: map[string]string{"key": "b.txt"}})
+	assert.Nil(t, err)
+	assert.Equal(t, "false", resp.Metadata["stored"])
+
+	_, err = m.Invoke(&bindings.InvokeRequest{Operation: ChecksumOperation, Metadata: map[string]string{"key": "missing"}})
+	assert.Equal(t, ErrCodeNotFound, ErrorCode(err))
+	_, err = m.Invoke(&bindings.InvokeRequest{Operation: ChecksumOperation})
+	assert.Error(t, err)
+}
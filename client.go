@@ -0,0 +1,69 @@
+package minio
+
+import (
+	"context"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/notification"
+	"github.com/minio/minio-go/v7/pkg/sse"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// objectClient is the subset of the MinIO API the binding uses, so tests can
+// run it against a fake instead of a live server.
+type objectClient interface {
+	BucketExists(ctx context.Context, bucketName string) (bool, error)
+	MakeBucket(ctx context.Context, bucketName string, opts minio.MakeBucketOptions) error
+	GetBucketEncryption(ctx context.Context, bucketName string) (*sse.Configuration, error)
+	GetBucketVersioning(ctx context.Context, bucketName string) (minio.BucketVersioningConfiguration, error)
+
+	PutObject(ctx context.Context, bucketName, objectName string, reader io.Reader, objectSize int64, opts minio.PutObjectOptions) (minio.UploadInfo, error)
+	GetObject(ctx context.Context, bucketName, objectName string, opts minio.GetObjectOptions) (objectReader, error)
+	StatObject(ctx context.Context, bucketName, objectName string, opts minio.StatObjectOptions) (minio.ObjectInfo, error)
+	RemoveObject(ctx context.Context, bucketName, objectName string, opts minio.RemoveObjectOptions) error
+	RemoveObjectWithResult(ctx context.Context, bucketName, objectName string, opts minio.RemoveObjectOptions) (minio.RemoveObjectResult, error)
+	CopyObject(ctx context.Context, dst minio.CopyDestOptions, src minio.CopySrcOptions) (minio.UploadInfo, error)
+	ListObjects(ctx context.Context, bucketName string, opts minio.ListObjectsOptions) <-chan minio.ObjectInfo
+	ListenBucketNotification(ctx context.Context, bucketName, prefix, suffix string, events []string) <-chan notification.Info
+
+	PresignedGetObject(ctx context.Context, bucketName, objectName string, expires time.Duration, reqParams url.Values) (*url.URL, error)
+	PresignedPutObject(ctx context.Context, bucketName, objectName string, expires time.Duration) (*url.URL, error)
+	PresignHeader(ctx context.Context, method, bucketName, objectName string, expires time.Duration, reqParams url.Values, extraHeaders http.Header) (*url.URL, error)
+	PresignedPostPolicy(ctx context.Context, policy *minio.PostPolicy) (*url.URL, map[string]string, error)
+}
+
+// objectReader is the part of *minio.Object the binding reads objects through.
+type objectReader interface {
+	io.ReadCloser
+	Stat() (minio.ObjectInfo, error)
+}
+
+// clientAdapter adapts *minio.Client to objectClient. GetObject differs
+// because *minio.Object can't be constructed outside minio-go, and
+// RemoveObjectWithResult has no single-object counterpart in minio-go.
+type clientAdapter struct {
+	*minio.Client
+}
+
+func (c clientAdapter) GetObject(ctx context.Context, bucketName, objectName string, opts minio.GetObjectOptions) (objectReader, error) {
+	object, err := c.Client.GetObject(ctx, bucketName, objectName, opts)
+	if err != nil {
+		return nil, err
+	}
+	return object, nil
+}
+
+// RemoveObjectWithResult deletes one object through the multi-object delete
+// API, the only one minio-go reports the resulting delete marker of.
+func (c clientAdapter) RemoveObjectWithResult(ctx context.Context, bucketName, objectName string, opts minio.RemoveObjectOptions) (minio.RemoveObjectResult, error) {
+	objects := make(chan minio.ObjectInfo, 1)
+	objects <- minio.ObjectInfo{Key: objectName, VersionID: opts.VersionID}
+	close(objects)
+	result := minio.RemoveObjectResult{ObjectName: objectName, ObjectVersionID: opts.VersionID}
+	for r := range c.Client.RemoveObjectsWithResult(ctx, bucketName, objects, minio.RemoveObjectsOptions{GovernanceBypass: opts.GovernanceBypass}) {
+		result = r
+	}
+	return result, result.Err
+}
@@ -0,0 +1,117 @@
+package minio
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/dapr/components-contrib/bindings"
+	"github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestFakeRoundTrip(t *testing.T) {
+	m, fake := newFakeMinio()
+	fake.versioned = true
+
+	resp, err := m.Invoke(&bindings.InvokeRequest{
+		Operation: bindings.CreateOperation,
+		Data:      []byte("test content"),
+		Metadata:  map[string]string{"objectName": "docs/a.txt"},
+	})
+	assert.Nil(t, err)
+	created := createResponse{}
+	assert.Nil(t, json.Unmarshal(resp.Data, &created))
+	assert.Equal(t, "docs/a.txt", created.Key)
+	assert.Equal(t, "v1", resp.Metadata["versionID"])
+
+	resp, err = m.Invoke(&bindings.InvokeRequest{Operation: bindings.GetOperation, Metadata: map[string]string{"objectName": "docs/a.txt"}})
+	assert.Nil(t, err)
+	assert.Equal(t, "test content", string(resp.Data))
+	assert.Equal(t, "12", resp.Metadata["size"])
+
+	resp, err = m.Invoke(&bindings.InvokeRequest{Operation: bindings.ListOperation})
+	assert.Nil(t, err)
+	assert.JSONEq(t, `[{"size":"12","versionID":"v1","key":"docs/a.txt"}]`, string(resp.Data))
+
+	resp, err = m.Invoke(&bindings.InvokeRequest{Operation: bindings.DeleteOperation, Metadata: map[string]string{"objectName": "docs/a.txt"}})
+	assert.Nil(t, err)
+	deleted := deleteResponse{}
+	assert.Nil(t, json.Unmarshal(resp.Data, &deleted))
+	assert.Equal(t, deleteResponse{Key: "docs/a.txt", DeleteMarker: true, DeleteMarkerVersionID: "v2", Existed: true}, deleted)
+
+	resp, err = m.Invoke(&bindings.InvokeRequest{Operation: bindings.DeleteOperation, Metadata: map[string]string{"objectName": "docs/a.txt"}})
+	assert.Nil(t, err)
+	deleted = deleteResponse{}
+	assert.Nil(t, json.Unmarshal(resp.Data, &deleted))
+	assert.False(t, deleted.Existed)
+
+	resp, err = m.Invoke(&bindings.InvokeRequest{Operation: bindings.DeleteOperation, Metadata: map[string]string{"objectName": "docs/a.txt", IfNoneMatchKey: "*"}})
+	assert.Nil(t, err)
+	deleted = deleteResponse{}
+	assert.Nil(t, json.Unmarshal(resp.Data, &deleted))
+	assert.False(t, deleted.Existed)
+}
+
+func TestFakeGetNotFound(t *testing.T) {
+	m, _ := newFakeMinio()
+
+	_, err := m.Invoke(&bindings.InvokeRequest{Operation: bindings.GetOperation, Metadata: map[string]string{"objectName": "missing"}})
+	assert.Equal(t, ErrCodeNotFound, ErrorCode(err))
+
+	resp, err := m.Invoke(&bindings.InvokeRequest{Operation: bindings.GetOperation, Metadata: map[string]string{"key": "missing", MissingAsEmptyKey: "true"}})
+	assert.Nil(t, err)
+	assert.Nil(t, resp.Data)
+	assert.Equal(t, "false", resp.Metadata["exists"])
+}
+
+func TestFakeKeyAlias(t *testing.T) {
+	m, fake := newFakeMinio()
+
+	_, err := m.Invoke(&bindings.InvokeRequest{Operation: bindings.CreateOperation, Data: []byte("x"), Metadata: map[string]string{"key": "a"}})
+	assert.Nil(t, err)
+	_, ok := fake.buckets["b"]["a"]
+	assert.True(t, ok)
+
+	resp, err := m.Invoke(&bindings.InvokeRequest{Operation: bindings.GetOperation, Metadata: map[string]string{"key": "a"}})
+	assert.Nil(t, err)
+	assert.Equal(t, "x", string(resp.Data))
+}
+
+func TestFakeListPage(t *testing.T) {
+	m, fake := newFakeMinio()
+	for _, key := range []string{"a", "b", "c", "d", "e"} {
+		fake.put("b", "logs/"+key, []byte(key), minio.ObjectInfo{})
+	}
+	fake.put("b", "other", []byte("x"), minio.ObjectInfo{})
+
+	var keys []string
+	token := ""
+	for pages := 0; pages < 5; pages++ {
+		resp, err := m.Invoke(&bindings.InvokeRequest{Operation: bindings.ListOperation, Metadata: map[string]string{
+			ListFormatKey:        ListFormatNDJSON,
+			PrefixKey:            "logs/",
+			MaxResultsKey:        "2",
+			ContinuationTokenKey: token,
+		}})
+		assert.Nil(t, err)
+		dec := json.NewDecoder(bytes.NewReader(resp.Data))
+		for dec.More() {
+			var info fileInfoResponse
+			assert.Nil(t, dec.Decode(&info))
+			keys = append(keys, info.Key)
+		}
+		if token = resp.Metadata[ContinuationTokenKey]; token == "" {
+			break
+		}
+	}
+	assert.Equal(t, []string{"logs/a", "logs/b", "logs/c", "logs/d", "logs/e"}, keys)
+}
+
+func TestFakeGetContentType(t *testing.T) {
+	m, fake := newFakeMinio()
+	fake.put("b", "report.pdf", []byte("%PDF"), minio.ObjectInfo{ContentType: "application/pdf"})
+
+	resp, err := m.Invoke(&bindings.InvokeRequest{Operation: bindings.GetOperation, Metadata: map[string]string{"key": "report.pdf"}})
+	assert.Nil(t, err)
+	assert.Equal(t, "application/pdf", resp.Metadata["contentType"])
+}
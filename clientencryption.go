@@ -0,0 +1,82 @@
+package minio
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"github.com/minio/minio-go/v7"
+	"github.com/pkg/errors"
+	"io"
+	"io/ioutil"
+)
+
+const (
+	ClientEncryptionKeyKey = "clientEncryptionKey"
+
+	// user metadata marking objects sealed by the binding
+	clientEncryptionMetadataKey = "Client-Encryption"
+	clientEncryptionAESGCM      = "aes-gcm"
+)
+
+// newClientCipher builds the AES-GCM cipher from the base64 256-bit
+// clientEncryptionKey property. A secretKeyRef for it in the component is
+// resolved by the Dapr runtime before Init. It returns nil when client-side
+// encryption is not configured.
+func newClientCipher(p map[string]string) (cipher.AEAD, error) {
+	key := p[ClientEncryptionKeyKey]
+	if key == "" {
+		return nil, nil
+	}
+	b, err := base64.StdEncoding.DecodeString(key)
+	if err != nil || len(b) != 32 {
+		return nil, errors.Errorf("Minio clientEncryptionKey must be a base64 encoded 256-bit key")
+	}
+	block, err := aes.NewCipher(b)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// encryptUpload seals the payload as nonce || ciphertext before it leaves the
+// process. GCM authenticates the whole message, so the payload is buffered.
+func encryptUpload(aead cipher.AEAD, r io.Reader, userMetadata map[string]string) (io.Reader, int64, map[string]string, error) {
+	plaintext, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, 0, nil, fmt.Errorf("minio binding error. encrypt: %w", err)
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, 0, nil, fmt.Errorf("minio binding error. encrypt: %w", err)
+	}
+	sealed := aead.Seal(nonce, nonce, plaintext, nil)
+
+	metadata := make(map[string]string, len(userMetadata)+1)
+	for k, v := range userMetadata {
+		metadata[k] = v
+	}
+	metadata[clientEncryptionMetadataKey] = clientEncryptionAESGCM
+	return bytes.NewReader(sealed), int64(len(sealed)), metadata, nil
+}
+
+func isClientEncrypted(info minio.ObjectInfo) bool {
+	return info.UserMetadata[clientEncryptionMetadataKey] == clientEncryptionAESGCM
+}
+
+func decryptObject(aead cipher.AEAD, sealed []byte) ([]byte, error) {
+	if aead == nil {
+		return nil, errors.Errorf("minio binding error. object is client-side encrypted but no clientEncryptionKey is configured")
+	}
+	if len(sealed) < aead.NonceSize() {
+		return nil, errors.Errorf("minio binding error. decrypt: object is too short")
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("minio binding error. decrypt: %w", err)
+	}
+	return plaintext, nil
+}
@@ -0,0 +1,43 @@
+package minio
+
+import (
+	"bytes"
+	"encoding/base64"
+	"github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"testing"
+)
+
+func TestClientEncryption(t *testing.T) {
+	key := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, 32))
+	aead, err := newClientCipher(map[string]string{ClientEncryptionKeyKey: key})
+	assert.Nil(t, err)
+
+	r, size, metadata, err := encryptUpload(aead, bytes.NewReader([]byte("secret payload")), nil)
+	assert.Nil(t, err)
+	sealed, _ := ioutil.ReadAll(r)
+	assert.Equal(t, int64(len(sealed)), size)
+	assert.NotContains(t, string(sealed), "secret payload")
+	assert.True(t, isClientEncrypted(minio.ObjectInfo{UserMetadata: metadata}))
+
+	plaintext, err := decryptObject(aead, sealed)
+	assert.Nil(t, err)
+	assert.Equal(t, "secret payload", string(plaintext))
+
+	sealed[len(sealed)-1] ^= 1
+	_, err = decryptObject(aead, sealed)
+	assert.Error(t, err)
+
+	_, err = decryptObject(nil, sealed)
+	assert.Error(t, err)
+}
+
+func TestNewClientCipher(t *testing.T) {
+	aead, err := newClientCipher(map[string]string{})
+	assert.Nil(t, err)
+	assert.Nil(t, aead)
+
+	_, err = newClientCipher(map[string]string{ClientEncryptionKeyKey: base64.StdEncoding.EncodeToString([]byte("short"))})
+	assert.EqualError(t, err, "Minio clientEncryptionKey must be a base64 encoded 256-bit key")
+}
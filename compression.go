@@ -0,0 +1,102 @@
+package minio
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"github.com/dapr/components-contrib/bindings"
+	"github.com/minio/minio-go/v7"
+	"github.com/pkg/errors"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+)
+
+const (
+	CompressKey   = "compress"
+	DecompressKey = "decompress"
+
+	CompressionGzip = "gzip"
+
+	// user metadata recording how the binding compressed an object
+	compressionMetadataKey  = "Compression"
+	originalSizeMetadataKey = "Original-Size"
+)
+
+// compressUpload wraps r in a gzip stream when the request asks for it and
+// records the encoding and, when known, the original size in user metadata.
+// The compressed size is unknown, so the returned size is -1. The gzip stream
+// is torn down once ctx is done.
+func compressUpload(ctx context.Context, p map[string]string, r io.Reader, size int64, userMetadata map[string]string) (io.Reader, int64, map[string]string, error) {
+	switch compression := p[CompressKey]; compression {
+	case "":
+		return r, size, userMetadata, nil
+	case CompressionGzip:
+	default:
+		return nil, 0, nil, errors.Errorf("unsupported Minio compress %s", compression)
+	}
+
+	metadata := make(map[string]string, len(userMetadata)+2)
+	for k, v := range userMetadata {
+		metadata[k] = v
+	}
+	metadata[compressionMetadataKey] = CompressionGzip
+	if size >= 0 {
+		metadata[originalSizeMetadataKey] = strconv.FormatInt(size, 10)
+	}
+	return gzipReader(ctx, r), -1, metadata, nil
+}
+
+func gzipReader(ctx context.Context, r io.Reader) io.Reader {
+	pr, pw := io.Pipe()
+	go func() {
+		<-ctx.Done()
+		pr.CloseWithError(ctx.Err())
+	}()
+	go func() {
+		gz := gzip.NewWriter(pw)
+		_, err := io.Copy(gz, r)
+		if closeErr := gz.Close(); err == nil {
+			err = closeErr
+		}
+		pw.CloseWithError(err)
+	}()
+	return pr
+}
+
+// isGzipped recognizes both objects stored with Content-Encoding: gzip and
+// objects compressed by the binding's compress option.
+func isGzipped(info minio.ObjectInfo) bool {
+	if strings.EqualFold(info.Metadata.Get("Content-Encoding"), CompressionGzip) {
+		return true
+	}
+	return strings.EqualFold(info.UserMetadata[compressionMetadataKey], CompressionGzip)
+}
+
+// gunzip decompresses data, refusing output above maxSize when it is set.
+func gunzip(data []byte, maxSize int64) ([]byte, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("minio binding error. decompress: %w", err)
+	}
+	defer gz.Close()
+
+	var r io.Reader = gz
+	if maxSize > 0 {
+		r = io.LimitReader(gz, maxSize+1)
+	}
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("minio binding error. decompress: %w", err)
+	}
+	if maxSize > 0 && int64(len(b)) > maxSize {
+		return nil, &Error{
+			Code:      ErrCodeTooLarge,
+			Operation: bindings.GetOperation,
+			Err:       errors.Errorf("decompressed object is larger than maxGetSize %d", maxSize),
+		}
+	}
+	return b, nil
+}
@@ -0,0 +1,58 @@
+package minio
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+func TestCompressUpload(t *testing.T) {
+	content := bytes.Repeat([]byte("text heavy payload "), 1000)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	r, size, metadata, err := compressUpload(ctx, map[string]string{CompressKey: CompressionGzip}, bytes.NewReader(content), int64(len(content)), map[string]string{"Owner": "a"})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(-1), size)
+	assert.Equal(t, map[string]string{"Owner": "a", "Compression": "gzip", "Original-Size": "19000"}, metadata)
+
+	gz, err := gzip.NewReader(r)
+	assert.Nil(t, err)
+	decompressed, err := ioutil.ReadAll(gz)
+	assert.Nil(t, err)
+	assert.Equal(t, content, decompressed)
+
+	_, size, _, err = compressUpload(ctx, nil, bytes.NewReader(content), 5, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(5), size)
+
+	_, _, _, err = compressUpload(ctx, map[string]string{CompressKey: "zstd"}, nil, 0, nil)
+	assert.EqualError(t, err, "unsupported Minio compress zstd")
+}
+
+func TestGunzip(t *testing.T) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	gz.Write([]byte("original bytes"))
+	gz.Close()
+
+	assert.True(t, isGzipped(minio.ObjectInfo{UserMetadata: minio.StringMap{"Compression": "gzip"}}))
+	assert.True(t, isGzipped(minio.ObjectInfo{Metadata: http.Header{"Content-Encoding": []string{"gzip"}}}))
+	assert.False(t, isGzipped(minio.ObjectInfo{}))
+
+	b, err := gunzip(buf.Bytes(), 0)
+	assert.Nil(t, err)
+	assert.Equal(t, "original bytes", string(b))
+
+	_, err = gunzip(buf.Bytes(), 4)
+	assert.Equal(t, ErrCodeTooLarge, ErrorCode(err))
+
+	_, err = gunzip([]byte("plain"), 0)
+	assert.Error(t, err)
+}
@@ -0,0 +1,253 @@
+// Package configstore implements Dapr's configuration API over a bucket
+// prefix: each key is an object, its content is the value and its ETag the
+// version. Subscriptions are fed by bucket notifications. The component
+// accepts the same connection metadata as the MinIO binding.
+package configstore
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/dapr/components-contrib/bindings"
+	"github.com/dapr/kit/logger"
+	"github.com/putao520/minio-dapr"
+	"strings"
+)
+
+const KeyPrefixKey = "keyPrefix"
+
+// The configuration API isn't part of the components-contrib release this
+// module builds against, so its types are declared here with the same shape.
+
+type Metadata struct {
+	Properties map[string]string `json:"properties"`
+}
+
+type Item struct {
+	Key      string            `json:"key"`
+	Value    string            `json:"value,omitempty"`
+	Version  string            `json:"version,omitempty"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+type GetRequest struct {
+	Keys     []string          `json:"keys"`
+	Metadata map[string]string `json:"metadata"`
+}
+
+type GetResponse struct {
+	Items []*Item `json:"items"`
+}
+
+type SubscribeRequest struct {
+	Keys     []string          `json:"keys"`
+	Metadata map[string]string `json:"metadata"`
+}
+
+type UpdateEvent struct {
+	Items []*Item `json:"items"`
+}
+
+type UpdateHandler func(ctx context.Context, e *UpdateEvent) error
+
+type Store interface {
+	Init(metadata Metadata) error
+	Get(ctx context.Context, req *GetRequest) (*GetResponse, error)
+	Subscribe(ctx context.Context, req *SubscribeRequest, handler UpdateHandler) error
+}
+
+type ConfigurationStore struct {
+	binding    *minio.Minio
+	logger     logger.Logger
+	properties map[string]string
+	prefix     string
+}
+
+var _ = Store(&ConfigurationStore{})
+
+func NewMinioConfigurationStore(logger logger.Logger) *ConfigurationStore {
+	return &ConfigurationStore{
+		binding: minio.NewMinio(logger),
+		logger:  logger,
+	}
+}
+
+func (s *ConfigurationStore) Init(metadata Metadata) error {
+	p := make(map[string]string, len(metadata.Properties))
+	for k, v := range metadata.Properties {
+		p[k] = v
+	}
+	s.prefix = p[KeyPrefixKey]
+	delete(p, KeyPrefixKey)
+	s.properties = p
+	return s.binding.Init(bindings.Metadata{Properties: p})
+}
+
+func (s *ConfigurationStore) Close() error {
+	return s.binding.Close()
+}
+
+// Get returns the requested keys, or every key under the prefix when none
+// are given. Keys without an object are left out of the response.
+func (s *ConfigurationStore) Get(ctx context.Context, req *GetRequest) (*GetResponse, error) {
+	keys := req.Keys
+	if len(keys) == 0 {
+		var err error
+		if keys, err = s.listKeys(ctx); err != nil {
+			return nil, err
+		}
+		if len(keys) == 0 {
+			return &GetResponse{}, nil
+		}
+	}
+
+	names := make([]string, len(keys))
+	for i, key := range keys {
+		names[i] = s.prefix + key
+	}
+	data, err := json.Marshal(names)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.binding.InvokeWithContext(ctx, &bindings.InvokeRequest{
+		Operation: minio.GetBatchOperation,
+		Data:      data,
+		Metadata:  map[string]string{minio.MissingAsEmptyKey: "true"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	items, err := itemsFromBatch(keys, s.prefix, resp.Data)
+	if err != nil {
+		return nil, err
+	}
+	return &GetResponse{Items: items}, nil
+}
+
+type batchGetResult struct {
+	Data     []byte            `json:"data"`
+	Metadata map[string]string `json:"metadata"`
+	Error    string            `json:"error"`
+}
+
+// itemsFromBatch converts a getBatch result into items in key order. Any
+// failed key fails the whole Get, so apps never see a partial configuration.
+func itemsFromBatch(keys []string, prefix string, data []byte) ([]*Item, error) {
+	var results map[string]batchGetResult
+	if err := json.Unmarshal(data, &results); err != nil {
+		return nil, fmt.Errorf("minio configuration store error. get response: %w", err)
+	}
+	items := make([]*Item, 0, len(keys))
+	for _, key := range keys {
+		result := results[prefix+key]
+		if result.Error != "" {
+			return nil, fmt.Errorf("minio configuration store error. get %s: %s", key, result.Error)
+		}
+		if result.Metadata["exists"] == "false" {
+			continue
+		}
+		items = append(items, &Item{Key: key, Value: string(result.Data), Version: result.Metadata["etag"]})
+	}
+	return items, nil
+}
+
+func (s *ConfigurationStore) listKeys(ctx context.Context) ([]string, error) {
+	var keys []string
+	token := ""
+	for {
+		resp, err := s.binding.InvokeWithContext(ctx, &bindings.InvokeRequest{
+			Operation: bindings.ListOperation,
+			Metadata: map[string]string{
+				minio.ListFormatKey:        minio.ListFormatNDJSON,
+				minio.PrefixKey:            s.prefix,
+				minio.ContinuationTokenKey: token,
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		scanner := bufio.NewScanner(bytes.NewReader(resp.Data))
+		for scanner.Scan() {
+			var object struct {
+				Key string `json:"key"`
+			}
+			if err := json.Unmarshal(scanner.Bytes(), &object); err != nil {
+				return nil, fmt.Errorf("minio configuration store error. list response: %w", err)
+			}
+			keys = append(keys, strings.TrimPrefix(object.Key, s.prefix))
+		}
+		if token = resp.Metadata[minio.ContinuationTokenKey]; token == "" {
+			return keys, nil
+		}
+	}
+}
+
+// Subscribe starts delivering changes to the requested keys, or to every key
+// under the prefix, until ctx is done. It uses a dedicated binding instance
+// that listens for notifications on the prefix. Deleted keys are delivered
+// with an empty value and a deleted metadata flag.
+func (s *ConfigurationStore) Subscribe(ctx context.Context, req *SubscribeRequest, handler UpdateHandler) error {
+	p := make(map[string]string, len(s.properties)+2)
+	for k, v := range s.properties {
+		p[k] = v
+	}
+	p[minio.InputModeKey] = minio.InputModeListen
+	p[minio.NotificationPrefixKey] = s.prefix
+
+	watcher := minio.NewMinio(s.logger)
+	if err := watcher.Init(bindings.Metadata{Properties: p}); err != nil {
+		return err
+	}
+	go func() {
+		<-ctx.Done()
+		watcher.Close()
+	}()
+	go func() {
+		err := watcher.Read(func(resp *bindings.ReadResponse) ([]byte, error) {
+			event, err := s.updateEvent(ctx, req.Keys, resp.Metadata)
+			if err != nil || event == nil {
+				return nil, err
+			}
+			return nil, handler(ctx, event)
+		})
+		if err != nil {
+			s.logger.Errorf("Minio configuration store subscription stopped: %s", err)
+		}
+	}()
+	return nil
+}
+
+// updateEvent turns a bucket notification into an update, or nil when the
+// key isn't subscribed to.
+func (s *ConfigurationStore) updateEvent(ctx context.Context, keys []string, event map[string]string) (*UpdateEvent, error) {
+	key := strings.TrimPrefix(event["key"], s.prefix)
+	if !subscribed(keys, key) {
+		return nil, nil
+	}
+	if strings.HasPrefix(event["eventName"], "s3:ObjectRemoved:") {
+		return &UpdateEvent{Items: []*Item{{Key: key, Metadata: map[string]string{"deleted": "true"}}}}, nil
+	}
+	resp, err := s.Get(ctx, &GetRequest{Keys: []string{key}})
+	if err != nil {
+		return nil, err
+	}
+	// removed again before it could be read; the removal has its own event
+	if len(resp.Items) == 0 {
+		return nil, nil
+	}
+	return &UpdateEvent{Items: resp.Items}, nil
+}
+
+func subscribed(keys []string, key string) bool {
+	if len(keys) == 0 {
+		return true
+	}
+	for _, k := range keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,40 @@
+package configstore
+
+import (
+	"context"
+	"github.com/dapr/kit/logger"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestItemsFromBatch(t *testing.T) {
+	data := []byte(`{
+		"cfg/a": {"data": "MQ==", "metadata": {"etag": "e1"}},
+		"cfg/missing": {"metadata": {"exists": "false"}}
+	}`)
+	items, err := itemsFromBatch([]string{"a", "missing"}, "cfg/", data)
+	assert.Nil(t, err)
+	assert.Equal(t, []*Item{{Key: "a", Value: "1", Version: "e1"}}, items)
+
+	_, err = itemsFromBatch([]string{"a"}, "cfg/", []byte(`{"cfg/a": {"error": "denied", "code": "AccessDenied"}}`))
+	assert.EqualError(t, err, "minio configuration store error. get a: denied")
+}
+
+func TestUpdateEvent(t *testing.T) {
+	s := NewMinioConfigurationStore(logger.NewLogger("minio"))
+	s.prefix = "cfg/"
+
+	event, err := s.updateEvent(context.Background(), []string{"a"}, map[string]string{"eventName": "s3:ObjectRemoved:Delete", "key": "cfg/a"})
+	assert.Nil(t, err)
+	assert.Equal(t, &UpdateEvent{Items: []*Item{{Key: "a", Metadata: map[string]string{"deleted": "true"}}}}, event)
+
+	event, err = s.updateEvent(context.Background(), []string{"a"}, map[string]string{"eventName": "s3:ObjectCreated:Put", "key": "cfg/b"})
+	assert.Nil(t, err)
+	assert.Nil(t, event)
+}
+
+func TestSubscribed(t *testing.T) {
+	assert.True(t, subscribed(nil, "a"))
+	assert.True(t, subscribed([]string{"a", "b"}, "b"))
+	assert.False(t, subscribed([]string{"a"}, "b"))
+}
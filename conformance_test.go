@@ -0,0 +1,48 @@
+//go:build conftests
+// +build conftests
+
+package minio
+
+import (
+	conf_bindings "github.com/dapr/components-contrib/tests/conformance/bindings"
+	"github.com/dapr/kit/logger"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+)
+
+// TestBindingsConformance runs the components-contrib output and input
+// binding conformance suite against the MinIO server at MINIO_ENDPOINT:
+//
+//	docker run -d -p 9000:9000 minio/minio server /data
+//	go test -tags conftests -run Conformance ./...
+func TestBindingsConformance(t *testing.T) {
+	props := map[string]string{
+		Endpoint:          getenv("MINIO_ENDPOINT", "localhost:9000"),
+		AccessKey:         getenv("MINIO_ACCESS_KEY", "minioadmin"),
+		SecretAccessKey:   getenv("MINIO_SECRET_KEY", "minioadmin"),
+		SSLKey:            getenv("MINIO_SSL", "false"),
+		BucketKey:         getenv("MINIO_BUCKET", "conformance"),
+		MissingAsEmptyKey: "true",
+	}
+	config, err := conf_bindings.NewTestConfig("minio", false,
+		[]string{"create", "operations", "get", "list", "delete", "read"},
+		map[string]interface{}{
+			"output": map[string]string{"key": uuid.New().String()},
+		})
+	assert.NoError(t, err)
+
+	input := NewMinio(logger.NewLogger("minio-input"))
+	output := NewMinio(logger.NewLogger("minio-output"))
+	defer input.Close()
+	defer output.Close()
+	conf_bindings.ConformanceTests(t, props, input, output, config)
+}
+
+func getenv(key, defaultValue string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return defaultValue
+}
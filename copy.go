@@ -0,0 +1,162 @@
+package minio
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/dapr/components-contrib/bindings"
+	"github.com/minio/minio-go/v7"
+	"github.com/pkg/errors"
+	"strings"
+)
+
+const (
+	// CopyOperation copies sourceKey to the request's key on the server,
+	// without the content passing through the binding. Objects up to 5 GiB
+	// can be copied.
+	CopyOperation bindings.OperationKind = "copy"
+
+	SourceKeyKey       = "sourceKey"
+	SourceBucketKey    = "sourceBucket"
+	SourceVersionIDKey = "sourceVersionID"
+
+	// MetadataDirectiveKey is COPY to keep the metadata of the source, the
+	// default, or REPLACE to set the userMetadata-* and contentType of the
+	// request instead.
+	MetadataDirectiveKey = "metadataDirective"
+	// TaggingDirectiveKey is COPY to keep the tags of the source, the
+	// default, or REPLACE to set the tag-* of the request instead.
+	TaggingDirectiveKey = "taggingDirective"
+	UserMetadataPrefix  = "userMetadata-"
+	TagPrefix           = "tag-"
+	ContentTypeKey      = "contentType"
+
+	DirectiveCopy    = "COPY"
+	DirectiveReplace = "REPLACE"
+)
+
+// bindingMetadataKeys is the user metadata the binding reads objects by. It
+// is kept when the metadata is replaced, or the copy couldn't be read back.
+var bindingMetadataKeys = []string{compressionMetadataKey, originalSizeMetadataKey, clientEncryptionMetadataKey}
+
+type copyResponse struct {
+	Key       string `json:"key"`
+	VersionID string `json:"versionID,omitempty"`
+	SourceKey string `json:"sourceKey"`
+}
+
+// parseDirective reads a COPY or REPLACE directive, and with COPY refuses the
+// request metadata that would only apply to REPLACE.
+func parseDirective(p map[string]string, key string, replacing ...string) (bool, error) {
+	directive := strings.ToUpper(p[key])
+	switch directive {
+	case "", DirectiveCopy:
+		for k := range p {
+			for _, r := range replacing {
+				if k == r || strings.HasSuffix(r, "-") && strings.HasPrefix(k, r) {
+					return false, errors.Errorf("Minio %s only applies with %s %s", k, key, DirectiveReplace)
+				}
+			}
+		}
+		return false, nil
+	case DirectiveReplace:
+		return true, nil
+	default:
+		return false, errors.Errorf("unsupported Minio %s %s", key, p[key])
+	}
+}
+
+// prefixed returns the request metadata with prefix, without the prefix.
+func prefixed(p map[string]string, prefix string) map[string]string {
+	values := map[string]string{}
+	for k, v := range p {
+		if name := strings.TrimPrefix(k, prefix); name != k && name != "" {
+			values[name] = v
+		}
+	}
+	return values
+}
+
+func (m *Minio) copy(ctx context.Context, req *bindings.InvokeRequest) (*bindings.InvokeResponse, error) {
+	p := req.Metadata
+
+	objectName := objectNameOf(p)
+	if objectName == "" {
+		return nil, errors.Errorf("missing name field")
+	}
+	sourceKey := p[SourceKeyKey]
+	if sourceKey == "" {
+		return nil, errors.Errorf("missing %s field", SourceKeyKey)
+	}
+	sourceBucket := p[SourceBucketKey]
+	if sourceBucket == "" {
+		sourceBucket = m.Bucket
+	}
+	replaceMetadata, err := parseDirective(p, MetadataDirectiveKey, UserMetadataPrefix, ContentTypeKey)
+	if err != nil {
+		return nil, err
+	}
+	replaceTags, err := parseDirective(p, TaggingDirectiveKey, TagPrefix)
+	if err != nil {
+		return nil, err
+	}
+	client, err := m.clientFor(p)
+	if err != nil {
+		return nil, err
+	}
+	client = primaryClient(client)
+	sourceSSE, err := readEncryption(m.properties, p)
+	if err != nil {
+		return nil, err
+	}
+	destSSE, err := serverSideEncryption(m.properties, p)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.checkPreconditions(ctx, client, objectName, p); err != nil {
+		return nil, err
+	}
+
+	src := minio.CopySrcOptions{Bucket: sourceBucket, Object: sourceKey, VersionID: p[SourceVersionIDKey], Encryption: sourceSSE}
+	dst := minio.CopyDestOptions{Bucket: m.Bucket, Object: objectName, Encryption: destSSE, ReplaceMetadata: replaceMetadata, ReplaceTags: replaceTags}
+	if replaceMetadata {
+		source, err := client.StatObject(ctx, sourceBucket, sourceKey, minio.StatObjectOptions{VersionID: src.VersionID, ServerSideEncryption: sourceSSE})
+		if err != nil {
+			return nil, fmt.Errorf("minio binding error. stat source: %w", err)
+		}
+		dst.UserMetadata = prefixed(p, UserMetadataPrefix)
+		for _, k := range bindingMetadataKeys {
+			if v, ok := source.UserMetadata[k]; ok {
+				dst.UserMetadata[k] = v
+			}
+		}
+		if encoding := source.Metadata.Get("Content-Encoding"); encoding != "" {
+			dst.UserMetadata["Content-Encoding"] = encoding
+		}
+		if contentType := p[ContentTypeKey]; contentType != "" {
+			dst.UserMetadata["Content-Type"] = contentType
+		}
+	}
+	if replaceTags {
+		dst.UserTags = prefixed(p, TagPrefix)
+	}
+
+	info, err := client.CopyObject(ctx, dst, src)
+	if err != nil {
+		return nil, fmt.Errorf("minio binding error. copy: %w", err)
+	}
+	m.mirror.enqueue(uploadMirrorTask(objectName, destSSE))
+
+	jsonResponse, err := json.Marshal(copyResponse{Key: objectName, VersionID: info.VersionID, SourceKey: sourceKey})
+	if err != nil {
+		return nil, err
+	}
+	return &bindings.InvokeResponse{
+		Data: jsonResponse,
+		Metadata: map[string]string{
+			"key":       objectName,
+			"etag":      info.ETag,
+			"versionID": info.VersionID,
+		},
+	}, nil
+}
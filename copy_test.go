@@ -0,0 +1,104 @@
+package minio
+
+import (
+	"github.com/dapr/components-contrib/bindings"
+	"github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func putTagged(fake *fakeClient) {
+	fake.put("b", "a.txt", []byte("hello"), minio.ObjectInfo{
+		ContentType:  "text/plain",
+		Metadata:     http.Header{},
+		UserMetadata: minio.StringMap{"Owner": "alice"},
+		UserTags:     map[string]string{"team": "a"},
+	})
+}
+
+func TestCopyKeepsMetadata(t *testing.T) {
+	m, fake := newFakeMinio()
+	putTagged(fake)
+
+	resp, err := m.Invoke(&bindings.InvokeRequest{Operation: CopyOperation, Metadata: map[string]string{"key": "b.txt", "sourceKey": "a.txt"}})
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"key":"b.txt","sourceKey":"a.txt"}`, string(resp.Data))
+
+	copied := fake.buckets["b"]["b.txt"]
+	assert.Equal(t, "hello", string(copied.data))
+	assert.Equal(t, "text/plain", copied.info.ContentType)
+	assert.Equal(t, "alice", copied.info.UserMetadata["Owner"])
+	assert.Equal(t, map[string]string{"team": "a"}, copied.info.UserTags)
+	assert.Equal(t, copied.info.ETag, resp.Metadata["etag"])
+}
+
+func TestCopyReplacesMetadataAndTags(t *testing.T) {
+	m, fake := newFakeMinio()
+	putTagged(fake)
+
+	_, err := m.Invoke(&bindings.InvokeRequest{Operation: CopyOperation, Metadata: map[string]string{
+		"key":                   "b.txt",
+		"sourceKey":             "a.txt",
+		"metadataDirective":     "replace",
+		"userMetadata-reviewer": "bob",
+		"contentType":           "text/markdown",
+		"taggingDirective":      "REPLACE",
+		"tag-team":              "b",
+	}})
+	assert.Nil(t, err)
+
+	copied := fake.buckets["b"]["b.txt"].info
+	assert.Equal(t, "text/markdown", copied.ContentType)
+	assert.Equal(t, minio.StringMap{"Reviewer": "bob"}, copied.UserMetadata)
+	assert.Equal(t, map[string]string{"team": "b"}, copied.UserTags)
+}
+
+func TestCopyReplaceKeepsBindingMetadata(t *testing.T) {
+	m, fake := newFakeMinio()
+	_, err := m.Invoke(&bindings.InvokeRequest{Operation: bindings.CreateOperation, Data: []byte("hello"), Metadata: map[string]string{"key": "a.txt", "compress": "gzip"}})
+	assert.Nil(t, err)
+
+	_, err = m.Invoke(&bindings.InvokeRequest{Operation: CopyOperation, Metadata: map[string]string{"key": "b.txt", "sourceKey": "a.txt", "metadataDirective": "REPLACE"}})
+	assert.Nil(t, err)
+
+	assert.Equal(t, CompressionGzip, fake.buckets["b"]["b.txt"].info.UserMetadata[compressionMetadataKey])
+	// the copy can still be decompressed on get
+	resp, err := m.Invoke(&bindings.InvokeRequest{Operation: bindings.GetOperation, Metadata: map[string]string{"key": "b.txt", DecompressKey: "true"}})
+	assert.Nil(t, err)
+	assert.Equal(t, "hello", string(resp.Data))
+}
+
+func TestCopyValidation(t *testing.T) {
+	m, fake := newFakeMinio()
+	putTagged(fake)
+
+	for _, c := range []struct {
+		p   map[string]string
+		msg string
+	}{
+		{map[string]string{"sourceKey": "a.txt"}, "missing name field"},
+		{map[string]string{"key": "b.txt"}, "missing sourceKey field"},
+		{map[string]string{"key": "b.txt", "sourceKey": "a.txt", "metadataDirective": "MERGE"}, "unsupported Minio metadataDirective MERGE"},
+		{map[string]string{"key": "b.txt", "sourceKey": "a.txt", "taggingDirective": "MERGE"}, "unsupported Minio taggingDirective MERGE"},
+		{map[string]string{"key": "b.txt", "sourceKey": "a.txt", "tag-team": "b"}, "Minio tag-team only applies with taggingDirective REPLACE"},
+		{map[string]string{"key": "b.txt", "sourceKey": "a.txt", "contentType": "text/csv"}, "Minio contentType only applies with metadataDirective REPLACE"},
+	} {
+		_, err := m.Invoke(&bindings.InvokeRequest{Operation: CopyOperation, Metadata: c.p})
+		assert.EqualError(t, err, c.msg)
+	}
+	assert.NotContains(t, fake.buckets["b"], "b.txt")
+
+	_, err := m.Invoke(&bindings.InvokeRequest{Operation: CopyOperation, Metadata: map[string]string{"key": "b.txt", "sourceKey": "gone.txt"}})
+	assert.Error(t, err)
+}
+
+func TestCopyMirrored(t *testing.T) {
+	m, target, mr := newMirrorMinio()
+	m.minioClient.(*fakeClient).put("b", "a.txt", []byte("hello"), minio.ObjectInfo{})
+
+	_, err := m.Invoke(&bindings.InvokeRequest{Operation: CopyOperation, Metadata: map[string]string{"key": "b.txt", "sourceKey": "a.txt"}})
+	assert.Nil(t, err)
+	drain(mr)
+	assert.Equal(t, "hello", string(target.buckets["dr"]["b.txt"].data))
+}
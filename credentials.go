@@ -0,0 +1,245 @@
+package minio
+
+import (
+	"fmt"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/pkg/errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	AuthModeKey             = "authMode"
+	AnonymousKey            = "anonymous"
+	SessionTokenKey         = "sessionToken"
+	STSEndpointKey          = "stsEndpoint"
+	WebIdentityTokenKey     = "webIdentityToken"
+	WebIdentityTokenFileKey = "webIdentityTokenFile"
+	WebIdentityDurationKey  = "webIdentityDuration"
+	LDAPUsernameKey         = "ldapUsername"
+	LDAPPasswordKey         = "ldapPassword"
+	CredentialsFileKey      = "credentialsFile"
+	CredentialsProfileKey   = "credentialsProfile"
+	SignatureVersionKey     = "signatureVersion"
+
+	AuthModeStatic      = "static"
+	AuthModeWebIdentity = "webIdentity"
+	AuthModeLDAP        = "ldap"
+	AuthModeChain       = "chain"
+	AuthModeKubernetes  = "kubernetes"
+
+	// DefaultServiceAccountTokenFile is where Kubernetes mounts the pod's
+	// service-account token. Projected tokens with a MinIO audience are usually
+	// mounted elsewhere and set through webIdentityTokenFile.
+	DefaultServiceAccountTokenFile = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+)
+
+// newCredentials builds the credentials provider selected by the authMode
+// property. STS requests go through transport, so they trust the same CAs and
+// present the same client certificate as the object requests.
+func newCredentials(p map[string]string, endpoint string, secure bool, transport http.RoundTripper) (*credentials.Credentials, error) {
+	if propertyToBool(p, AnonymousKey) {
+		return credentials.NewStatic("", "", "", credentials.SignatureAnonymous), nil
+	}
+	switch mode := p[AuthModeKey]; mode {
+	case "", AuthModeStatic:
+		return staticCredentials(p)
+	case AuthModeWebIdentity:
+		return webIdentityCredentials(p, stsEndpoint(p, endpoint, secure), transport)
+	case AuthModeLDAP:
+		return ldapCredentials(p, stsEndpoint(p, endpoint, secure), transport)
+	case AuthModeChain:
+		return chainCredentials(p), nil
+	case AuthModeKubernetes:
+		return kubernetesCredentials(p, stsEndpoint(p, endpoint, secure), transport)
+	case AuthModeVault:
+		return vaultCredentials(p)
+	default:
+		return nil, errors.Errorf("unsupported Minio authMode %s", mode)
+	}
+}
+
+func staticCredentials(p map[string]string) (*credentials.Credentials, error) {
+	accessKey, ok := p[AccessKey]
+	if !ok || accessKey == "" {
+		return nil, errors.Errorf("missing Minio accessKey string")
+	}
+	secretKey, ok := p[SecretAccessKey]
+	if !ok || secretKey == "" {
+		return nil, errors.Errorf("missing Minio secretKey string")
+	}
+	// sessionToken is only set for temporary credentials issued out-of-band.
+	switch version := p[SignatureVersionKey]; version {
+	case "", "v4":
+		return credentials.NewStaticV4(accessKey, secretKey, p[SessionTokenKey]), nil
+	case "v2":
+		return credentials.NewStaticV2(accessKey, secretKey, p[SessionTokenKey]), nil
+	default:
+		return nil, errors.Errorf("unsupported Minio signatureVersion %s", version)
+	}
+}
+
+// stsEndpoint defaults to the MinIO endpoint itself, which serves the STS API.
+func stsEndpoint(p map[string]string, endpoint string, secure bool) string {
+	if v := p[STSEndpointKey]; v != "" {
+		return v
+	}
+	if secure {
+		return "https://" + endpoint
+	}
+	return "http://" + endpoint
+}
+
+// stsClient sends STS requests through the binding's transport, or the
+// default one when no TLS or pool settings are configured.
+func stsClient(transport http.RoundTripper) *http.Client {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return &http.Client{Transport: transport}
+}
+
+// webIdentityCredentials exchanges an OIDC token for temporary STS credentials.
+// minio-go refreshes them shortly before they expire by calling back into the
+// token getter, so a token file is re-read each time to pick up rotated tokens.
+func webIdentityCredentials(p map[string]string, sts string, transport http.RoundTripper) (*credentials.Credentials, error) {
+	token := p[WebIdentityTokenKey]
+	tokenFile := p[WebIdentityTokenFileKey]
+	if token == "" && tokenFile == "" {
+		return nil, errors.Errorf("missing Minio webIdentityToken or webIdentityTokenFile string")
+	}
+	expiry := 0
+	if v, ok := p[WebIdentityDurationKey]; ok && v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return nil, errors.Errorf("webIdentityDuration %s is invalid", v)
+		}
+		expiry = int(d.Seconds())
+	}
+
+	return credentials.New(&credentials.STSWebIdentity{
+		Client:      stsClient(transport),
+		STSEndpoint: sts,
+		GetWebIDTokenExpiry: func() (*credentials.WebIdentityToken, error) {
+			t := token
+			if tokenFile != "" {
+				b, err := ioutil.ReadFile(tokenFile)
+				if err != nil {
+					return nil, fmt.Errorf("minio binding error. read web identity token: %w", err)
+				}
+				t = strings.TrimSpace(string(b))
+			}
+			return &credentials.WebIdentityToken{Token: t, Expiry: expiry}, nil
+		},
+	}), nil
+}
+
+// kubernetesCredentials exchanges the pod's service-account token through
+// AssumeRoleWithWebIdentity, so no keys have to be deployed with the pod.
+func kubernetesCredentials(p map[string]string, sts string, transport http.RoundTripper) (*credentials.Credentials, error) {
+	if p[WebIdentityTokenFileKey] == "" {
+		withDefault := make(map[string]string, len(p)+1)
+		for k, v := range p {
+			withDefault[k] = v
+		}
+		withDefault[WebIdentityTokenFileKey] = DefaultServiceAccountTokenFile
+		p = withDefault
+	}
+	return webIdentityCredentials(p, sts, transport)
+}
+
+func ldapCredentials(p map[string]string, sts string, transport http.RoundTripper) (*credentials.Credentials, error) {
+	username, ok := p[LDAPUsernameKey]
+	if !ok || username == "" {
+		return nil, errors.Errorf("missing Minio ldapUsername string")
+	}
+	password, ok := p[LDAPPasswordKey]
+	if !ok || password == "" {
+		return nil, errors.Errorf("missing Minio ldapPassword string")
+	}
+	return credentials.New(&credentials.LDAPIdentity{
+		Client:       stsClient(transport),
+		STSEndpoint:  sts,
+		LDAPUsername: username,
+		LDAPPassword: password,
+	}), nil
+}
+
+// chainCredentials resolves credentials from the environment, the shared
+// credentials files and finally the IAM endpoint, so none have to be
+// embedded in the component definition.
+func chainCredentials(p map[string]string) *credentials.Credentials {
+	return credentials.NewChainCredentials([]credentials.Provider{
+		&credentials.EnvAWS{},
+		&credentials.EnvMinio{},
+		&credentials.FileAWSCredentials{
+			Filename: p[CredentialsFileKey],
+			Profile:  p[CredentialsProfileKey],
+		},
+		&credentials.FileMinioClient{},
+		&credentials.IAM{
+			Client: &http.Client{Transport: http.DefaultTransport},
+		},
+	})
+}
+
+// rotatingProvider lets the credentials of live clients be swapped without
+// rebuilding them. Every rotation starts a new generation; each client's
+// credentials remember the generation they last retrieved, and minio-go checks
+// IsExpired before signing every request, so a rotation takes effect on the
+// next operation of every client.
+type rotatingProvider struct {
+	mu         sync.Mutex
+	current    *credentials.Credentials
+	generation uint64
+}
+
+func newRotatingProvider(c *credentials.Credentials) *rotatingProvider {
+	return &rotatingProvider{current: c}
+}
+
+// clientCredentials returns credentials for one client. They must not be
+// shared between clients.
+func (r *rotatingProvider) clientCredentials() *credentials.Credentials {
+	return credentials.New(&rotatingCredentials{rotating: r})
+}
+
+// value returns the current keys without affecting any client.
+func (r *rotatingProvider) value() (credentials.Value, error) {
+	current, _ := r.load()
+	return current.Get()
+}
+
+func (r *rotatingProvider) load() (*credentials.Credentials, uint64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.current, r.generation
+}
+
+func (r *rotatingProvider) rotate(c *credentials.Credentials) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.current = c
+	r.generation++
+}
+
+// rotatingCredentials is one client's view of a rotatingProvider.
+// credentials.Credentials serializes the calls into it.
+type rotatingCredentials struct {
+	rotating   *rotatingProvider
+	generation uint64
+}
+
+func (c *rotatingCredentials) Retrieve() (credentials.Value, error) {
+	current, generation := c.rotating.load()
+	c.generation = generation
+	return current.Get()
+}
+
+func (c *rotatingCredentials) IsExpired() bool {
+	current, generation := c.rotating.load()
+	return generation != c.generation || current.IsExpired()
+}
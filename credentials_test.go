@@ -0,0 +1,129 @@
+package minio
+
+import (
+	"encoding/pem"
+	"github.com/dapr/kit/logger"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNewCredentials(t *testing.T) {
+	t.Run("static requires accessKey", func(t *testing.T) {
+		_, err := newCredentials(map[string]string{SecretAccessKey: "secret"}, "localhost:9000", false, nil)
+		assert.EqualError(t, err, "missing Minio accessKey string")
+	})
+
+	t.Run("anonymous needs no keys", func(t *testing.T) {
+		c, err := newCredentials(map[string]string{AnonymousKey: "true"}, "localhost:9000", false, nil)
+		assert.NoError(t, err)
+		v, err := c.Get()
+		assert.NoError(t, err)
+		assert.True(t, v.SignerType.IsAnonymous())
+	})
+
+	t.Run("static with signature v2", func(t *testing.T) {
+		c, err := newCredentials(map[string]string{AccessKey: "key", SecretAccessKey: "secret", SignatureVersionKey: "v2"}, "localhost:9000", false, nil)
+		assert.NoError(t, err)
+		v, err := c.Get()
+		assert.NoError(t, err)
+		assert.True(t, v.SignerType.IsV2())
+	})
+
+	t.Run("unsupported signatureVersion", func(t *testing.T) {
+		_, err := newCredentials(map[string]string{AccessKey: "key", SecretAccessKey: "secret", SignatureVersionKey: "v3"}, "localhost:9000", false, nil)
+		assert.EqualError(t, err, "unsupported Minio signatureVersion v3")
+	})
+
+	t.Run("webIdentity requires a token", func(t *testing.T) {
+		_, err := newCredentials(map[string]string{AuthModeKey: AuthModeWebIdentity}, "localhost:9000", false, nil)
+		assert.EqualError(t, err, "missing Minio webIdentityToken or webIdentityTokenFile string")
+	})
+
+	t.Run("ldap requires a username", func(t *testing.T) {
+		_, err := newCredentials(map[string]string{AuthModeKey: AuthModeLDAP, LDAPPasswordKey: "secret"}, "localhost:9000", false, nil)
+		assert.EqualError(t, err, "missing Minio ldapUsername string")
+	})
+
+	t.Run("unsupported authMode", func(t *testing.T) {
+		_, err := newCredentials(map[string]string{AuthModeKey: "kerberos"}, "localhost:9000", false, nil)
+		assert.EqualError(t, err, "unsupported Minio authMode kerberos")
+	})
+}
+
+func TestSTSUsesTransport(t *testing.T) {
+	sts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		assert.Equal(t, "AssumeRoleWithWebIdentity", r.Form.Get("Action"))
+		w.Header().Set("Content-Type", "application/xml")
+		_, _ = w.Write([]byte(`<AssumeRoleWithWebIdentityResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/"><AssumeRoleWithWebIdentityResult><Credentials>` +
+			`<AccessKeyId>sts-key</AccessKeyId><SecretAccessKey>sts-secret</SecretAccessKey><SessionToken>sts-token</SessionToken>` +
+			`</Credentials></AssumeRoleWithWebIdentityResult></AssumeRoleWithWebIdentityResponse>`))
+	}))
+	defer sts.Close()
+
+	ca := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: sts.Certificate().Raw}))
+	p := map[string]string{
+		AuthModeKey:         AuthModeWebIdentity,
+		WebIdentityTokenKey: "jwt",
+		STSEndpointKey:      sts.URL,
+		CACertKey:           ca,
+	}
+	endpoint := strings.TrimPrefix(sts.URL, "https://")
+
+	transport, err := NewMinio(logger.NewLogger("test")).newTransport(p, true)
+	require.NoError(t, err)
+	c, err := newCredentials(p, endpoint, true, transport)
+	require.NoError(t, err)
+	v, err := c.Get()
+	require.NoError(t, err)
+	assert.Equal(t, "sts-key", v.AccessKeyID)
+	assert.Equal(t, "sts-token", v.SessionToken)
+
+	// without the caCert the STS certificate isn't trusted
+	c, err = newCredentials(p, endpoint, true, nil)
+	require.NoError(t, err)
+	_, err = c.Get()
+	assert.Error(t, err)
+}
+
+func TestSTSEndpoint(t *testing.T) {
+	assert.Equal(t, "http://localhost:9000", stsEndpoint(map[string]string{}, "localhost:9000", false))
+	assert.Equal(t, "https://localhost:9000", stsEndpoint(map[string]string{}, "localhost:9000", true))
+	assert.Equal(t, "https://sts.example.com", stsEndpoint(map[string]string{STSEndpointKey: "https://sts.example.com"}, "localhost:9000", true))
+}
+
+func TestRotatingProvider(t *testing.T) {
+	r := newRotatingProvider(credentials.NewStaticV4("old", "old-secret", ""))
+	object, admin := r.clientCredentials(), r.clientCredentials()
+
+	for _, c := range []*credentials.Credentials{object, admin} {
+		v, err := c.Get()
+		assert.NoError(t, err)
+		assert.Equal(t, "old", v.AccessKeyID)
+	}
+
+	r.rotate(credentials.NewStaticV4("new", "new-secret", "token"))
+	assert.True(t, object.IsExpired())
+	assert.True(t, admin.IsExpired())
+
+	// every client picks up the rotation, not just the first to ask
+	for _, c := range []*credentials.Credentials{object, admin} {
+		v, err := c.Get()
+		assert.NoError(t, err)
+		assert.Equal(t, "new", v.AccessKeyID)
+		assert.Equal(t, "token", v.SessionToken)
+		assert.False(t, c.IsExpired())
+	}
+
+	// reading the keys directly doesn't hide a rotation from the clients
+	r.rotate(credentials.NewStaticV4("newer", "newer-secret", ""))
+	v, err := r.value()
+	assert.NoError(t, err)
+	assert.Equal(t, "newer", v.AccessKeyID)
+	assert.True(t, object.IsExpired())
+}
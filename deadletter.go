@@ -0,0 +1,54 @@
+package minio
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/notification"
+	"strings"
+	"time"
+)
+
+type deadLetter struct {
+	Event    notification.Event `json:"event"`
+	Error    string             `json:"error"`
+	Attempts int64              `json:"attempts"`
+	FailedAt time.Time          `json:"failedAt"`
+}
+
+func (r *reader) deadLetterBucket() string {
+	if r.input.deadLetterBucket != "" {
+		return r.input.deadLetterBucket
+	}
+	return r.bucket
+}
+
+// deadLetter stores an event the app failed to handle under deadLetterPrefix
+// for later inspection.
+func (r *reader) deadLetter(event notification.Event, cause error) error {
+	b, err := json.Marshal(deadLetter{
+		Event:    event,
+		Error:    cause.Error(),
+		Attempts: r.input.deliveryRetries + 1,
+		FailedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return err
+	}
+
+	key := deadLetterKey(r.input.deadLetterPrefix, event, time.Now().UTC())
+	_, err = r.client.PutObject(r.ctx, r.deadLetterBucket(), key, bytes.NewReader(b), int64(len(b)), minio.PutObjectOptions{
+		ContentType: "application/json",
+	})
+	if err != nil {
+		return fmt.Errorf("minio binding error. dead letter: %w", err)
+	}
+	r.logger.Warnf("Minio binding dead-lettered %s for %s to %s", event.EventName, eventKey(event), key)
+	return nil
+}
+
+func deadLetterKey(prefix string, event notification.Event, now time.Time) string {
+	name := strings.NewReplacer("/", "_", ":", "_").Replace(eventKey(event))
+	return prefix + now.Format("20060102T150405.000000000Z") + "-" + name + ".json"
+}
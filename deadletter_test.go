@@ -0,0 +1,61 @@
+package minio
+
+import (
+	"errors"
+	"github.com/dapr/components-contrib/bindings"
+	"github.com/minio/minio-go/v7/pkg/notification"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestDeadLetterKey(t *testing.T) {
+	var event notification.Event
+	event.S3.Object.Key = "uploads%2Fa.txt"
+	now := time.Date(2021, 10, 1, 10, 0, 0, 0, time.UTC)
+	assert.Equal(t, "dlq/20211001T100000.000000000Z-uploads_a.txt.json", deadLetterKey("dlq/", event, now))
+}
+
+func TestDeliverWithRetry(t *testing.T) {
+	m, _ := newFakeMinio()
+	m.input.deliveryRetries = 2
+	m.input.deliveryBackoff = time.Millisecond
+	r, err := m.newReader()
+	require.NoError(t, err)
+
+	var calls int
+	err = r.deliverWithRetry(func(*bindings.ReadResponse) ([]byte, error) {
+		calls++
+		return nil, errors.New("app unavailable")
+	}, notification.Event{})
+	assert.EqualError(t, err, "app unavailable")
+	assert.Equal(t, 3, calls)
+
+	calls = 0
+	err = r.deliverWithRetry(func(*bindings.ReadResponse) ([]byte, error) {
+		calls++
+		if calls < 2 {
+			return nil, errors.New("app unavailable")
+		}
+		return nil, nil
+	}, notification.Event{})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, calls)
+}
+
+func TestIsControlObject(t *testing.T) {
+	m, _ := newFakeMinio()
+	m.Bucket = "fos"
+	m.input.deadLetterPrefix = "dlq/"
+	m.SetCheckpointStore(&objectCheckpointStore{client: m.minioClient, bucket: "fos", key: ".dapr/checkpoints/minio"})
+	r, err := m.newReader()
+	require.NoError(t, err)
+
+	assert.True(t, r.isControlObject(".dapr/checkpoints/minio"))
+	assert.True(t, r.isControlObject("dlq/event.json"))
+	assert.False(t, r.isControlObject("uploads/a.txt"))
+
+	r.input.deadLetterBucket = "failures"
+	assert.False(t, r.isControlObject("dlq/event.json"))
+}
@@ -0,0 +1,137 @@
+package minio
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/encrypt"
+	"github.com/minio/minio-go/v7/pkg/sse"
+	"github.com/pkg/errors"
+)
+
+const (
+	EncryptionKey     = "encryption"
+	SSECustomerKeyKey = "sseCustomerKey"
+	SSEKMSKeyIDKey    = "sseKmsKeyId"
+	SSEKMSContextKey  = "sseKmsContext"
+
+	RequireEncryptedBucketKey = "requireEncryptedBucket"
+
+	EncryptionSSES3  = "sse-s3"
+	EncryptionSSEC   = "sse-c"
+	EncryptionSSEKMS = "sse-kms"
+)
+
+// serverSideEncryption returns the encryption to upload with. The request
+// metadata takes precedence over the component property; nil means none.
+func serverSideEncryption(component, request map[string]string) (encrypt.ServerSide, error) {
+	mode, ok := request[EncryptionKey]
+	if !ok {
+		mode = component[EncryptionKey]
+	}
+	switch mode {
+	case "", "none":
+		return nil, nil
+	case EncryptionSSES3:
+		return encrypt.NewSSE(), nil
+	case EncryptionSSEC:
+		return customerKey(component, request)
+	case EncryptionSSEKMS:
+		return kmsKey(component, request)
+	default:
+		return nil, errors.Errorf("unsupported Minio encryption %s", mode)
+	}
+}
+
+// readEncryption returns the SSE-C key needed to read or stat an object.
+// Objects encrypted with other modes are decrypted transparently by MinIO.
+func readEncryption(component, request map[string]string) (encrypt.ServerSide, error) {
+	serverSide, err := serverSideEncryption(component, request)
+	if err != nil || serverSide == nil || serverSide.Type() != encrypt.SSEC {
+		return nil, err
+	}
+	return serverSide, nil
+}
+
+// customerKey decodes the base64 SSE-C key. Errors never include the key.
+func customerKey(component, request map[string]string) (encrypt.ServerSide, error) {
+	key, ok := request[SSECustomerKeyKey]
+	if !ok {
+		key = component[SSECustomerKeyKey]
+	}
+	if key == "" {
+		return nil, errors.Errorf("missing Minio sseCustomerKey string")
+	}
+	b, err := base64.StdEncoding.DecodeString(key)
+	if err != nil {
+		return nil, errors.Errorf("Minio sseCustomerKey must be base64 encoded")
+	}
+	serverSide, err := encrypt.NewSSEC(b)
+	if err != nil {
+		return nil, errors.Errorf("Minio sseCustomerKey must be a base64 encoded 256-bit key")
+	}
+	return serverSide, nil
+}
+
+// kmsKey selects the KMS master key, with an optional JSON object as the
+// encryption context.
+func kmsKey(component, request map[string]string) (encrypt.ServerSide, error) {
+	keyID, ok := request[SSEKMSKeyIDKey]
+	if !ok {
+		keyID = component[SSEKMSKeyIDKey]
+	}
+	if keyID == "" {
+		return nil, errors.Errorf("missing Minio sseKmsKeyId string")
+	}
+	contextJSON, ok := request[SSEKMSContextKey]
+	if !ok {
+		contextJSON = component[SSEKMSContextKey]
+	}
+	var kmsContext map[string]string
+	if contextJSON != "" {
+		if err := json.Unmarshal([]byte(contextJSON), &kmsContext); err != nil {
+			return nil, errors.Errorf("Minio sseKmsContext must be a JSON object of strings")
+		}
+	}
+
+	// a nil map must be passed as a nil interface to omit the context header
+	var encryptionContext interface{}
+	if kmsContext != nil {
+		encryptionContext = kmsContext
+	}
+	serverSide, err := encrypt.NewSSEKMS(keyID, encryptionContext)
+	if err != nil {
+		return nil, fmt.Errorf("minio binding error. sse-kms: %w", err)
+	}
+	return serverSide, nil
+}
+
+// checkBucketEncryption refuses buckets without a default encryption rule, so
+// objects written by other clients are encrypted at rest as well.
+func checkBucketEncryption(client objectClient, bucket string) error {
+	config, err := client.GetBucketEncryption(context.Background(), bucket)
+	if err != nil {
+		if minio.ToErrorResponse(err).Code == "ServerSideEncryptionConfigurationNotFoundError" {
+			return errors.Errorf("Minio bucket %s has no default encryption configured", bucket)
+		}
+		return fmt.Errorf("minio binding error. get bucket encryption: %w", err)
+	}
+	if !bucketEncryptionEnforced(config) {
+		return errors.Errorf("Minio bucket %s has no default encryption configured", bucket)
+	}
+	return nil
+}
+
+func bucketEncryptionEnforced(config *sse.Configuration) bool {
+	if config == nil {
+		return false
+	}
+	for _, rule := range config.Rules {
+		if rule.Apply.SSEAlgorithm != "" {
+			return true
+		}
+	}
+	return false
+}
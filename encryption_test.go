@@ -0,0 +1,78 @@
+package minio
+
+import (
+	"bytes"
+	"encoding/base64"
+	"github.com/minio/minio-go/v7/pkg/encrypt"
+	"github.com/minio/minio-go/v7/pkg/sse"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestServerSideEncryption(t *testing.T) {
+	sse, err := serverSideEncryption(map[string]string{}, nil)
+	assert.Nil(t, err)
+	assert.Nil(t, sse)
+
+	sse, err = serverSideEncryption(map[string]string{EncryptionKey: EncryptionSSES3}, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, encrypt.S3, sse.Type())
+
+	sse, err = serverSideEncryption(map[string]string{EncryptionKey: EncryptionSSES3}, map[string]string{EncryptionKey: "none"})
+	assert.Nil(t, err)
+	assert.Nil(t, sse)
+
+	_, err = serverSideEncryption(map[string]string{EncryptionKey: "aes"}, nil)
+	assert.EqualError(t, err, "unsupported Minio encryption aes")
+}
+
+func TestCustomerKey(t *testing.T) {
+	key := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{7}, 32))
+	component := map[string]string{EncryptionKey: EncryptionSSEC}
+
+	_, err := serverSideEncryption(component, nil)
+	assert.EqualError(t, err, "missing Minio sseCustomerKey string")
+
+	sse, err := serverSideEncryption(component, map[string]string{SSECustomerKeyKey: key})
+	assert.Nil(t, err)
+	assert.Equal(t, encrypt.SSEC, sse.Type())
+
+	sse, err = readEncryption(component, map[string]string{SSECustomerKeyKey: key})
+	assert.Nil(t, err)
+	assert.Equal(t, encrypt.SSEC, sse.Type())
+
+	sse, err = readEncryption(map[string]string{EncryptionKey: EncryptionSSES3}, nil)
+	assert.Nil(t, err)
+	assert.Nil(t, sse)
+
+	short := base64.StdEncoding.EncodeToString([]byte("secret"))
+	_, err = serverSideEncryption(component, map[string]string{SSECustomerKeyKey: short})
+	assert.EqualError(t, err, "Minio sseCustomerKey must be a base64 encoded 256-bit key")
+	assert.NotContains(t, err.Error(), short)
+}
+
+func TestKMSKey(t *testing.T) {
+	component := map[string]string{EncryptionKey: EncryptionSSEKMS, SSEKMSKeyIDKey: "tenant-a"}
+
+	sse, err := serverSideEncryption(component, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, encrypt.KMS, sse.Type())
+
+	sse, err = serverSideEncryption(component, map[string]string{SSEKMSContextKey: `{"tenant":"a"}`})
+	assert.Nil(t, err)
+	assert.Equal(t, encrypt.KMS, sse.Type())
+
+	_, err = serverSideEncryption(component, map[string]string{SSEKMSContextKey: `["a"]`})
+	assert.EqualError(t, err, "Minio sseKmsContext must be a JSON object of strings")
+
+	_, err = serverSideEncryption(map[string]string{EncryptionKey: EncryptionSSEKMS}, nil)
+	assert.EqualError(t, err, "missing Minio sseKmsKeyId string")
+}
+
+func TestBucketEncryptionEnforced(t *testing.T) {
+	assert.False(t, bucketEncryptionEnforced(nil))
+	assert.False(t, bucketEncryptionEnforced(&sse.Configuration{}))
+	assert.True(t, bucketEncryptionEnforced(&sse.Configuration{Rules: []sse.Rule{
+		{Apply: sse.ApplySSEByDefault{SSEAlgorithm: "aws:kms", KmsMasterKeyID: "key"}},
+	}}))
+}
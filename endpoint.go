@@ -0,0 +1,95 @@
+package minio
+
+import (
+	"github.com/pkg/errors"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// PortKey is the port of endpoints given without one, for deployments
+// listening on neither 80 nor 443.
+const PortKey = "port"
+
+// parseEndpoints parses a comma-separated endpoint property into validated
+// host:port endpoints, adding port to the ones without a port, and whether
+// they use TLS.
+func parseEndpoints(value string, secure bool, port string) ([]string, bool, error) {
+	endpoints, secure, err := stripScheme(splitEndpoints(value), secure)
+	if err != nil {
+		return nil, false, err
+	}
+	if port != "" && !validPort(port) {
+		return nil, false, errors.Errorf("Minio %s %s is invalid, it must be a number between 1 and 65535", PortKey, port)
+	}
+	for i, endpoint := range endpoints {
+		if endpoints[i], err = normalizeEndpoint(endpoint, port); err != nil {
+			return nil, false, err
+		}
+	}
+	return endpoints, secure, nil
+}
+
+// normalizeEndpoint checks that endpoint is a host with an optional port,
+// IPv6 addresses in brackets, so that mistakes are reported at Init rather
+// than by the first request.
+func normalizeEndpoint(endpoint, port string) (string, error) {
+	if strings.ContainsAny(endpoint, "/?#") {
+		return "", errors.Errorf("Minio endpoint %s must be host:port without a path, the bucket is set with the %s property", endpoint, BucketKey)
+	}
+	host, endpointPort := endpoint, ""
+	if i := strings.LastIndex(endpoint, ":"); i >= 0 && !strings.HasSuffix(endpoint, "]") {
+		host, endpointPort = endpoint[:i], endpoint[i+1:]
+	}
+	switch {
+	case host == "":
+		return "", errors.Errorf("Minio endpoint %s is missing the host", endpoint)
+	case strings.ContainsAny(host, " \t") || (strings.Contains(host, ":") && !strings.HasPrefix(host, "[")):
+		return "", errors.Errorf("Minio endpoint %s is invalid, use host:port, with IPv6 addresses in brackets as in [::1]:9000", endpoint)
+	case endpointPort != "" && !validPort(endpointPort):
+		return "", errors.Errorf("Minio endpoint %s has an invalid port, it must be a number between 1 and 65535", endpoint)
+	case endpointPort != "" && port != "" && endpointPort != port:
+		return "", errors.Errorf("Minio endpoint %s conflicts with %s %s", endpoint, PortKey, port)
+	case endpointPort == "" && port != "":
+		return host + ":" + port, nil
+	}
+	return endpoint, nil
+}
+
+func validPort(port string) bool {
+	n, err := strconv.Atoi(port)
+	return err == nil && n > 0 && n <= 65535
+}
+
+// stripScheme accepts endpoints given as host:port or as http:// and https://
+// URLs, and returns them as host:port together with whether they use TLS.
+// The scheme of the URLs takes precedence over secure, so all of them must
+// agree on it.
+func stripScheme(endpoints []string, secure bool) ([]string, bool, error) {
+	hosts := make([]string, 0, len(endpoints))
+	scheme := ""
+	for _, endpoint := range endpoints {
+		if !strings.Contains(endpoint, "://") {
+			hosts = append(hosts, endpoint)
+			continue
+		}
+		u, err := url.Parse(endpoint)
+		if err != nil {
+			return nil, false, errors.Errorf("invalid Minio endpoint %s", endpoint)
+		}
+		switch {
+		case u.Scheme != "http" && u.Scheme != "https":
+			return nil, false, errors.Errorf("unsupported Minio endpoint scheme %s, use http or https", u.Scheme)
+		case u.User != nil || (u.Path != "" && u.Path != "/") || u.RawQuery != "" || u.Host == "":
+			return nil, false, errors.Errorf("Minio endpoint %s must be scheme://host:port, without credentials or a path", u.Redacted())
+		case scheme != "" && u.Scheme != scheme:
+			return nil, false, errors.Errorf("Minio endpoints mix %s and %s", scheme, u.Scheme)
+		}
+		scheme = u.Scheme
+		hosts = append(hosts, u.Host)
+	}
+	if scheme != "" {
+		secure = scheme == "https"
+	}
+	return hosts, secure, nil
+}
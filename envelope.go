@@ -0,0 +1,57 @@
+package minio
+
+import (
+	"encoding/json"
+	"github.com/dapr/components-contrib/bindings"
+	"github.com/pkg/errors"
+)
+
+const (
+	ResponseEnvelopeKey = "responseEnvelope"
+
+	EnvelopeV1 = "v1"
+)
+
+// responseEnvelope gives every operation the same response shape. get returns
+// the object content base64 encoded; other operations embed their JSON result.
+type responseEnvelope struct {
+	Version   string                 `json:"version"`
+	Operation bindings.OperationKind `json:"operation"`
+	Data      interface{}            `json:"data,omitempty"`
+	Metadata  map[string]string      `json:"metadata,omitempty"`
+}
+
+func parseEnvelopeVersion(p map[string]string) (string, error) {
+	switch v := p[ResponseEnvelopeKey]; v {
+	case "", "false":
+		return "", nil
+	case EnvelopeV1, "true":
+		return EnvelopeV1, nil
+	default:
+		return "", errors.Errorf("unsupported Minio responseEnvelope %s", v)
+	}
+}
+
+func wrapResponse(version string, op bindings.OperationKind, resp *bindings.InvokeResponse) (*bindings.InvokeResponse, error) {
+	envelope := responseEnvelope{Version: version, Operation: op}
+	if resp != nil {
+		envelope.Metadata = resp.Metadata
+		switch {
+		case len(resp.Data) == 0:
+		case op == bindings.GetOperation || op == PreviewOperation:
+			envelope.Data = resp.Data
+		// an archive is returned as data, its upload as JSON
+		case op == ArchiveOperation && !json.Valid(resp.Data):
+			envelope.Data = resp.Data
+		case json.Valid(resp.Data):
+			envelope.Data = json.RawMessage(resp.Data)
+		default:
+			envelope.Data = string(resp.Data)
+		}
+	}
+	b, err := json.Marshal(envelope)
+	if err != nil {
+		return nil, err
+	}
+	return &bindings.InvokeResponse{Data: b, Metadata: map[string]string{"contentType": "application/json"}}, nil
+}
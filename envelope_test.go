@@ -0,0 +1,42 @@
+package minio
+
+import (
+	"github.com/dapr/components-contrib/bindings"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestWrapResponse(t *testing.T) {
+	resp, err := wrapResponse(EnvelopeV1, bindings.GetOperation, &bindings.InvokeResponse{
+		Data:     []byte("hi"),
+		Metadata: map[string]string{"key": "a.txt"},
+	})
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"version":"v1","operation":"get","data":"aGk=","metadata":{"key":"a.txt"}}`, string(resp.Data))
+
+	resp, err = wrapResponse(EnvelopeV1, bindings.CreateOperation, &bindings.InvokeResponse{Data: []byte(`{"key":"a.txt"}`)})
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"version":"v1","operation":"create","data":{"key":"a.txt"}}`, string(resp.Data))
+
+	resp, err = wrapResponse(EnvelopeV1, PresignedGetOperation, &bindings.InvokeResponse{Data: []byte("http://x/a?sig")})
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"version":"v1","operation":"presignedGet","data":"http://x/a?sig"}`, string(resp.Data))
+
+	resp, err = wrapResponse(EnvelopeV1, bindings.DeleteOperation, nil)
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"version":"v1","operation":"delete"}`, string(resp.Data))
+
+	resp, err = wrapResponse(EnvelopeV1, ArchiveOperation, &bindings.InvokeResponse{Data: []byte("PK\x03\x04")})
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"version":"v1","operation":"archive","data":"UEsDBA=="}`, string(resp.Data))
+}
+
+func TestParseEnvelopeVersion(t *testing.T) {
+	v, err := parseEnvelopeVersion(map[string]string{})
+	assert.Nil(t, err)
+	assert.Equal(t, "", v)
+	v, _ = parseEnvelopeVersion(map[string]string{ResponseEnvelopeKey: "v1"})
+	assert.Equal(t, EnvelopeV1, v)
+	_, err = parseEnvelopeVersion(map[string]string{ResponseEnvelopeKey: "v2"})
+	assert.EqualError(t, err, "unsupported Minio responseEnvelope v2")
+}
@@ -0,0 +1,120 @@
+package minio
+
+import (
+	"context"
+	"fmt"
+	"github.com/dapr/components-contrib/bindings"
+	"github.com/minio/minio-go/v7"
+	"github.com/pkg/errors"
+	"net/http"
+)
+
+// Error codes are stable across minio-go and server versions, so callers can
+// branch on them instead of matching error strings.
+const (
+	ErrCodeNotFound           = "NotFound"
+	ErrCodeBucketNotFound     = "BucketNotFound"
+	ErrCodeAccessDenied       = "AccessDenied"
+	ErrCodeBucketNotEmpty     = "BucketNotEmpty"
+	ErrCodePreconditionFailed = "PreconditionFailed"
+	ErrCodeInvalidArgument    = "InvalidArgument"
+	ErrCodeTooLarge           = "TooLarge"
+	ErrCodeThrottled          = "Throttled"
+	ErrCodeUnavailable        = "Unavailable"
+	ErrCodeTimeout            = "Timeout"
+	ErrCodeInternal           = "Internal"
+)
+
+var errorCodes = map[string]string{
+	"NoSuchKey":                  ErrCodeNotFound,
+	"NoSuchVersion":              ErrCodeNotFound,
+	"NoSuchBucket":               ErrCodeBucketNotFound,
+	"AccessDenied":               ErrCodeAccessDenied,
+	"InvalidAccessKeyId":         ErrCodeAccessDenied,
+	"SignatureDoesNotMatch":      ErrCodeAccessDenied,
+	"ExpiredToken":               ErrCodeAccessDenied,
+	"BucketNotEmpty":             ErrCodeBucketNotEmpty,
+	"PreconditionFailed":         ErrCodePreconditionFailed,
+	"InvalidArgument":            ErrCodeInvalidArgument,
+	"InvalidBucketName":          ErrCodeInvalidArgument,
+	"XMinioInvalidObjectName":    ErrCodeInvalidArgument,
+	"EntityTooLarge":             ErrCodeTooLarge,
+	"SlowDown":                   ErrCodeThrottled,
+	"ServiceUnavailable":         ErrCodeUnavailable,
+	"XMinioServerNotInitialized": ErrCodeUnavailable,
+	"RequestTimeout":             ErrCodeTimeout,
+	"InternalError":              ErrCodeInternal,
+}
+
+// Error is returned by Invoke for failures reported by MinIO.
+type Error struct {
+	Code       string
+	Operation  bindings.OperationKind
+	Bucket     string
+	Key        string
+	StatusCode int
+	Err        error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("minio binding error. %s %s: %s", e.Operation, e.Code, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// ErrorCode returns the code of a binding Error, or "" for other errors.
+func ErrorCode(err error) string {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Code
+	}
+	return ""
+}
+
+// newError classifies err. Errors that didn't come from MinIO or a deadline,
+// such as request validation, are returned unchanged.
+func newError(op bindings.OperationKind, err error) error {
+	var e *Error
+	if errors.As(err, &e) {
+		return err
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return &Error{Code: ErrCodeTimeout, Operation: op, Err: err}
+	}
+	var resp minio.ErrorResponse
+	if !errors.As(err, &resp) {
+		return err
+	}
+	return &Error{
+		Code:       errorCode(resp),
+		Operation:  op,
+		Bucket:     resp.BucketName,
+		Key:        resp.Key,
+		StatusCode: resp.StatusCode,
+		Err:        err,
+	}
+}
+
+func errorCode(resp minio.ErrorResponse) string {
+	if code, ok := errorCodes[resp.Code]; ok {
+		return code
+	}
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return ErrCodeNotFound
+	case resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusUnauthorized:
+		return ErrCodeAccessDenied
+	case resp.StatusCode == http.StatusPreconditionFailed:
+		return ErrCodePreconditionFailed
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return ErrCodeThrottled
+	case resp.StatusCode == http.StatusServiceUnavailable:
+		return ErrCodeUnavailable
+	case resp.StatusCode >= http.StatusBadRequest && resp.StatusCode < http.StatusInternalServerError:
+		return ErrCodeInvalidArgument
+	default:
+		return ErrCodeInternal
+	}
+}
@@ -0,0 +1,31 @@
+package minio
+
+import (
+	"context"
+	"fmt"
+	"github.com/dapr/components-contrib/bindings"
+	"github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNewError(t *testing.T) {
+	cause := minio.ErrorResponse{Code: "NoSuchKey", Message: "The specified key does not exist.", BucketName: "b", Key: "k", StatusCode: 404}
+	err := newError(bindings.GetOperation, fmt.Errorf("get object error: %w", cause))
+
+	assert.Equal(t, ErrCodeNotFound, ErrorCode(err))
+	var e *Error
+	assert.ErrorAs(t, err, &e)
+	assert.Equal(t, "k", e.Key)
+	assert.Equal(t, 404, e.StatusCode)
+	assert.ErrorIs(t, err, cause)
+	assert.EqualError(t, err, "minio binding error. get NotFound: get object error: The specified key does not exist.")
+
+	assert.Equal(t, ErrCodeAccessDenied, ErrorCode(newError(bindings.ListOperation, minio.ErrorResponse{Code: "Custom", StatusCode: 403})))
+	assert.Equal(t, ErrCodeInternal, ErrorCode(newError(bindings.ListOperation, minio.ErrorResponse{StatusCode: 500})))
+	assert.Equal(t, ErrCodeTimeout, ErrorCode(newError(bindings.CreateOperation, context.DeadlineExceeded)))
+
+	plain := fmt.Errorf("missing name field")
+	assert.Equal(t, plain, newError(bindings.GetOperation, plain))
+	assert.Equal(t, "", ErrorCode(plain))
+}
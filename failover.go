@@ -0,0 +1,316 @@
+package minio
+
+import (
+	"context"
+	"github.com/dapr/kit/logger"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/minio/minio-go/v7/pkg/notification"
+	"github.com/minio/minio-go/v7/pkg/sse"
+	"github.com/pkg/errors"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// FailoverCooldownKey is how long an unreachable endpoint is skipped
+	// before it is tried again.
+	FailoverCooldownKey = "failoverCooldown"
+
+	defaultFailoverCooldown = 30 * time.Second
+)
+
+// splitEndpoints parses the endpoint property, a comma-separated list of
+// endpoints with the primary first.
+func splitEndpoints(endpoint string) []string {
+	var endpoints []string
+	for _, e := range strings.Split(endpoint, ",") {
+		if e = strings.TrimSpace(e); e != "" {
+			endpoints = append(endpoints, e)
+		}
+	}
+	return endpoints
+}
+
+// newObjectClient connects to every endpoint with the options returned by
+// options. With several endpoints, requests fail over between them. The raw
+// clients are returned as well, for settings objectClient doesn't cover.
+func newObjectClient(endpoints []string, options func() *minio.Options, cooldown time.Duration, log logger.Logger) (objectClient, []*minio.Client, error) {
+	clients := make([]*minio.Client, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		client, err := minio.New(endpoint, options())
+		if err != nil {
+			return nil, nil, err
+		}
+		clients = append(clients, client)
+	}
+	if len(clients) == 1 {
+		return clientAdapter{clients[0]}, clients, nil
+	}
+
+	f := &failoverClient{cooldown: cooldown, logger: log, now: time.Now}
+	for i, client := range clients {
+		f.targets = append(f.targets, &failoverTarget{endpoint: endpoints[i], client: clientAdapter{client}})
+	}
+	return f, clients, nil
+}
+
+// newClient connects to the binding's endpoints with other credentials or
+// another region than the binding's own client.
+func (m *Minio) newClient(creds *credentials.Credentials, region string) (objectClient, []*minio.Client, error) {
+	return newRoutedClient(m.endpoints, m.readEndpoints, func() *minio.Options {
+		return &minio.Options{
+			Creds:        creds,
+			Secure:       m.secure,
+			Transport:    m.transport,
+			Region:       m.signingRegionFor(region),
+			BucketLookup: m.bucketLookup,
+		}
+	}, m.failoverCooldown, m.logger)
+}
+
+// failoverClient sends requests to the first endpoint that is up, in the
+// configured order. An endpoint that can't be reached is marked down for the
+// cooldown and the request is repeated on the next one; when all are down
+// they are tried anyway.
+type failoverClient struct {
+	targets  []*failoverTarget
+	cooldown time.Duration
+	logger   logger.Logger
+	now      func() time.Time
+}
+
+type failoverTarget struct {
+	endpoint string
+	client   objectClient
+
+	mu        sync.Mutex
+	failures  int
+	downUntil time.Time
+}
+
+func (t *failoverTarget) up(now time.Time) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return !now.Before(t.downUntil)
+}
+
+// candidates orders the endpoints that are up before the ones that are down.
+func (f *failoverClient) candidates() []*failoverTarget {
+	now := f.now()
+	up := make([]*failoverTarget, 0, len(f.targets))
+	var down []*failoverTarget
+	for _, t := range f.targets {
+		if t.up(now) {
+			up = append(up, t)
+		} else {
+			down = append(down, t)
+		}
+	}
+	return append(up, down...)
+}
+
+func (f *failoverClient) markUp(t *failoverTarget) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.failures > 0 {
+		f.logger.Infof("Minio endpoint %s is reachable again", t.endpoint)
+	}
+	t.failures = 0
+	t.downUntil = time.Time{}
+}
+
+func (f *failoverClient) markDown(t *failoverTarget, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.failures++
+	t.downUntil = f.now().Add(f.cooldown)
+	f.logger.Warnf("Minio endpoint %s is unreachable, failing over: %s", t.endpoint, err)
+}
+
+// record updates the health of t after a request to it.
+func (f *failoverClient) record(t *failoverTarget, err error) {
+	if err == nil || !isUnreachable(err) {
+		f.markUp(t)
+	} else {
+		f.markDown(t, err)
+	}
+}
+
+// do runs fn against each candidate until one answers.
+func (f *failoverClient) do(fn func(objectClient) error) error {
+	var err error
+	for _, t := range f.candidates() {
+		err = fn(t.client)
+		f.record(t, err)
+		if err == nil || !isUnreachable(err) {
+			return err
+		}
+	}
+	return err
+}
+
+// isUnreachable reports failures that mean the endpoint itself is down,
+// rather than the request being refused.
+func isUnreachable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	var resp minio.ErrorResponse
+	if errors.As(err, &resp) {
+		return resp.Code == "XMinioServerNotInitialized" || resp.StatusCode == http.StatusServiceUnavailable || resp.StatusCode == http.StatusBadGateway
+	}
+	return false
+}
+
+func (f *failoverClient) BucketExists(ctx context.Context, bucketName string) (exists bool, err error) {
+	err = f.do(func(c objectClient) error {
+		exists, err = c.BucketExists(ctx, bucketName)
+		return err
+	})
+	return exists, err
+}
+
+func (f *failoverClient) MakeBucket(ctx context.Context, bucketName string, opts minio.MakeBucketOptions) error {
+	return f.do(func(c objectClient) error {
+		return c.MakeBucket(ctx, bucketName, opts)
+	})
+}
+
+func (f *failoverClient) GetBucketEncryption(ctx context.Context, bucketName string) (config *sse.Configuration, err error) {
+	err = f.do(func(c objectClient) error {
+		config, err = c.GetBucketEncryption(ctx, bucketName)
+		return err
+	})
+	return config, err
+}
+
+func (f *failoverClient) GetBucketVersioning(ctx context.Context, bucketName string) (config minio.BucketVersioningConfiguration, err error) {
+	err = f.do(func(c objectClient) error {
+		config, err = c.GetBucketVersioning(ctx, bucketName)
+		return err
+	})
+	return config, err
+}
+
+// PutObject fails over only when the upload can be replayed, that is when
+// reader can seek back to where the upload started.
+func (f *failoverClient) PutObject(ctx context.Context, bucketName, objectName string, reader io.Reader, objectSize int64, opts minio.PutObjectOptions) (info minio.UploadInfo, err error) {
+	seeker, ok := reader.(io.Seeker)
+	var start int64
+	if ok {
+		start, err = seeker.Seek(0, io.SeekCurrent)
+	}
+	if !ok || err != nil {
+		t := f.candidates()[0]
+		info, err = t.client.PutObject(ctx, bucketName, objectName, reader, objectSize, opts)
+		f.record(t, err)
+		return info, err
+	}
+
+	err = f.do(func(c objectClient) error {
+		if _, err := seeker.Seek(start, io.SeekStart); err != nil {
+			return err
+		}
+		info, err = c.PutObject(ctx, bucketName, objectName, reader, objectSize, opts)
+		return err
+	})
+	return info, err
+}
+
+// GetObject stats the object up front, because the object reader only
+// contacts the endpoint once it is used.
+func (f *failoverClient) GetObject(ctx context.Context, bucketName, objectName string, opts minio.GetObjectOptions) (reader objectReader, err error) {
+	err = f.do(func(c objectClient) error {
+		if reader, err = c.GetObject(ctx, bucketName, objectName, opts); err != nil {
+			return err
+		}
+		if _, err := reader.Stat(); err != nil && isUnreachable(err) {
+			reader.Close()
+			return err
+		}
+		return nil
+	})
+	return reader, err
+}
+
+func (f *failoverClient) StatObject(ctx context.Context, bucketName, objectName string, opts minio.StatObjectOptions) (info minio.ObjectInfo, err error) {
+	err = f.do(func(c objectClient) error {
+		info, err = c.StatObject(ctx, bucketName, objectName, opts)
+		return err
+	})
+	return info, err
+}
+
+func (f *failoverClient) RemoveObject(ctx context.Context, bucketName, objectName string, opts minio.RemoveObjectOptions) error {
+	return f.do(func(c objectClient) error {
+		return c.RemoveObject(ctx, bucketName, objectName, opts)
+	})
+}
+
+func (f *failoverClient) RemoveObjectWithResult(ctx context.Context, bucketName, objectName string, opts minio.RemoveObjectOptions) (result minio.RemoveObjectResult, err error) {
+	err = f.do(func(c objectClient) error {
+		result, err = c.RemoveObjectWithResult(ctx, bucketName, objectName, opts)
+		return err
+	})
+	return result, err
+}
+
+func (f *failoverClient) CopyObject(ctx context.Context, dst minio.CopyDestOptions, src minio.CopySrcOptions) (info minio.UploadInfo, err error) {
+	err = f.do(func(c objectClient) error {
+		info, err = c.CopyObject(ctx, dst, src)
+		return err
+	})
+	return info, err
+}
+
+// ListObjects and ListenBucketNotification stream from the first endpoint
+// that is up; a stream broken midway is not resumed elsewhere.
+func (f *failoverClient) ListObjects(ctx context.Context, bucketName string, opts minio.ListObjectsOptions) <-chan minio.ObjectInfo {
+	return f.candidates()[0].client.ListObjects(ctx, bucketName, opts)
+}
+
+func (f *failoverClient) ListenBucketNotification(ctx context.Context, bucketName, prefix, suffix string, events []string) <-chan notification.Info {
+	return f.candidates()[0].client.ListenBucketNotification(ctx, bucketName, prefix, suffix, events)
+}
+
+func (f *failoverClient) PresignedGetObject(ctx context.Context, bucketName, objectName string, expires time.Duration, reqParams url.Values) (u *url.URL, err error) {
+	err = f.do(func(c objectClient) error {
+		u, err = c.PresignedGetObject(ctx, bucketName, objectName, expires, reqParams)
+		return err
+	})
+	return u, err
+}
+
+func (f *failoverClient) PresignedPutObject(ctx context.Context, bucketName, objectName string, expires time.Duration) (u *url.URL, err error) {
+	err = f.do(func(c objectClient) error {
+		u, err = c.PresignedPutObject(ctx, bucketName, objectName, expires)
+		return err
+	})
+	return u, err
+}
+
+func (f *failoverClient) PresignHeader(ctx context.Context, method, bucketName, objectName string, expires time.Duration, reqParams url.Values, extraHeaders http.Header) (u *url.URL, err error) {
+	err = f.do(func(c objectClient) error {
+		u, err = c.PresignHeader(ctx, method, bucketName, objectName, expires, reqParams, extraHeaders)
+		return err
+	})
+	return u, err
+}
+
+func (f *failoverClient) PresignedPostPolicy(ctx context.Context, policy *minio.PostPolicy) (u *url.URL, formData map[string]string, err error) {
+	err = f.do(func(c objectClient) error {
+		u, formData, err = c.PresignedPostPolicy(ctx, policy)
+		return err
+	})
+	return u, formData, err
+}
@@ -0,0 +1,119 @@
+package minio
+
+import (
+	"bytes"
+	"context"
+	"github.com/dapr/kit/logger"
+	"github.com/minio/minio-go/v7"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"io/ioutil"
+	"net"
+	"testing"
+	"time"
+)
+
+var errRefused = &net.OpError{Op: "dial", Net: "tcp", Err: errors.Errorf("connection refused")}
+
+// downClient is an endpoint that can't be reached. Uploads read part of the
+// payload before failing.
+type downClient struct {
+	*fakeClient
+	calls int
+}
+
+func (d *downClient) StatObject(ctx context.Context, bucketName, objectName string, opts minio.StatObjectOptions) (minio.ObjectInfo, error) {
+	d.calls++
+	return minio.ObjectInfo{}, errRefused
+}
+
+func (d *downClient) PutObject(ctx context.Context, bucketName, objectName string, reader io.Reader, objectSize int64, opts minio.PutObjectOptions) (minio.UploadInfo, error) {
+	d.calls++
+	io.CopyN(ioutil.Discard, reader, 2)
+	return minio.UploadInfo{}, errRefused
+}
+
+func newFailoverTest(primary objectClient, secondary *fakeClient) (*failoverClient, *time.Time) {
+	now := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	f := &failoverClient{
+		targets: []*failoverTarget{
+			{endpoint: "minio-0:9000", client: primary},
+			{endpoint: "minio-1:9000", client: secondary},
+		},
+		cooldown: time.Minute,
+		logger:   logger.NewLogger("minio"),
+		now:      func() time.Time { return now },
+	}
+	return f, &now
+}
+
+func TestSplitEndpoints(t *testing.T) {
+	assert.Equal(t, []string{"minio-0:9000"}, splitEndpoints("minio-0:9000"))
+	assert.Equal(t, []string{"minio-0:9000", "minio-1:9000"}, splitEndpoints(" minio-0:9000, ,minio-1:9000 "))
+	assert.Empty(t, splitEndpoints(" , "))
+}
+
+func TestFailover(t *testing.T) {
+	secondary := newFakeClient("b")
+	secondary.put("b", "a.txt", []byte("hello"), minio.ObjectInfo{})
+	primary := &downClient{fakeClient: newFakeClient("b")}
+	f, now := newFailoverTest(primary, secondary)
+
+	info, err := f.StatObject(context.Background(), "b", "a.txt", minio.StatObjectOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(5), info.Size)
+	assert.Equal(t, 1, primary.calls)
+
+	// the primary is skipped until the cooldown has passed
+	_, err = f.StatObject(context.Background(), "b", "a.txt", minio.StatObjectOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, primary.calls)
+
+	*now = now.Add(time.Minute)
+	_, err = f.StatObject(context.Background(), "b", "a.txt", minio.StatObjectOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, primary.calls)
+}
+
+func TestFailoverAllDown(t *testing.T) {
+	secondary := newFakeClient("b")
+	secondary.err = errRefused
+	primary := &downClient{fakeClient: newFakeClient("b")}
+	f, _ := newFailoverTest(primary, secondary)
+
+	_, err := f.StatObject(context.Background(), "b", "a.txt", minio.StatObjectOptions{})
+	assert.True(t, isUnreachable(err))
+
+	// endpoints that are down are still tried when none is up
+	_, err = f.StatObject(context.Background(), "b", "a.txt", minio.StatObjectOptions{})
+	assert.Error(t, err)
+	assert.Equal(t, 2, primary.calls)
+}
+
+func TestFailoverOnlyWhenUnreachable(t *testing.T) {
+	primary, secondary := newFakeClient("b"), newFakeClient("b")
+	secondary.put("b", "a.txt", []byte("hello"), minio.ObjectInfo{})
+	f, _ := newFailoverTest(primary, secondary)
+
+	// a missing object on the primary is an answer, not an outage
+	_, err := f.StatObject(context.Background(), "b", "a.txt", minio.StatObjectOptions{})
+	assert.Equal(t, ErrCodeNotFound, errorCode(minio.ToErrorResponse(err)))
+	assert.True(t, f.targets[0].up(f.now()))
+}
+
+func TestFailoverReplaysUploads(t *testing.T) {
+	secondary := newFakeClient("b")
+	primary := &downClient{fakeClient: newFakeClient("b")}
+	f, _ := newFailoverTest(primary, secondary)
+
+	_, err := f.PutObject(context.Background(), "b", "a.txt", bytes.NewReader([]byte("hello")), 5, minio.PutObjectOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, "hello", string(secondary.buckets["b"]["a.txt"].data))
+
+	// a stream can't be replayed, so it fails on the endpoint it was sent to
+	f, _ = newFailoverTest(primary, secondary)
+	_, err = f.PutObject(context.Background(), "b", "b.txt", io.MultiReader(bytes.NewReader([]byte("hello"))), 5, minio.PutObjectOptions{})
+	assert.True(t, isUnreachable(err))
+	assert.NotContains(t, secondary.buckets["b"], "b.txt")
+}
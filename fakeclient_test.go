@@ -0,0 +1,371 @@
+package minio
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"github.com/dapr/kit/logger"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/notification"
+	"github.com/minio/minio-go/v7/pkg/sse"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// fakeClient is an in-memory objectClient for unit tests.
+type fakeClient struct {
+	mu         sync.Mutex
+	buckets    map[string]map[string]fakeObject
+	versioned  bool
+	encryption *sse.Configuration
+	versions   int
+
+	// err, when set, is returned by every call
+	err error
+}
+
+type fakeObject struct {
+	data []byte
+	info minio.ObjectInfo
+}
+
+func newFakeClient(buckets ...string) *fakeClient {
+	f := &fakeClient{buckets: map[string]map[string]fakeObject{}}
+	for _, bucket := range buckets {
+		f.buckets[bucket] = map[string]fakeObject{}
+	}
+	return f
+}
+
+// newFakeMinio returns an initialized binding for bucket b backed by a fake.
+func newFakeMinio() (*Minio, *fakeClient) {
+	fake := newFakeClient("b")
+	m := NewMinio(logger.NewLogger("minio"))
+	m.minioClient = fake
+	m.Bucket = "b"
+	m.endpoint = "fake:9000"
+	m.properties = map[string]string{}
+	m.regional = &regionalClients{}
+	m.previewBytes = defaultPreviewBytes
+	m.ctx, m.cancel = context.WithCancel(context.Background())
+	return m, fake
+}
+
+func notFound(bucket, key string) error {
+	return minio.ErrorResponse{Code: "NoSuchKey", Message: "The specified key does not exist.", BucketName: bucket, Key: key, StatusCode: http.StatusNotFound}
+}
+
+func (f *fakeClient) bucket(name string) (map[string]fakeObject, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	objects, ok := f.buckets[name]
+	if !ok {
+		return nil, minio.ErrorResponse{Code: "NoSuchBucket", Message: "The specified bucket does not exist", BucketName: name, StatusCode: http.StatusNotFound}
+	}
+	return objects, nil
+}
+
+func (f *fakeClient) put(bucket, key string, data []byte, info minio.ObjectInfo) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	sum := md5.Sum(data)
+	info.Key = key
+	info.Size = int64(len(data))
+	info.ETag = hex.EncodeToString(sum[:])
+	info.LastModified = time.Now()
+	if f.versioned {
+		f.versions++
+		info.VersionID = fmt.Sprintf("v%d", f.versions)
+	}
+	f.buckets[bucket][key] = fakeObject{data: data, info: info}
+}
+
+// keys lists the objects of a bucket in order.
+func (f *fakeClient) keys(bucket string) []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	keys := []string{}
+	for key := range f.buckets[bucket] {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (f *fakeClient) BucketExists(ctx context.Context, bucketName string) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return false, f.err
+	}
+	_, ok := f.buckets[bucketName]
+	return ok, nil
+}
+
+func (f *fakeClient) MakeBucket(ctx context.Context, bucketName string, opts minio.MakeBucketOptions) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return f.err
+	}
+	if _, ok := f.buckets[bucketName]; ok {
+		return minio.ErrorResponse{Code: "BucketAlreadyOwnedByYou", BucketName: bucketName, StatusCode: http.StatusConflict}
+	}
+	f.buckets[bucketName] = map[string]fakeObject{}
+	return nil
+}
+
+func (f *fakeClient) GetBucketEncryption(ctx context.Context, bucketName string) (*sse.Configuration, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, err := f.bucket(bucketName); err != nil {
+		return nil, err
+	}
+	if f.encryption == nil {
+		return nil, minio.ErrorResponse{Code: "ServerSideEncryptionConfigurationNotFoundError", StatusCode: http.StatusNotFound}
+	}
+	return f.encryption, nil
+}
+
+func (f *fakeClient) GetBucketVersioning(ctx context.Context, bucketName string) (minio.BucketVersioningConfiguration, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, err := f.bucket(bucketName); err != nil {
+		return minio.BucketVersioningConfiguration{}, err
+	}
+	if f.versioned {
+		return minio.BucketVersioningConfiguration{Status: "Enabled"}, nil
+	}
+	return minio.BucketVersioningConfiguration{}, nil
+}
+
+func (f *fakeClient) PutObject(ctx context.Context, bucketName, objectName string, reader io.Reader, objectSize int64, opts minio.PutObjectOptions) (minio.UploadInfo, error) {
+	f.mu.Lock()
+	_, err := f.bucket(bucketName)
+	f.mu.Unlock()
+	if err != nil {
+		return minio.UploadInfo{}, err
+	}
+	if objectSize >= 0 {
+		reader = io.LimitReader(reader, objectSize)
+	}
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return minio.UploadInfo{}, err
+	}
+
+	info := minio.ObjectInfo{ContentType: opts.ContentType, Metadata: http.Header{}, UserMetadata: minio.StringMap{}}
+	if opts.ContentEncoding != "" {
+		info.Metadata.Set("Content-Encoding", opts.ContentEncoding)
+	}
+	for k, v := range opts.UserMetadata {
+		info.UserMetadata[http.CanonicalHeaderKey(k)] = v
+	}
+	f.put(bucketName, objectName, data, info)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	stored := f.buckets[bucketName][objectName].info
+	return minio.UploadInfo{Bucket: bucketName, Key: objectName, ETag: stored.ETag, Size: stored.Size, VersionID: stored.VersionID}, nil
+}
+
+func (f *fakeClient) GetObject(ctx context.Context, bucketName, objectName string, opts minio.GetObjectOptions) (objectReader, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	objects, err := f.bucket(bucketName)
+	if err != nil {
+		return nil, err
+	}
+	// like minio-go, a missing object is only reported on the first read
+	object, ok := objects[objectName]
+	if !ok {
+		return &fakeReader{err: notFound(bucketName, objectName)}, nil
+	}
+	return &fakeReader{Reader: bytes.NewReader(object.data), info: object.info}, nil
+}
+
+func (f *fakeClient) StatObject(ctx context.Context, bucketName, objectName string, opts minio.StatObjectOptions) (minio.ObjectInfo, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	objects, err := f.bucket(bucketName)
+	if err != nil {
+		return minio.ObjectInfo{}, err
+	}
+	object, ok := objects[objectName]
+	if !ok || opts.VersionID != "" && opts.VersionID != object.info.VersionID {
+		return minio.ObjectInfo{}, notFound(bucketName, objectName)
+	}
+	return object.info, nil
+}
+
+func (f *fakeClient) CopyObject(ctx context.Context, dst minio.CopyDestOptions, src minio.CopySrcOptions) (minio.UploadInfo, error) {
+	f.mu.Lock()
+	objects, err := f.bucket(src.Bucket)
+	if err == nil {
+		_, err = f.bucket(dst.Bucket)
+	}
+	source, ok := objects[src.Object]
+	f.mu.Unlock()
+	if err != nil {
+		return minio.UploadInfo{}, err
+	}
+	if !ok || src.VersionID != "" && src.VersionID != source.info.VersionID {
+		return minio.UploadInfo{}, notFound(src.Bucket, src.Object)
+	}
+
+	info := minio.ObjectInfo{ContentType: source.info.ContentType, Metadata: source.info.Metadata, UserMetadata: source.info.UserMetadata, UserTags: source.info.UserTags}
+	if dst.ReplaceMetadata {
+		info.ContentType, info.Metadata, info.UserMetadata = "", http.Header{}, minio.StringMap{}
+		for k, v := range dst.UserMetadata {
+			switch k = http.CanonicalHeaderKey(k); k {
+			case "Content-Type":
+				info.ContentType = v
+			case "Content-Encoding":
+				info.Metadata.Set(k, v)
+			default:
+				info.UserMetadata[k] = v
+			}
+		}
+	}
+	if dst.ReplaceTags {
+		info.UserTags = dst.UserTags
+	}
+	f.put(dst.Bucket, dst.Object, source.data, info)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	stored := f.buckets[dst.Bucket][dst.Object].info
+	return minio.UploadInfo{Bucket: dst.Bucket, Key: dst.Object, ETag: stored.ETag, Size: stored.Size, VersionID: stored.VersionID}, nil
+}
+
+func (f *fakeClient) RemoveObject(ctx context.Context, bucketName, objectName string, opts minio.RemoveObjectOptions) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	objects, err := f.bucket(bucketName)
+	if err != nil {
+		return err
+	}
+	delete(objects, objectName)
+	return nil
+}
+
+func (f *fakeClient) RemoveObjectWithResult(ctx context.Context, bucketName, objectName string, opts minio.RemoveObjectOptions) (minio.RemoveObjectResult, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	objects, err := f.bucket(bucketName)
+	if err != nil {
+		return minio.RemoveObjectResult{Err: err}, err
+	}
+	delete(objects, objectName)
+	result := minio.RemoveObjectResult{ObjectName: objectName, ObjectVersionID: opts.VersionID}
+	// removing the latest version of a versioned object leaves a delete marker
+	if f.versioned && opts.VersionID == "" {
+		f.versions++
+		result.DeleteMarker = true
+		result.DeleteMarkerVersionID = fmt.Sprintf("v%d", f.versions)
+	}
+	return result, nil
+}
+
+func (f *fakeClient) ListObjects(ctx context.Context, bucketName string, opts minio.ListObjectsOptions) <-chan minio.ObjectInfo {
+	ch := make(chan minio.ObjectInfo)
+	f.mu.Lock()
+	var infos []minio.ObjectInfo
+	objects, err := f.bucket(bucketName)
+	if err != nil {
+		infos = []minio.ObjectInfo{{Err: err}}
+	}
+	for key, object := range objects {
+		if strings.HasPrefix(key, opts.Prefix) && key > opts.StartAfter {
+			infos = append(infos, object.info)
+		}
+	}
+	f.mu.Unlock()
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Key < infos[j].Key })
+
+	go func() {
+		defer close(ch)
+		for _, info := range infos {
+			select {
+			case ch <- info:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch
+}
+
+func (f *fakeClient) ListenBucketNotification(ctx context.Context, bucketName, prefix, suffix string, events []string) <-chan notification.Info {
+	ch := make(chan notification.Info)
+	go func() {
+		defer close(ch)
+		<-ctx.Done()
+	}()
+	return ch
+}
+
+func (f *fakeClient) PresignedGetObject(ctx context.Context, bucketName, objectName string, expires time.Duration, reqParams url.Values) (*url.URL, error) {
+	return f.presign(http.MethodGet, bucketName, objectName, expires, reqParams)
+}
+
+func (f *fakeClient) PresignedPutObject(ctx context.Context, bucketName, objectName string, expires time.Duration) (*url.URL, error) {
+	return f.presign(http.MethodPut, bucketName, objectName, expires, nil)
+}
+
+func (f *fakeClient) PresignHeader(ctx context.Context, method, bucketName, objectName string, expires time.Duration, reqParams url.Values, extraHeaders http.Header) (*url.URL, error) {
+	return f.presign(method, bucketName, objectName, expires, reqParams)
+}
+
+// PresignedPostPolicy can't read the policy back, so its form data only
+// stands in for the real fields.
+func (f *fakeClient) PresignedPostPolicy(ctx context.Context, policy *minio.PostPolicy) (*url.URL, map[string]string, error) {
+	if f.err != nil {
+		return nil, nil, f.err
+	}
+	return &url.URL{Scheme: "http", Host: "fake:9000", Path: "/"}, map[string]string{"policy": "fake", "x-amz-signature": "post"}, nil
+}
+
+func (f *fakeClient) presign(method, bucketName, objectName string, expires time.Duration, reqParams url.Values) (*url.URL, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	query := url.Values{}
+	for k, v := range reqParams {
+		query[k] = v
+	}
+	query.Set("X-Amz-Expires", fmt.Sprint(int(expires.Seconds())))
+	query.Set("X-Amz-Signature", strings.ToLower(method))
+	return &url.URL{Scheme: "http", Host: "fake:9000", Path: "/" + bucketName + "/" + objectName, RawQuery: query.Encode()}, nil
+}
+
+type fakeReader struct {
+	*bytes.Reader
+	info minio.ObjectInfo
+	err  error
+}
+
+func (r *fakeReader) Read(b []byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	return r.Reader.Read(b)
+}
+
+func (r *fakeReader) Stat() (minio.ObjectInfo, error) {
+	if r.err != nil {
+		return minio.ObjectInfo{}, r.err
+	}
+	return r.info, nil
+}
+
+func (r *fakeReader) Close() error { return nil }
@@ -0,0 +1,81 @@
+package minio
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/dapr/components-contrib/bindings"
+	"github.com/minio/minio-go/v7"
+	"github.com/pkg/errors"
+	"strconv"
+)
+
+const (
+	// HasChangedOperation reports whether an object differs from the version
+	// a caller has seen, for cheap cache invalidation.
+	HasChangedOperation bindings.OperationKind = "hasChanged"
+	// ETagKey is the ETag the caller has seen.
+	ETagKey = "etag"
+)
+
+type hasChangedResponse struct {
+	Key     string `json:"key"`
+	Changed bool   `json:"changed"`
+	Exists  bool   `json:"exists"`
+	ETag    string `json:"etag,omitempty"`
+}
+
+// hasChanged compares the ETag of the object with the one in the request. An
+// object deleted since has changed.
+func (m *Minio) hasChanged(ctx context.Context, req *bindings.InvokeRequest) (*bindings.InvokeResponse, error) {
+	p := req.Metadata
+
+	objectName := objectNameOf(p)
+	if objectName == "" {
+		return nil, errors.Errorf("missing name field")
+	}
+	seen := p[ETagKey]
+	if seen == "" {
+		return nil, errors.Errorf("missing %s field", ETagKey)
+	}
+	client, err := m.clientFor(p)
+	if err != nil {
+		return nil, err
+	}
+	sse, err := readEncryption(m.properties, p)
+	if err != nil {
+		return nil, err
+	}
+
+	exists := true
+	var info minio.ObjectInfo
+	err = m.withRetry(ctx, func() error {
+		info, err = client.StatObject(ctx, m.Bucket, objectName, minio.StatObjectOptions{ServerSideEncryption: sse})
+		return err
+	})
+	if err != nil {
+		if errorCode(minio.ToErrorResponse(err)) != ErrCodeNotFound {
+			return nil, fmt.Errorf("minio binding error. stat: %w", err)
+		}
+		exists = false
+	}
+
+	resp := hasChangedResponse{
+		Key:     objectName,
+		Changed: !exists || trimETag(info.ETag) != trimETag(seen),
+		Exists:  exists,
+		ETag:    info.ETag,
+	}
+	jsonResponse, err := json.Marshal(resp)
+	if err != nil {
+		return nil, err
+	}
+	return &bindings.InvokeResponse{
+		Data: jsonResponse,
+		Metadata: map[string]string{
+			"key":     objectName,
+			"changed": strconv.FormatBool(resp.Changed),
+			"etag":    info.ETag,
+		},
+	}, nil
+}
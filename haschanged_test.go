@@ -0,0 +1,38 @@
+package minio
+
+import (
+	"github.com/dapr/components-contrib/bindings"
+	"github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestHasChanged(t *testing.T) {
+	m, fake := newFakeMinio()
+	fake.put("b", "a.txt", []byte("hello"), minio.ObjectInfo{})
+	etag := fake.buckets["b"]["a.txt"].info.ETag
+
+	resp, err := m.Invoke(&bindings.InvokeRequest{Operation: HasChangedOperation, Metadata: map[string]string{"key": "a.txt", "etag": `"` + etag + `"`}})
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"key":"a.txt","changed":false,"exists":true,"etag":"`+etag+`"}`, string(resp.Data))
+	assert.Equal(t, "false", resp.Metadata["changed"])
+
+	fake.put("b", "a.txt", []byte("hello again"), minio.ObjectInfo{})
+	resp, err = m.Invoke(&bindings.InvokeRequest{Operation: HasChangedOperation, Metadata: map[string]string{"key": "a.txt", "etag": etag}})
+	assert.Nil(t, err)
+	assert.Equal(t, "true", resp.Metadata["changed"])
+	assert.Equal(t, fake.buckets["b"]["a.txt"].info.ETag, resp.Metadata["etag"])
+
+	resp, err = m.Invoke(&bindings.InvokeRequest{Operation: HasChangedOperation, Metadata: map[string]string{"key": "gone.txt", "etag": etag}})
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"key":"gone.txt","changed":true,"exists":false}`, string(resp.Data))
+}
+
+func TestHasChangedValidation(t *testing.T) {
+	m, _ := newFakeMinio()
+
+	_, err := m.Invoke(&bindings.InvokeRequest{Operation: HasChangedOperation, Metadata: map[string]string{"key": "a.txt"}})
+	assert.EqualError(t, err, "missing etag field")
+	_, err = m.Invoke(&bindings.InvokeRequest{Operation: HasChangedOperation, Metadata: map[string]string{"etag": "abc"}})
+	assert.EqualError(t, err, "missing name field")
+}
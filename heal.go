@@ -0,0 +1,161 @@
+package minio
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/dapr/components-contrib/bindings"
+	"github.com/minio/madmin-go"
+	"time"
+)
+
+const (
+	HealTokenKey  = "healToken"
+	RecursiveKey  = "recursive"
+	DryRunKey     = "dryRun"
+	DeepScanKey   = "deepScan"
+	ForceStartKey = "forceStart"
+	StopKey       = "stop"
+
+	HealOperation       bindings.OperationKind = "heal"
+	HealStatusOperation bindings.OperationKind = "healStatus"
+)
+
+type healResponse struct {
+	Token     string    `json:"healToken"`
+	Bucket    string    `json:"bucket"`
+	Prefix    string    `json:"prefix,omitempty"`
+	StartTime time.Time `json:"startTime"`
+	Stopped   bool      `json:"stopped,omitempty"`
+}
+
+type healTaskResponse struct {
+	Summary       string           `json:"summary"`
+	FailureDetail string           `json:"failureDetail,omitempty"`
+	StartTime     time.Time        `json:"startTime"`
+	Items         []healItemStatus `json:"items"`
+}
+
+type healItemStatus struct {
+	Type      string `json:"type"`
+	Bucket    string `json:"bucket"`
+	Object    string `json:"object,omitempty"`
+	VersionID string `json:"versionID,omitempty"`
+	Detail    string `json:"detail,omitempty"`
+}
+
+type backgroundHealResponse struct {
+	ScannedItems  int64           `json:"scannedItems"`
+	HealingDrives []string        `json:"healingDrives"`
+	Sets          []healSetStatus `json:"sets"`
+}
+
+type healSetStatus struct {
+	Pool   int    `json:"pool"`
+	Set    int    `json:"set"`
+	Status string `json:"status"`
+}
+
+// heal starts healing the bucket, or the objects under prefix, and returns
+// the token to follow it with healStatus. With stop set it stops the heal
+// identified by healToken instead. Healing is recursive unless recursive is
+// false.
+func (m *Minio) heal(ctx context.Context, admin adminClient, p map[string]string) (*bindings.InvokeResponse, error) {
+	bucket, prefix := m.targetBucket(p), p[PrefixKey]
+	opts := madmin.HealOpts{
+		Recursive: true,
+		DryRun:    propertyToBool(p, DryRunKey),
+		ScanMode:  madmin.HealNormalScan,
+	}
+	if _, ok := p[RecursiveKey]; ok {
+		opts.Recursive = propertyToBool(p, RecursiveKey)
+	}
+	if propertyToBool(p, DeepScanKey) {
+		opts.ScanMode = madmin.HealDeepScan
+	}
+	stop := propertyToBool(p, StopKey)
+
+	start, _, err := admin.Heal(ctx, bucket, prefix, opts, p[HealTokenKey], propertyToBool(p, ForceStartKey), stop)
+	if err != nil {
+		return nil, fmt.Errorf("minio binding error. heal: %w", err)
+	}
+	token := start.ClientToken
+	if token == "" {
+		token = p[HealTokenKey]
+	}
+	jsonResponse, err := json.Marshal(healResponse{
+		Token:     token,
+		Bucket:    bucket,
+		Prefix:    prefix,
+		StartTime: start.StartTime,
+		Stopped:   stop,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &bindings.InvokeResponse{
+		Data:     jsonResponse,
+		Metadata: map[string]string{HealTokenKey: token, "bucket": bucket},
+	}, nil
+}
+
+// healStatus reports the progress of the heal identified by healToken, which
+// must be queried with the bucket and prefix it was started with. Without a
+// token it reports MinIO's background healing.
+func (m *Minio) healStatus(ctx context.Context, admin adminClient, p map[string]string) (*bindings.InvokeResponse, error) {
+	token := p[HealTokenKey]
+	if token == "" {
+		return backgroundHealStatus(ctx, admin)
+	}
+
+	_, status, err := admin.Heal(ctx, m.targetBucket(p), p[PrefixKey], madmin.HealOpts{}, token, false, false)
+	if err != nil {
+		return nil, fmt.Errorf("minio binding error. heal status: %w", err)
+	}
+	resp := healTaskResponse{
+		Summary:       status.Summary,
+		FailureDetail: status.FailureDetail,
+		StartTime:     status.StartTime,
+		Items:         make([]healItemStatus, 0, len(status.Items)),
+	}
+	for _, item := range status.Items {
+		resp.Items = append(resp.Items, healItemStatus{
+			Type:      string(item.Type),
+			Bucket:    item.Bucket,
+			Object:    item.Object,
+			VersionID: item.VersionID,
+			Detail:    item.Detail,
+		})
+	}
+	jsonResponse, err := json.Marshal(resp)
+	if err != nil {
+		return nil, err
+	}
+	return &bindings.InvokeResponse{
+		Data:     jsonResponse,
+		Metadata: map[string]string{HealTokenKey: token, "summary": status.Summary},
+	}, nil
+}
+
+func backgroundHealStatus(ctx context.Context, admin adminClient) (*bindings.InvokeResponse, error) {
+	state, err := admin.BackgroundHealStatus(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("minio binding error. background heal status: %w", err)
+	}
+	resp := backgroundHealResponse{
+		ScannedItems:  state.ScannedItemsCount,
+		HealingDrives: state.HealDisks,
+		Sets:          make([]healSetStatus, 0, len(state.Sets)),
+	}
+	if resp.HealingDrives == nil {
+		resp.HealingDrives = []string{}
+	}
+	for _, set := range state.Sets {
+		resp.Sets = append(resp.Sets, healSetStatus{Pool: set.PoolIndex, Set: set.SetIndex, Status: set.HealStatus})
+	}
+	jsonResponse, err := json.Marshal(resp)
+	if err != nil {
+		return nil, err
+	}
+	return &bindings.InvokeResponse{Data: jsonResponse}, nil
+}
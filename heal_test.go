@@ -0,0 +1,85 @@
+package minio
+
+import (
+	"github.com/dapr/components-contrib/bindings"
+	"github.com/minio/madmin-go"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestHeal(t *testing.T) {
+	m, admin := newFakeAdminMinio()
+
+	resp, err := m.Invoke(&bindings.InvokeRequest{
+		Operation: HealOperation,
+		Metadata:  map[string]string{"prefix": "docs/", "deepScan": "true"},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"healToken": "heal-1", "bucket": "b"}, resp.Metadata)
+	assert.Equal(t, fakeHeal{
+		bucket: "b",
+		prefix: "docs/",
+		opts:   madmin.HealOpts{Recursive: true, ScanMode: madmin.HealDeepScan},
+	}, admin.heals[0])
+
+	_, err = m.Invoke(&bindings.InvokeRequest{
+		Operation: HealOperation,
+		Metadata:  map[string]string{"bucket": "tenant-a", "recursive": "false", "dryRun": "true", "forceStart": "true"},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, fakeHeal{
+		bucket:     "tenant-a",
+		opts:       madmin.HealOpts{DryRun: true, ScanMode: madmin.HealNormalScan},
+		forceStart: true,
+	}, admin.heals[1])
+
+	resp, err = m.Invoke(&bindings.InvokeRequest{
+		Operation: HealOperation,
+		Metadata:  map[string]string{"healToken": "heal-1", "prefix": "docs/", "stop": "true"},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "heal-1", resp.Metadata["healToken"])
+	assert.True(t, admin.heals[2].forceStop)
+	assert.Equal(t, "heal-1", admin.heals[2].token)
+}
+
+func TestHealStatus(t *testing.T) {
+	m, admin := newFakeAdminMinio()
+	admin.healStatus = madmin.HealTaskStatus{
+		Summary: "running",
+		Items: []madmin.HealResultItem{
+			{Type: "object", Bucket: "b", Object: "docs/a.txt", Detail: "healed"},
+		},
+	}
+
+	resp, err := m.Invoke(&bindings.InvokeRequest{
+		Operation: HealStatusOperation,
+		Metadata:  map[string]string{"healToken": "heal-1", "prefix": "docs/"},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "running", resp.Metadata["summary"])
+	assert.JSONEq(t, `{
+		"summary": "running",
+		"startTime": "0001-01-01T00:00:00Z",
+		"items": [{"type": "object", "bucket": "b", "object": "docs/a.txt", "detail": "healed"}]
+	}`, string(resp.Data))
+	assert.Equal(t, fakeHeal{bucket: "b", prefix: "docs/", token: "heal-1"}, admin.heals[0])
+}
+
+func TestBackgroundHealStatus(t *testing.T) {
+	m, admin := newFakeAdminMinio()
+	admin.background = madmin.BgHealState{
+		ScannedItemsCount: 42,
+		HealDisks:         []string{"minio-0:9000/data2"},
+		Sets:              []madmin.SetStatus{{PoolIndex: 0, SetIndex: 1, HealStatus: "healing"}},
+	}
+
+	resp, err := m.Invoke(&bindings.InvokeRequest{Operation: HealStatusOperation})
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{
+		"scannedItems": 42,
+		"healingDrives": ["minio-0:9000/data2"],
+		"sets": [{"pool": 0, "set": 1, "status": "healing"}]
+	}`, string(resp.Data))
+	assert.Empty(t, admin.heals)
+}
@@ -0,0 +1,37 @@
+package minio
+
+import (
+	"context"
+	"fmt"
+	"github.com/pkg/errors"
+	"time"
+)
+
+const pingTimeout = 5 * time.Second
+
+// Ping reports whether MinIO is reachable and the bucket exists, for the Dapr
+// component health check.
+func (m *Minio) Ping() error {
+	m.mu.RLock()
+	client, bucket, p := m.minioClient, m.Bucket, m.properties
+	m.mu.RUnlock()
+	if client == nil {
+		return errors.Errorf("minio binding error. binding is not initialized")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), pingTimeout)
+	defer cancel()
+	exists, err := client.BucketExists(ctx, bucket)
+	if err != nil {
+		// anonymous clients usually may not query the bucket, but an
+		// access denied answer still proves the server is up
+		if propertyToBool(p, AnonymousKey) && ErrorCode(newError("ping", err)) == ErrCodeAccessDenied {
+			return nil
+		}
+		return fmt.Errorf("minio binding error. ping: %w", err)
+	}
+	if !exists {
+		return errors.Errorf("minio binding error. bucket %s does not exist", bucket)
+	}
+	return nil
+}
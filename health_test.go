@@ -0,0 +1,12 @@
+package minio
+
+import (
+	"github.com/dapr/kit/logger"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestPingBeforeInit(t *testing.T) {
+	m := NewMinio(logger.NewLogger("minio"))
+	assert.EqualError(t, m.Ping(), "minio binding error. binding is not initialized")
+}
@@ -0,0 +1,582 @@
+package minio
+
+import (
+	"context"
+	"crypto/cipher"
+	"encoding/json"
+	"fmt"
+	"github.com/dapr/components-contrib/bindings"
+	"github.com/dapr/kit/logger"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/notification"
+	"github.com/pkg/errors"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	InputModeKey    = "inputMode"
+	PollIntervalKey = "pollInterval"
+	PollPrefixKey   = "pollPrefix"
+
+	NotificationPrefixKey = "notificationPrefix"
+	NotificationSuffixKey = "notificationSuffix"
+	EventsKey             = "events"
+
+	ReconnectBackoffKey    = "reconnectBackoff"
+	ReconnectMaxBackoffKey = "reconnectMaxBackoff"
+
+	FetchObjectOnEventKey = "fetchObjectOnEvent"
+	FetchMaxSizeKey       = "fetchMaxSize"
+
+	DeliveryRetriesKey  = "deliveryRetries"
+	DeliveryBackoffKey  = "deliveryBackoff"
+	DeadLetterPrefixKey = "deadLetterPrefix"
+	DeadLetterBucketKey = "deadLetterBucket"
+
+	InputModeListen = "listen"
+	InputModePoll   = "poll"
+
+	defaultPollInterval        = 30 * time.Second
+	defaultReconnectBackoff    = time.Second
+	defaultReconnectMaxBackoff = time.Minute
+	defaultFetchMaxSize        = 10 << 20
+	defaultDeliveryBackoff     = time.Second
+
+	objectCreatedEvent = "s3:ObjectCreated:Put"
+	objectRemovedEvent = "s3:ObjectRemoved:Delete"
+)
+
+var _ = bindings.InputBinding(&Minio{})
+
+var defaultNotificationEvents = []string{
+	"s3:ObjectCreated:*",
+	"s3:ObjectRemoved:*",
+}
+
+type inputConfig struct {
+	mode         string
+	pollInterval time.Duration
+	pollPrefix   string
+	prefix       string
+	suffix       string
+	events       []string
+
+	reconnectBackoff    time.Duration
+	reconnectMaxBackoff time.Duration
+
+	fetchObject  bool
+	fetchMaxSize int64
+
+	deliveryRetries  int64
+	deliveryBackoff  time.Duration
+	deadLetterPrefix string
+	deadLetterBucket string
+
+	checkpointInterval time.Duration
+
+	publishPubsubName string
+	publishTopic      string
+}
+
+func parseInputConfig(p map[string]string) (inputConfig, error) {
+	cfg := inputConfig{
+		mode:         InputModeListen,
+		pollInterval: defaultPollInterval,
+		pollPrefix:   p[PollPrefixKey],
+		prefix:       p[NotificationPrefixKey],
+		suffix:       p[NotificationSuffixKey],
+		events:       defaultNotificationEvents,
+
+		reconnectBackoff:    defaultReconnectBackoff,
+		reconnectMaxBackoff: defaultReconnectMaxBackoff,
+
+		fetchObject:  propertyToBool(p, FetchObjectOnEventKey),
+		fetchMaxSize: defaultFetchMaxSize,
+
+		deliveryBackoff:  defaultDeliveryBackoff,
+		deadLetterPrefix: p[DeadLetterPrefixKey],
+		deadLetterBucket: p[DeadLetterBucketKey],
+
+		checkpointInterval: defaultCheckpointInterval,
+
+		publishPubsubName: p[PublishPubsubNameKey],
+		publishTopic:      p[PublishTopicKey],
+	}
+	if cfg.pollPrefix == "" {
+		cfg.pollPrefix = cfg.prefix
+	}
+	if v := p[EventsKey]; v != "" {
+		cfg.events = nil
+		for _, event := range strings.Split(v, ",") {
+			if event = strings.TrimSpace(event); event != "" {
+				cfg.events = append(cfg.events, event)
+			}
+		}
+	}
+	switch mode := p[InputModeKey]; mode {
+	case "", InputModeListen:
+	case InputModePoll:
+		cfg.mode = mode
+	default:
+		return cfg, errors.Errorf("unsupported Minio inputMode %s", mode)
+	}
+	var err error
+	if cfg.pollInterval, err = durationProperty(p, PollIntervalKey, cfg.pollInterval); err != nil {
+		return cfg, err
+	}
+	if cfg.reconnectBackoff, err = durationProperty(p, ReconnectBackoffKey, cfg.reconnectBackoff); err != nil {
+		return cfg, err
+	}
+	if cfg.reconnectMaxBackoff, err = durationProperty(p, ReconnectMaxBackoffKey, cfg.reconnectMaxBackoff); err != nil {
+		return cfg, err
+	}
+	if cfg.fetchMaxSize, err = sizeProperty(p, FetchMaxSizeKey, cfg.fetchMaxSize); err != nil {
+		return cfg, err
+	}
+	if cfg.deliveryRetries, err = sizeProperty(p, DeliveryRetriesKey, 0); err != nil {
+		return cfg, err
+	}
+	if cfg.deliveryBackoff, err = durationProperty(p, DeliveryBackoffKey, cfg.deliveryBackoff); err != nil {
+		return cfg, err
+	}
+	if cfg.checkpointInterval, err = durationProperty(p, CheckpointIntervalKey, cfg.checkpointInterval); err != nil {
+		return cfg, err
+	}
+	if cfg.reconnectMaxBackoff < cfg.reconnectBackoff {
+		cfg.reconnectMaxBackoff = cfg.reconnectBackoff
+	}
+	return cfg, nil
+}
+
+// matches applies the prefix, suffix and event type filters. MinIO already
+// filters what it sends; polling relies on this alone.
+func (cfg inputConfig) matches(event notification.Event) bool {
+	key := eventKey(event)
+	if !strings.HasPrefix(key, cfg.prefix) || !strings.HasSuffix(key, cfg.suffix) {
+		return false
+	}
+	for _, pattern := range cfg.events {
+		if pattern == event.EventName ||
+			strings.HasSuffix(pattern, "*") && strings.HasPrefix(event.EventName, strings.TrimSuffix(pattern, "*")) {
+			return true
+		}
+	}
+	return false
+}
+
+// Read delivers bucket events to the app until the binding is closed, either
+// from MinIO's notification API or, for backends without it, by polling.
+// With a checkpoint, objects written while the binding was down are replayed
+// first. In listen mode, objects written between that replay and the
+// subscription taking effect are not, because MinIO doesn't replay
+// notifications; polling starts from the replay's listing and misses none.
+// A re-Init stops reading: Read then logs an error and returns one, and must
+// be called again to read with the new configuration.
+func (m *Minio) Read(handler func(*bindings.ReadResponse) ([]byte, error)) error {
+	r, err := m.newReader()
+	if err != nil {
+		return err
+	}
+	defer r.flushCheckpoint()
+
+	snapshot, err := r.catchUp(func(event notification.Event) {
+		r.dispatch(handler, event)
+	})
+	if err != nil {
+		return err
+	}
+	if r.input.mode == InputModePoll {
+		err = r.poll(handler, snapshot)
+	} else {
+		err = r.listen(handler)
+	}
+	if err == nil && m.reinitialized(r.ctx) {
+		r.logger.Errorf("Minio binding stopped reading bucket %s because the component was re-initialized, Read must be called again", r.bucket)
+		return errors.Errorf("minio binding error. input stopped by re-initialization")
+	}
+	return err
+}
+
+// reader is the configuration a Read runs with. It is copied when Read
+// starts, so that a concurrent Init swaps the binding's state without
+// changing it under a running Read.
+type reader struct {
+	ctx          context.Context
+	client       objectClient
+	bucket       string
+	input        inputConfig
+	logger       logger.Logger
+	properties   map[string]string
+	clientCipher cipher.AEAD
+	audit        auditConfig
+	checkpoints  CheckpointStore
+	publisher    Publisher
+
+	// checkpointHeld is set once an event was neither delivered nor
+	// dead-lettered
+	checkpointHeld bool
+	// pendingCheckpoint is the checkpoint not written yet, if any
+	pendingCheckpoint *Checkpoint
+	checkpointSaved   time.Time
+}
+
+func (m *Minio) newReader() (*reader, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.ctx == nil || m.minioClient == nil {
+		return nil, errors.Errorf("minio binding error. binding is not initialized")
+	}
+	return &reader{
+		ctx:          m.ctx,
+		client:       m.minioClient,
+		bucket:       m.Bucket,
+		input:        m.input,
+		logger:       m.logger,
+		properties:   m.properties,
+		clientCipher: m.clientCipher,
+		audit:        m.audit,
+		checkpoints:  m.checkpoints,
+		publisher:    m.publisher,
+	}, nil
+}
+
+// reinitialized reports whether Init has replaced the context a Read
+// started with, rather than Close cancelling it.
+func (m *Minio) reinitialized(ctx context.Context) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.ctx != ctx
+}
+
+// listen subscribes to bucket notifications and re-subscribes with
+// exponential backoff and jitter whenever the stream drops, e.g. while MinIO
+// restarts. Events raised while disconnected are not replayed by MinIO.
+func (r *reader) listen(handler func(*bindings.ReadResponse) ([]byte, error)) error {
+	r.logger.Infof("Minio binding listening for notifications on bucket %s", r.bucket)
+	// checkpoints held back by checkpointInterval are written once it passes
+	var flush <-chan time.Time
+	if r.checkpoints != nil && r.input.checkpointInterval > 0 {
+		ticker := time.NewTicker(r.input.checkpointInterval)
+		defer ticker.Stop()
+		flush = ticker.C
+	}
+	backoff := r.input.reconnectBackoff
+	var disconnectedAt time.Time
+	for {
+		if !disconnectedAt.IsZero() {
+			r.logger.Warnf("Minio binding re-subscribing to bucket %s, events since %s may have been missed", r.bucket, disconnectedAt.Format(time.RFC3339))
+		}
+
+		stream, cancel := context.WithCancel(r.ctx)
+		notifications := r.client.ListenBucketNotification(stream, r.bucket, r.input.prefix, r.input.suffix, r.input.events)
+		for {
+			var info notification.Info
+			var ok bool
+			select {
+			case info, ok = <-notifications:
+			case <-flush:
+				r.flushCheckpoint()
+				continue
+			}
+			if !ok {
+				break
+			}
+			if info.Err != nil {
+				r.logger.Warnf("Minio binding notification stream error: %s", info.Err)
+				break
+			}
+			disconnectedAt = time.Time{}
+			backoff = r.input.reconnectBackoff
+			for _, event := range info.Records {
+				r.dispatch(handler, event)
+			}
+		}
+		cancel()
+
+		if r.ctx.Err() != nil {
+			return nil
+		}
+		if disconnectedAt.IsZero() {
+			disconnectedAt = time.Now()
+		}
+		wait := withJitter(backoff)
+		r.logger.Warnf("Minio binding notification stream closed, reconnecting in %s", wait)
+		select {
+		case <-r.ctx.Done():
+			return nil
+		case <-time.After(wait):
+		}
+		if backoff *= 2; backoff > r.input.reconnectMaxBackoff {
+			backoff = r.input.reconnectMaxBackoff
+		}
+	}
+}
+
+// withJitter spreads reconnect attempts over [d/2, d).
+func withJitter(d time.Duration) time.Duration {
+	half := d / 2
+	if half <= 0 {
+		return d
+	}
+	return half + time.Duration(rand.Int63n(int64(half)))
+}
+
+// poll lists the prefix every pollInterval and emits created/removed events
+// for the differences to the previous listing, starting from previous when
+// it is set.
+func (r *reader) poll(handler func(*bindings.ReadResponse) ([]byte, error), previous objectSnapshot) error {
+	r.logger.Infof("Minio binding polling bucket %s every %s", r.bucket, r.input.pollInterval)
+	if previous == nil {
+		var err error
+		if previous, err = r.snapshot(); err != nil {
+			return err
+		}
+	}
+
+	ticker := time.NewTicker(r.input.pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		current, err := r.snapshot()
+		if err != nil {
+			r.logger.Warnf("Minio binding poll of bucket %s failed: %s", r.bucket, err)
+			continue
+		}
+		for _, event := range diffSnapshots(r.bucket, previous, current) {
+			r.dispatch(handler, event)
+		}
+		r.flushCheckpoint()
+		previous = current
+	}
+}
+
+type objectSnapshot map[string]minio.ObjectInfo
+
+func (r *reader) snapshot() (objectSnapshot, error) {
+	snapshot := objectSnapshot{}
+	for object := range r.client.ListObjects(r.ctx, r.bucket, minio.ListObjectsOptions{
+		Prefix:    r.input.pollPrefix,
+		Recursive: true,
+	}) {
+		if object.Err != nil {
+			return nil, fmt.Errorf("minio binding error. poll list: %w", object.Err)
+		}
+		snapshot[object.Key] = object
+	}
+	return snapshot, nil
+}
+
+// diffSnapshots reports new or rewritten objects as created and missing ones
+// as removed, ordered by key.
+func diffSnapshots(bucket string, previous, current objectSnapshot) []notification.Event {
+	var events []notification.Event
+	for _, key := range sortedKeys(current) {
+		object := current[key]
+		if old, ok := previous[key]; !ok || old.ETag != object.ETag {
+			events = append(events, newEvent(objectCreatedEvent, bucket, object))
+		}
+	}
+	for _, key := range sortedKeys(previous) {
+		if _, ok := current[key]; !ok {
+			events = append(events, newEvent(objectRemovedEvent, bucket, minio.ObjectInfo{Key: key}))
+		}
+	}
+	return events
+}
+
+func sortedKeys(snapshot objectSnapshot) []string {
+	keys := make([]string, 0, len(snapshot))
+	for key := range snapshot {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// newEvent builds an event shaped like the ones MinIO sends, so both input
+// modes deliver the same payload.
+func newEvent(name, bucket string, object minio.ObjectInfo) notification.Event {
+	var event notification.Event
+	event.EventVersion = "2.0"
+	event.EventSource = "minio:s3"
+	event.EventName = name
+	event.EventTime = time.Now().UTC().Format(time.RFC3339Nano)
+	event.S3.Bucket.Name = bucket
+	event.S3.Object.Key = url.QueryEscape(object.Key)
+	event.S3.Object.Size = object.Size
+	event.S3.Object.ETag = object.ETag
+	event.S3.Object.ContentType = object.ContentType
+	event.S3.Object.VersionID = object.VersionID
+	return event
+}
+
+func (r *reader) dispatch(handler func(*bindings.ReadResponse) ([]byte, error), event notification.Event) {
+	if !r.input.matches(event) || r.isControlObject(eventKey(event)) {
+		return
+	}
+	// the event is published once, before the app handles it, so retries of
+	// the handler don't republish it. A publish is retried on its own and
+	// doesn't fail the delivery, so an event the app handled is never
+	// dead-lettered for it
+	if err := r.publishWithRetry(event); err != nil {
+		r.logger.Errorf("Minio binding error publishing %s for %s: %s", event.EventName, eventKey(event), err)
+	}
+	err := r.deliverWithRetry(handler, event)
+	if err != nil {
+		r.logger.Errorf("Minio binding error delivering %s for %s: %s", event.EventName, eventKey(event), err)
+		if r.input.deadLetterPrefix == "" {
+			r.holdCheckpoint(event)
+			return
+		}
+		if err := r.deadLetter(event, err); err != nil {
+			r.logger.Errorf("Minio binding failed to dead-letter %s: %s", eventKey(event), err)
+			r.holdCheckpoint(event)
+			return
+		}
+	}
+	r.saveCheckpoint(event)
+}
+
+// deliverWithRetry retries a failing handler deliveryRetries times, doubling
+// the delay between attempts.
+func (r *reader) deliverWithRetry(handler func(*bindings.ReadResponse) ([]byte, error), event notification.Event) error {
+	return r.retry("delivery", event, func() error {
+		return r.deliver(handler, event)
+	})
+}
+
+// publishWithRetry retries a failing publish the way deliverWithRetry
+// retries the handler.
+func (r *reader) publishWithRetry(event notification.Event) error {
+	return r.retry("publish", event, func() error {
+		return r.publish(event)
+	})
+}
+
+func (r *reader) retry(what string, event notification.Event, fn func() error) error {
+	backoff := r.input.deliveryBackoff
+	for attempt := int64(0); ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= r.input.deliveryRetries {
+			return err
+		}
+		r.logger.Warnf("Minio binding %s of %s failed (attempt %d): %s", what, eventKey(event), attempt+1, err)
+		select {
+		case <-r.ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// isControlObject reports objects the binding writes itself, which must not
+// be delivered back to the app.
+func (r *reader) isControlObject(key string) bool {
+	if key == r.checkpointObjectKey() {
+		return true
+	}
+	if r.audit.target == AuditLogBucket && strings.HasPrefix(key, r.audit.prefix) {
+		return true
+	}
+	prefix := r.input.deadLetterPrefix
+	return prefix != "" && r.deadLetterBucket() == r.bucket && strings.HasPrefix(key, prefix)
+}
+
+func (r *reader) deliver(handler func(*bindings.ReadResponse) ([]byte, error), event notification.Event) error {
+	resp, err := r.readResponse(event)
+	if err != nil {
+		return err
+	}
+	_, err = handler(resp)
+	return err
+}
+
+// readResponse carries the event as JSON, or with fetchObjectOnEvent the
+// created object's content plus the event metadata.
+func (r *reader) readResponse(event notification.Event) (*bindings.ReadResponse, error) {
+	md := eventMetadata(event)
+	if r.input.fetchObject && strings.HasPrefix(event.EventName, "s3:ObjectCreated:") {
+		if event.S3.Object.Size > r.input.fetchMaxSize {
+			r.logger.Warnf("Minio binding not fetching %s: size %d exceeds %s", md["key"], event.S3.Object.Size, FetchMaxSizeKey)
+		} else {
+			data, contentType, err := r.fetchObject(md["key"])
+			if err != nil {
+				return nil, err
+			}
+			md["fetched"] = "true"
+			md["contentType"] = contentType
+			return &bindings.ReadResponse{Data: data, Metadata: md}, nil
+		}
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("minio binding error. cannot marshal event to json: %w", err)
+	}
+	return &bindings.ReadResponse{Data: data, Metadata: md}, nil
+}
+
+func (r *reader) fetchObject(key string) ([]byte, string, error) {
+	sse, err := readEncryption(r.properties, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	object, err := r.client.GetObject(r.ctx, r.bucket, key, minio.GetObjectOptions{ServerSideEncryption: sse})
+	if err != nil {
+		return nil, "", fmt.Errorf("minio binding error. fetch object: %w", err)
+	}
+	defer object.Close()
+
+	stat, err := object.Stat()
+	if err != nil {
+		return nil, "", fmt.Errorf("minio binding error. fetch object: %w", err)
+	}
+	// the object may have been replaced since the event was raised
+	if stat.Size > r.input.fetchMaxSize {
+		return nil, "", errors.Errorf("minio binding error. object %s size %d exceeds %s", key, stat.Size, FetchMaxSizeKey)
+	}
+	data, err := ioutil.ReadAll(io.LimitReader(object, stat.Size))
+	if err != nil {
+		return nil, "", fmt.Errorf("minio binding error. fetch object: %w", err)
+	}
+	if isClientEncrypted(stat) {
+		if data, err = decryptObject(r.clientCipher, data); err != nil {
+			return nil, "", err
+		}
+	}
+	return data, stat.ContentType, nil
+}
+
+func eventMetadata(event notification.Event) map[string]string {
+	return map[string]string{
+		"eventName": event.EventName,
+		"eventTime": event.EventTime,
+		"bucket":    event.S3.Bucket.Name,
+		"key":       eventKey(event),
+		"size":      strconv.FormatInt(event.S3.Object.Size, 10),
+		"etag":      event.S3.Object.ETag,
+		"versionID": event.S3.Object.VersionID,
+	}
+}
+
+// eventKey returns the object key, which notifications carry URL-encoded.
+func eventKey(event notification.Event) string {
+	key, err := url.QueryUnescape(event.S3.Object.Key)
+	if err != nil {
+		return event.S3.Object.Key
+	}
+	return key
+}
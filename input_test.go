@@ -0,0 +1,181 @@
+package minio
+
+import (
+	"context"
+	"github.com/dapr/components-contrib/bindings"
+	"github.com/dapr/kit/logger"
+	"github.com/minio/minio-go/v7/pkg/notification"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestEventMetadata(t *testing.T) {
+	var event notification.Event
+	event.EventName = "s3:ObjectCreated:Put"
+	event.S3.Bucket.Name = "fos"
+	event.S3.Object.Key = "uploads%2Fmy+file.txt"
+	event.S3.Object.Size = 12
+
+	md := eventMetadata(event)
+	assert.Equal(t, "s3:ObjectCreated:Put", md["eventName"])
+	assert.Equal(t, "fos", md["bucket"])
+	assert.Equal(t, "uploads/my file.txt", md["key"])
+	assert.Equal(t, "12", md["size"])
+}
+
+func TestParseInputConfig(t *testing.T) {
+	cfg, err := parseInputConfig(map[string]string{})
+	assert.NoError(t, err)
+	assert.Equal(t, InputModeListen, cfg.mode)
+	assert.Equal(t, defaultPollInterval, cfg.pollInterval)
+
+	cfg, err = parseInputConfig(map[string]string{InputModeKey: InputModePoll, PollIntervalKey: "5s", PollPrefixKey: "in/"})
+	assert.NoError(t, err)
+	assert.Equal(t, InputModePoll, cfg.mode)
+	assert.Equal(t, 5*time.Second, cfg.pollInterval)
+	assert.Equal(t, "in/", cfg.pollPrefix)
+
+	_, err = parseInputConfig(map[string]string{PollIntervalKey: "soon"})
+	assert.EqualError(t, err, "pollInterval soon is invalid")
+
+	cfg, err = parseInputConfig(map[string]string{FetchObjectOnEventKey: "true", FetchMaxSizeKey: "1024"})
+	assert.NoError(t, err)
+	assert.True(t, cfg.fetchObject)
+	assert.Equal(t, int64(1024), cfg.fetchMaxSize)
+
+	_, err = parseInputConfig(map[string]string{FetchMaxSizeKey: "-1"})
+	assert.EqualError(t, err, "fetchMaxSize -1 is invalid")
+
+	cfg, err = parseInputConfig(map[string]string{ReconnectBackoffKey: "2s", ReconnectMaxBackoffKey: "1s"})
+	assert.NoError(t, err)
+	assert.Equal(t, 2*time.Second, cfg.reconnectBackoff)
+	assert.Equal(t, 2*time.Second, cfg.reconnectMaxBackoff)
+}
+
+func TestWithJitter(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		d := withJitter(time.Second)
+		assert.True(t, d >= 500*time.Millisecond && d < time.Second)
+	}
+}
+
+func TestDiffSnapshots(t *testing.T) {
+	previous := objectSnapshot{
+		"a.txt": {Key: "a.txt", ETag: "1"},
+		"b.txt": {Key: "b.txt", ETag: "1"},
+		"c.txt": {Key: "c.txt", ETag: "1"},
+	}
+	current := objectSnapshot{
+		"a.txt": {Key: "a.txt", ETag: "1"},
+		"b.txt": {Key: "b.txt", ETag: "2"},
+		"d.txt": {Key: "d.txt", ETag: "1", Size: 4},
+	}
+
+	events := diffSnapshots("fos", previous, current)
+	assert.Len(t, events, 3)
+	assert.Equal(t, objectCreatedEvent, events[0].EventName)
+	assert.Equal(t, "b.txt", eventKey(events[0]))
+	assert.Equal(t, objectCreatedEvent, events[1].EventName)
+	assert.Equal(t, "d.txt", eventKey(events[1]))
+	assert.Equal(t, int64(4), events[1].S3.Object.Size)
+	assert.Equal(t, objectRemovedEvent, events[2].EventName)
+	assert.Equal(t, "c.txt", eventKey(events[2]))
+	assert.Equal(t, "fos", events[2].S3.Bucket.Name)
+
+	assert.Empty(t, diffSnapshots("fos", current, current))
+}
+
+func TestInputConfigMatches(t *testing.T) {
+	cfg, err := parseInputConfig(map[string]string{
+		NotificationPrefixKey: "photos/",
+		NotificationSuffixKey: ".jpg",
+		EventsKey:             "s3:ObjectCreated:Put, s3:ObjectRemoved:*",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "photos/", cfg.pollPrefix)
+
+	event := func(name, key string) notification.Event {
+		var e notification.Event
+		e.EventName = name
+		e.S3.Object.Key = key
+		return e
+	}
+	assert.True(t, cfg.matches(event("s3:ObjectCreated:Put", "photos/cat.jpg")))
+	assert.True(t, cfg.matches(event("s3:ObjectRemoved:Delete", "photos/cat.jpg")))
+	assert.False(t, cfg.matches(event("s3:ObjectCreated:Copy", "photos/cat.jpg")))
+	assert.False(t, cfg.matches(event("s3:ObjectCreated:Put", "photos/cat.png")))
+	assert.False(t, cfg.matches(event("s3:ObjectCreated:Put", "videos/cat.jpg")))
+}
+
+func TestReadStopsOnReinit(t *testing.T) {
+	handler := func(*bindings.ReadResponse) ([]byte, error) { return nil, nil }
+	for _, mode := range []string{InputModeListen, InputModePoll} {
+		t.Run(mode, func(t *testing.T) {
+			m, _ := newFakeMinio()
+			m.input = inputConfig{mode: mode, events: defaultNotificationEvents, pollInterval: time.Hour}
+
+			done := make(chan error, 1)
+			go func() { done <- m.Read(handler) }()
+			time.Sleep(50 * time.Millisecond)
+
+			// what Init does to the context of a running binding
+			m.mu.Lock()
+			m.cancel()
+			m.ctx, m.cancel = context.WithCancel(context.Background())
+			m.mu.Unlock()
+
+			select {
+			case err := <-done:
+				assert.Error(t, err)
+			case <-time.After(time.Second):
+				t.Fatal("Read kept running after re-init")
+			}
+
+			go func() { done <- m.Read(handler) }()
+			time.Sleep(50 * time.Millisecond)
+			assert.NoError(t, m.Close())
+			select {
+			case err := <-done:
+				assert.NoError(t, err)
+			case <-time.After(time.Second):
+				t.Fatal("Read kept running after Close")
+			}
+		})
+	}
+}
+
+func TestReadBeforeInit(t *testing.T) {
+	m := NewMinio(logger.NewLogger("test"))
+	err := m.Read(func(*bindings.ReadResponse) ([]byte, error) { return nil, nil })
+	assert.EqualError(t, err, "minio binding error. binding is not initialized")
+}
+
+// TestReadDuringReinit swaps the binding's state the way Init does while
+// Read keeps polling; run with -race to check Read doesn't share it.
+func TestReadDuringReinit(t *testing.T) {
+	m, _ := newFakeMinio()
+	m.input = inputConfig{mode: InputModePoll, events: defaultNotificationEvents, pollInterval: time.Millisecond}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- m.Read(func(*bindings.ReadResponse) ([]byte, error) { return nil, nil })
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	m.mu.Lock()
+	m.cancel()
+	m.minioClient = newFakeClient("c")
+	m.Bucket = "c"
+	m.input = inputConfig{mode: InputModeListen}
+	m.ctx, m.cancel = context.WithCancel(context.Background())
+	m.mu.Unlock()
+
+	select {
+	case err := <-done:
+		assert.EqualError(t, err, "minio binding error. input stopped by re-initialization")
+	case <-time.After(time.Second):
+		t.Fatal("Read kept running after re-init")
+	}
+	assert.NoError(t, m.Close())
+}
@@ -0,0 +1,201 @@
+//go:build integration
+// +build integration
+
+package minio
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/dapr/components-contrib/bindings"
+	"github.com/dapr/kit/logger"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+)
+
+// The integration tests run against a throwaway MinIO container:
+//
+//	go test -tags integration ./...
+//
+// MINIO_IMAGE overrides the image, e.g. to test against another release.
+const (
+	defaultIntegrationImage = "minio/minio:RELEASE.2021-10-13T00-23-17Z"
+	integrationAccessKey    = "minioadmin"
+	integrationSecretKey    = "minioadmin"
+)
+
+var integrationEndpoint string
+
+func TestMain(m *testing.M) {
+	ctx := context.Background()
+	image := os.Getenv("MINIO_IMAGE")
+	if image == "" {
+		image = defaultIntegrationImage
+	}
+	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{
+			Image:        image,
+			ExposedPorts: []string{"9000/tcp"},
+			Env: map[string]string{
+				"MINIO_ROOT_USER":     integrationAccessKey,
+				"MINIO_ROOT_PASSWORD": integrationSecretKey,
+			},
+			Cmd:        []string{"server", "/data"},
+			WaitingFor: wait.ForHTTP("/minio/health/live").WithPort("9000/tcp").WithStartupTimeout(time.Minute),
+		},
+		Started: true,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "cannot start MinIO container: %s\n", err)
+		os.Exit(1)
+	}
+	host, err := container.Host(ctx)
+	if err == nil {
+		port, perr := container.MappedPort(ctx, "9000")
+		integrationEndpoint, err = host+":"+port.Port(), perr
+	}
+
+	code := 1
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "cannot resolve MinIO endpoint: %s\n", err)
+	} else {
+		code = m.Run()
+	}
+	if err := container.Terminate(ctx); err != nil {
+		fmt.Fprintf(os.Stderr, "cannot terminate MinIO container: %s\n", err)
+	}
+	os.Exit(code)
+}
+
+// newIntegrationMinio initializes a binding on a fresh bucket, which Init
+// creates.
+func newIntegrationMinio(t *testing.T, properties map[string]string) *Minio {
+	p := map[string]string{
+		Endpoint:        integrationEndpoint,
+		AccessKey:       integrationAccessKey,
+		SecretAccessKey: integrationSecretKey,
+		SSLKey:          "false",
+		BucketKey:       "test-" + uuid.New().String()[:8],
+	}
+	for k, v := range properties {
+		p[k] = v
+	}
+	m := NewMinio(logger.NewLogger("minio"))
+	require.NoError(t, m.Init(bindings.Metadata{Properties: p}))
+	t.Cleanup(func() { m.Close() })
+	return m
+}
+
+func invoke(t *testing.T, m *Minio, op bindings.OperationKind, data []byte, metadata map[string]string) *bindings.InvokeResponse {
+	resp, err := m.Invoke(&bindings.InvokeRequest{Operation: op, Data: data, Metadata: metadata})
+	require.NoError(t, err)
+	return resp
+}
+
+func TestIntegrationOperations(t *testing.T) {
+	m := newIntegrationMinio(t, nil)
+	assert.Nil(t, m.Ping())
+
+	resp := invoke(t, m, bindings.CreateOperation, []byte("test content"), map[string]string{"objectName": "docs/a.txt"})
+	created := createResponse{}
+	require.NoError(t, json.Unmarshal(resp.Data, &created))
+	assert.Equal(t, "docs/a.txt", created.Key)
+
+	resp = invoke(t, m, bindings.GetOperation, nil, map[string]string{"objectName": "docs/a.txt"})
+	assert.Equal(t, "test content", string(resp.Data))
+	assert.Equal(t, "12", resp.Metadata["size"])
+
+	resp = invoke(t, m, bindings.ListOperation, nil, nil)
+	var listed []fileInfoResponse
+	require.NoError(t, json.Unmarshal(resp.Data, &listed))
+	assert.Len(t, listed, 1)
+
+	resp = invoke(t, m, bindings.DeleteOperation, nil, map[string]string{"objectName": "docs/a.txt", IfMatchKey: "*"})
+	deleted := deleteResponse{}
+	require.NoError(t, json.Unmarshal(resp.Data, &deleted))
+	assert.True(t, deleted.Existed)
+	assert.False(t, deleted.DeleteMarker)
+
+	_, err := m.Invoke(&bindings.InvokeRequest{Operation: bindings.GetOperation, Metadata: map[string]string{"objectName": "docs/a.txt"}})
+	assert.Equal(t, ErrCodeNotFound, ErrorCode(err))
+}
+
+func TestIntegrationListPages(t *testing.T) {
+	m := newIntegrationMinio(t, nil)
+	for i := 0; i < 5; i++ {
+		invoke(t, m, bindings.CreateOperation, []byte("x"), map[string]string{"objectName": fmt.Sprintf("logs/%d", i)})
+	}
+
+	count, token := 0, ""
+	for pages := 0; pages < 5; pages++ {
+		resp := invoke(t, m, bindings.ListOperation, nil, map[string]string{
+			ListFormatKey:        ListFormatNDJSON,
+			PrefixKey:            "logs/",
+			MaxResultsKey:        "2",
+			ContinuationTokenKey: token,
+		})
+		count += bytes.Count(resp.Data, []byte("\n"))
+		if token = resp.Metadata[ContinuationTokenKey]; token == "" {
+			break
+		}
+	}
+	assert.Equal(t, 5, count)
+}
+
+func TestIntegrationPresigned(t *testing.T) {
+	m := newIntegrationMinio(t, nil)
+
+	resp := invoke(t, m, PresignedPutOperation, nil, map[string]string{"objectName": "upload.txt", "expires": "1m"})
+	req, err := http.NewRequest(http.MethodPut, presigned(t, resp.Data).URL, bytes.NewReader([]byte("uploaded")))
+	require.NoError(t, err)
+	put, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	put.Body.Close()
+	assert.Equal(t, http.StatusOK, put.StatusCode)
+
+	resp = invoke(t, m, PresignedGetOperation, nil, map[string]string{"objectName": "upload.txt", "expires": "1m"})
+	get, err := http.Get(presigned(t, resp.Data).URL)
+	require.NoError(t, err)
+	defer get.Body.Close()
+	body, err := ioutil.ReadAll(get.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "uploaded", string(body))
+}
+
+func TestIntegrationBatch(t *testing.T) {
+	m := newIntegrationMinio(t, nil)
+
+	items, err := json.Marshal([]batchCreateItem{
+		{ObjectName: "a", Data: []byte("first")},
+		{ObjectName: "b", Data: []byte("second")},
+	})
+	require.NoError(t, err)
+	invoke(t, m, CreateBatchOperation, items, nil)
+
+	resp := invoke(t, m, GetBatchOperation, []byte(`["a","b","missing"]`), nil)
+	results := map[string]batchGetResult{}
+	require.NoError(t, json.Unmarshal(resp.Data, &results))
+	assert.Equal(t, "first", string(results["a"].Data))
+	assert.Equal(t, "second", string(results["b"].Data))
+	assert.Equal(t, ErrCodeNotFound, results["missing"].Code)
+}
+
+func TestIntegrationCompressionAndClientEncryption(t *testing.T) {
+	m := newIntegrationMinio(t, map[string]string{
+		ClientEncryptionKeyKey: "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY=",
+	})
+	content := bytes.Repeat([]byte("compressible "), 1000)
+
+	invoke(t, m, bindings.CreateOperation, content, map[string]string{"objectName": "big.txt", CompressKey: CompressionGzip})
+	resp := invoke(t, m, bindings.GetOperation, nil, map[string]string{"objectName": "big.txt", DecompressKey: "true"})
+	assert.Equal(t, content, resp.Data)
+}
@@ -0,0 +1,71 @@
+package minio
+
+import (
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"path"
+	"strings"
+	"time"
+)
+
+const (
+	KeyTemplateKey = "keyTemplate"
+	FileNameKey    = "fileName"
+)
+
+var keyTemplateDateFormats = map[string]string{
+	"yyyy": "2006",
+	"MM":   "01",
+	"dd":   "02",
+	"HH":   "15",
+	"mm":   "04",
+	"ss":   "05",
+}
+
+// expandKeyTemplate replaces {placeholders} in the template. Date placeholders
+// use now in UTC, {uuid} is a fresh UUID, {ext} is the extension of the
+// request's objectName or fileName, and anything else is looked up in the
+// request metadata.
+func expandKeyTemplate(template string, p map[string]string, now time.Time) (string, error) {
+	var b strings.Builder
+	rest := template
+	for {
+		start := strings.IndexByte(rest, '{')
+		if start < 0 {
+			b.WriteString(rest)
+			return b.String(), nil
+		}
+		end := strings.IndexByte(rest[start:], '}')
+		if end < 0 {
+			return "", errors.Errorf("Minio keyTemplate %s has an unclosed placeholder", template)
+		}
+		b.WriteString(rest[:start])
+		name := rest[start+1 : start+end]
+		rest = rest[start+end+1:]
+
+		value, err := keyTemplateValue(name, p, now)
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(value)
+	}
+}
+
+func keyTemplateValue(name string, p map[string]string, now time.Time) (string, error) {
+	if layout, ok := keyTemplateDateFormats[name]; ok {
+		return now.UTC().Format(layout), nil
+	}
+	switch name {
+	case "uuid":
+		return uuid.New().String(), nil
+	case "ext":
+		if v := objectNameOf(p); v != "" {
+			return path.Ext(v), nil
+		}
+		return path.Ext(p[FileNameKey]), nil
+	}
+	if v, ok := p[name]; ok {
+		return v, nil
+	}
+	return "", errors.Errorf("Minio keyTemplate placeholder {%s} has no value", name)
+}
@@ -0,0 +1,29 @@
+package minio
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestExpandKeyTemplate(t *testing.T) {
+	now := time.Date(2021, 9, 4, 13, 5, 0, 0, time.UTC)
+
+	key, err := expandKeyTemplate("uploads/{yyyy}/{MM}/{dd}/{tenant}-{HH}{mm}{ext}", map[string]string{
+		"tenant":     "acme",
+		"objectName": "report.pdf",
+	}, now)
+	assert.Nil(t, err)
+	assert.Equal(t, "uploads/2021/09/04/acme-1305.pdf", key)
+
+	key, err = expandKeyTemplate("{uuid}{ext}", map[string]string{FileNameKey: "photo.jpg"}, now)
+	assert.Nil(t, err)
+	assert.Len(t, key, 40)
+	assert.Equal(t, ".jpg", key[36:])
+
+	_, err = expandKeyTemplate("{tenant}/x", nil, now)
+	assert.EqualError(t, err, "Minio keyTemplate placeholder {tenant} has no value")
+
+	_, err = expandKeyTemplate("a/{yyyy", nil, now)
+	assert.EqualError(t, err, "Minio keyTemplate a/{yyyy has an unclosed placeholder")
+}
@@ -0,0 +1,53 @@
+package minio
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/dapr/components-contrib/bindings"
+	"strconv"
+)
+
+const KMSKeyStatusOperation bindings.OperationKind = "kmsKeyStatus"
+
+type kmsKeyStatusResponse struct {
+	KeyID           string `json:"keyID"`
+	Healthy         bool   `json:"healthy"`
+	EncryptionError string `json:"encryptionError,omitempty"`
+	DecryptionError string `json:"decryptionError,omitempty"`
+}
+
+// kmsKeyStatus has MinIO generate a data key with the KMS master key and
+// decrypt it again, reporting whether SSE-KMS uploads would work. The key is
+// the request's or component's sseKmsKeyId, or MinIO's default key when
+// neither is set. A failed round-trip is reported in the response rather than
+// as an error, so monitoring can tell it apart from MinIO being unreachable.
+func (m *Minio) kmsKeyStatus(ctx context.Context, admin adminClient, p map[string]string) (*bindings.InvokeResponse, error) {
+	keyID, ok := p[SSEKMSKeyIDKey]
+	if !ok {
+		keyID = m.properties[SSEKMSKeyIDKey]
+	}
+
+	status, err := admin.GetKeyStatus(ctx, keyID)
+	if err != nil {
+		return nil, fmt.Errorf("minio binding error. kms key status: %w", err)
+	}
+	resp := kmsKeyStatusResponse{
+		KeyID:           status.KeyID,
+		EncryptionError: status.EncryptionErr,
+		DecryptionError: status.DecryptionErr,
+	}
+	resp.Healthy = resp.EncryptionError == "" && resp.DecryptionError == ""
+
+	jsonResponse, err := json.Marshal(resp)
+	if err != nil {
+		return nil, err
+	}
+	return &bindings.InvokeResponse{
+		Data: jsonResponse,
+		Metadata: map[string]string{
+			"keyID":   status.KeyID,
+			"healthy": strconv.FormatBool(resp.Healthy),
+		},
+	}, nil
+}
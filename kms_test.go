@@ -0,0 +1,37 @@
+package minio
+
+import (
+	"github.com/dapr/components-contrib/bindings"
+	"github.com/minio/madmin-go"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestKMSKeyStatus(t *testing.T) {
+	m, admin := newFakeAdminMinio()
+	m.properties[SSEKMSKeyIDKey] = "tenant-a"
+	admin.keys = map[string]madmin.KMSKeyStatus{
+		"tenant-b": {KeyID: "tenant-b", DecryptionErr: "key not found"},
+	}
+
+	resp, err := m.Invoke(&bindings.InvokeRequest{Operation: KMSKeyStatusOperation})
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"keyID":"tenant-a","healthy":true}`, string(resp.Data))
+	assert.Equal(t, map[string]string{"keyID": "tenant-a", "healthy": "true"}, resp.Metadata)
+
+	resp, err = m.Invoke(&bindings.InvokeRequest{
+		Operation: KMSKeyStatusOperation,
+		Metadata:  map[string]string{"sseKmsKeyId": "tenant-b"},
+	})
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"keyID":"tenant-b","healthy":false,"decryptionError":"key not found"}`, string(resp.Data))
+	assert.Equal(t, "false", resp.Metadata["healthy"])
+}
+
+func TestKMSKeyStatusUnreachable(t *testing.T) {
+	m, admin := newFakeAdminMinio()
+	admin.err = madmin.ErrorResponse{Code: "XMinioKMSNotConfigured", Message: "KMS not configured for a server side encrypted objects"}
+
+	_, err := m.Invoke(&bindings.InvokeRequest{Operation: KMSKeyStatusOperation})
+	assert.Error(t, err)
+}
@@ -0,0 +1,87 @@
+package minio
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/dapr/components-contrib/bindings"
+	"github.com/minio/minio-go/v7"
+	"strconv"
+)
+
+const (
+	ListFormatKey        = "format"
+	MaxResultsKey        = "maxResults"
+	ContinuationTokenKey = "continuationToken"
+	PrefixKey            = "prefix"
+
+	ListFormatNDJSON = "ndjson"
+
+	defaultMaxResults = 1000
+)
+
+// listPage returns up to maxResults entries as newline-delimited JSON, one
+// object per line, so arbitrarily large listings are fetched in bounded pages.
+// The continuationToken response metadata is passed back to fetch the next page
+// and is absent on the last one.
+func (m *Minio) listPage(ctx context.Context, client objectClient, p map[string]string) (*bindings.InvokeResponse, error) {
+	maxResults, err := sizeProperty(p, MaxResultsKey, defaultMaxResults)
+	if err != nil {
+		return nil, err
+	}
+	if maxResults == 0 {
+		maxResults = defaultMaxResults
+	}
+
+	var (
+		buf       bytes.Buffer
+		count     int64
+		lastKey   string
+		truncated bool
+	)
+	err = m.withRetry(ctx, func() error {
+		buf.Reset()
+		count, lastKey, truncated = 0, "", false
+		listCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		enc := json.NewEncoder(&buf)
+		for object := range client.ListObjects(listCtx, m.Bucket, minio.ListObjectsOptions{
+			Prefix:     p[PrefixKey],
+			Recursive:  true,
+			StartAfter: p[ContinuationTokenKey],
+			MaxKeys:    int(maxResults),
+		}) {
+			if object.Err != nil {
+				return object.Err
+			}
+			if count == maxResults {
+				truncated = true
+				return nil
+			}
+			if err := enc.Encode(fileInfoResponse{
+				Size:      strconv.FormatInt(object.Size, 10),
+				VersionID: object.VersionID,
+				Key:       object.Key,
+			}); err != nil {
+				return err
+			}
+			count++
+			lastKey = object.Key
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("minio binding error. list: %w", err)
+	}
+
+	metadata := map[string]string{
+		"contentType": "application/x-ndjson",
+		"count":       strconv.FormatInt(count, 10),
+	}
+	if truncated {
+		metadata[ContinuationTokenKey] = lastKey
+	}
+	return &bindings.InvokeResponse{Data: buf.Bytes(), Metadata: metadata}, nil
+}
@@ -0,0 +1,87 @@
+package minio
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func listPageKeys(t *testing.T, data []byte) []string {
+	keys := []string{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	for dec.More() {
+		var info fileInfoResponse
+		assert.Nil(t, dec.Decode(&info))
+		keys = append(keys, info.Key)
+	}
+	return keys
+}
+
+func TestListPageChunkBoundaries(t *testing.T) {
+	m, fake := newFakeMinio()
+	for _, key := range []string{"a", "b", "c", "d"} {
+		fake.put("b", "logs/"+key, []byte(key), minio.ObjectInfo{})
+	}
+
+	// a page ending exactly on the last object has no continuation token
+	resp, err := m.listPage(context.Background(), fake, map[string]string{PrefixKey: "logs/", MaxResultsKey: "4"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"logs/a", "logs/b", "logs/c", "logs/d"}, listPageKeys(t, resp.Data))
+	assert.Equal(t, "4", resp.Metadata["count"])
+	assert.NotContains(t, resp.Metadata, ContinuationTokenKey)
+
+	resp, err = m.listPage(context.Background(), fake, map[string]string{PrefixKey: "logs/", MaxResultsKey: "3"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"logs/a", "logs/b", "logs/c"}, listPageKeys(t, resp.Data))
+	assert.Equal(t, "logs/c", resp.Metadata[ContinuationTokenKey])
+	assert.Equal(t, "application/x-ndjson", resp.Metadata["contentType"])
+
+	_, err = m.listPage(context.Background(), fake, map[string]string{MaxResultsKey: "-1"})
+	assert.EqualError(t, err, "maxResults -1 is invalid")
+}
+
+func TestListPageContinuationToken(t *testing.T) {
+	m, fake := newFakeMinio()
+	for _, key := range []string{"a", "b", "c", "d", "e"} {
+		fake.put("b", "logs/"+key, []byte(key), minio.ObjectInfo{})
+	}
+
+	var pages [][]string
+	token := ""
+	for len(pages) < 5 {
+		resp, err := m.listPage(context.Background(), fake, map[string]string{PrefixKey: "logs/", MaxResultsKey: "2", ContinuationTokenKey: token})
+		assert.Nil(t, err)
+		pages = append(pages, listPageKeys(t, resp.Data))
+		if token = resp.Metadata[ContinuationTokenKey]; token == "" {
+			break
+		}
+	}
+	assert.Equal(t, [][]string{{"logs/a", "logs/b"}, {"logs/c", "logs/d"}, {"logs/e"}}, pages)
+
+	// a token past the last key returns an empty last page
+	resp, err := m.listPage(context.Background(), fake, map[string]string{PrefixKey: "logs/", ContinuationTokenKey: "logs/e"})
+	assert.Nil(t, err)
+	assert.Empty(t, resp.Data)
+	assert.Equal(t, "0", resp.Metadata["count"])
+	assert.NotContains(t, resp.Metadata, ContinuationTokenKey)
+}
+
+func TestListPageEmptyPrefix(t *testing.T) {
+	m, fake := newFakeMinio()
+	fake.put("b", "logs/a", []byte("a"), minio.ObjectInfo{})
+	fake.put("b", "other", []byte("x"), minio.ObjectInfo{})
+
+	resp, err := m.listPage(context.Background(), fake, map[string]string{})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"logs/a", "other"}, listPageKeys(t, resp.Data))
+	assert.NotContains(t, resp.Metadata, ContinuationTokenKey)
+
+	// with no objects the page is empty rather than an error
+	resp, err = m.listPage(context.Background(), fake, map[string]string{PrefixKey: "missing/"})
+	assert.Nil(t, err)
+	assert.Empty(t, resp.Data)
+	assert.Equal(t, "0", resp.Metadata["count"])
+}
@@ -0,0 +1,121 @@
+package minio
+
+import (
+	"fmt"
+	"github.com/dapr/components-contrib/bindings"
+	"github.com/dapr/kit/logger"
+	"github.com/pkg/errors"
+	"strings"
+	"time"
+)
+
+const (
+	LogLevelKey = "logLevel"
+
+	SlowThresholdKey       = "slowThreshold"
+	SlowGetThresholdKey    = "slowGetThreshold"
+	SlowPutThresholdKey    = "slowPutThreshold"
+	SlowListThresholdKey   = "slowListThreshold"
+	SlowDeleteThresholdKey = "slowDeleteThreshold"
+)
+
+// slowThresholds are the durations past which an operation is reported as
+// slow. The per-operation values override slowThreshold.
+type slowThresholds struct {
+	fallback    time.Duration
+	byOperation map[bindings.OperationKind]time.Duration
+}
+
+func parseLogLevel(p map[string]string) (logger.LogLevel, error) {
+	switch level := logger.LogLevel(strings.ToLower(p[LogLevelKey])); level {
+	case "":
+		return logger.UndefinedLevel, nil
+	case logger.DebugLevel, logger.InfoLevel, logger.WarnLevel, logger.ErrorLevel:
+		return level, nil
+	default:
+		return logger.UndefinedLevel, errors.Errorf("unsupported Minio logLevel %s", p[LogLevelKey])
+	}
+}
+
+// componentLogger returns a logger of its own for the component, so its
+// logLevel doesn't change the level of other components sharing the binding's
+// logger.
+func componentLogger(name string, level logger.LogLevel) logger.Logger {
+	log := logger.NewLogger("dapr.bindings.minio." + name)
+	log.SetOutputLevel(level)
+	return log
+}
+
+func parseSlowThresholds(p map[string]string) (slowThresholds, error) {
+	keys := map[string]bindings.OperationKind{
+		SlowGetThresholdKey:    bindings.GetOperation,
+		SlowPutThresholdKey:    bindings.CreateOperation,
+		SlowListThresholdKey:   bindings.ListOperation,
+		SlowDeleteThresholdKey: bindings.DeleteOperation,
+	}
+	var thresholds slowThresholds
+	var err error
+	if thresholds.fallback, err = durationProperty(p, SlowThresholdKey, 0); err != nil {
+		return thresholds, err
+	}
+	thresholds.byOperation = map[bindings.OperationKind]time.Duration{}
+	for key, operation := range keys {
+		threshold, err := durationProperty(p, key, 0)
+		if err != nil {
+			return thresholds, err
+		}
+		if threshold > 0 {
+			thresholds.byOperation[operation] = threshold
+		}
+	}
+	return thresholds, nil
+}
+
+func (t slowThresholds) threshold(op bindings.OperationKind) time.Duration {
+	if threshold, ok := t.byOperation[op]; ok {
+		return threshold
+	}
+	return t.fallback
+}
+
+// warnIfSlow flags operations that took longer than their threshold,
+// regardless of the outcome, to help spot a degraded cluster before calls
+// start timing out.
+func (m *Minio) warnIfSlow(req *bindings.InvokeRequest, resp *bindings.InvokeResponse, elapsed time.Duration) {
+	threshold := m.slow.threshold(req.Operation)
+	if threshold <= 0 || elapsed <= threshold {
+		return
+	}
+	received := 0
+	if resp != nil {
+		received = len(resp.Data)
+	}
+	m.logger.Warnf("Minio binding slow operation=%s endpoint=%s bucket=%s key=%s duration=%s threshold=%s sent=%d received=%d",
+		req.Operation, m.endpoint, m.Bucket, objectNameOf(req.Metadata), elapsed, threshold, len(req.Data), received)
+}
+
+// logOperation writes one key=value line per operation. Successful operations
+// and missing objects, which callers routinely probe for, are logged at debug
+// level and other failures at warn level, so the default info level stays
+// quiet unless something goes wrong.
+func (m *Minio) logOperation(req *bindings.InvokeRequest, resp *bindings.InvokeResponse, err error, elapsed time.Duration) {
+	received := 0
+	if resp != nil {
+		received = len(resp.Data)
+	}
+	line := fmt.Sprintf("Minio binding operation=%s bucket=%s key=%s duration=%s sent=%d received=%d",
+		req.Operation, m.Bucket, objectNameOf(req.Metadata), elapsed, len(req.Data), received)
+	if err != nil {
+		code := ErrorCode(err)
+		if code == "" {
+			code = "Error"
+		}
+		if code == ErrCodeNotFound {
+			m.logger.Debugf("%s outcome=%s error=%q", line, code, err.Error())
+			return
+		}
+		m.logger.Warnf("%s outcome=%s error=%q", line, code, err.Error())
+		return
+	}
+	m.logger.Debugf("%s outcome=OK", line)
+}
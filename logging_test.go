@@ -0,0 +1,87 @@
+package minio
+
+import (
+	"fmt"
+	"github.com/dapr/components-contrib/bindings"
+	"github.com/dapr/kit/logger"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+type recordingLogger struct {
+	logger.Logger
+	level logger.LogLevel
+	lines []string
+}
+
+func (r *recordingLogger) SetOutputLevel(level logger.LogLevel) { r.level = level }
+func (r *recordingLogger) Debugf(format string, args ...interface{}) {
+	r.lines = append(r.lines, "debug "+fmt.Sprintf(format, args...))
+}
+func (r *recordingLogger) Warnf(format string, args ...interface{}) {
+	r.lines = append(r.lines, "warn "+fmt.Sprintf(format, args...))
+}
+
+func TestLogOperation(t *testing.T) {
+	log := &recordingLogger{Logger: logger.NewLogger("minio")}
+	m := NewMinio(log)
+	m.Bucket = "b"
+	req := &bindings.InvokeRequest{Operation: bindings.GetOperation, Metadata: map[string]string{"objectName": "a.txt"}}
+
+	m.logOperation(req, &bindings.InvokeResponse{Data: []byte("abc")}, nil, 12*time.Millisecond)
+	m.logOperation(req, nil, &Error{Code: ErrCodeNotFound, Operation: bindings.GetOperation, Err: fmt.Errorf("missing")}, time.Millisecond)
+	m.logOperation(req, nil, &Error{Code: ErrCodeAccessDenied, Operation: bindings.GetOperation, Err: fmt.Errorf("denied")}, time.Millisecond)
+
+	assert.Equal(t, []string{
+		"debug Minio binding operation=get bucket=b key=a.txt duration=12ms sent=0 received=3 outcome=OK",
+		`debug Minio binding operation=get bucket=b key=a.txt duration=1ms sent=0 received=0 outcome=NotFound error="minio binding error. get NotFound: missing"`,
+		`warn Minio binding operation=get bucket=b key=a.txt duration=1ms sent=0 received=0 outcome=AccessDenied error="minio binding error. get AccessDenied: denied"`,
+	}, log.lines)
+}
+
+func TestComponentLogger(t *testing.T) {
+	shared := logger.NewLogger("minio")
+	log := componentLogger("orders", logger.DebugLevel)
+	assert.NotEqual(t, shared, log)
+	assert.Equal(t, log, componentLogger("orders", logger.DebugLevel))
+	assert.NotEqual(t, log, componentLogger("invoices", logger.WarnLevel))
+}
+
+func TestParseLogLevel(t *testing.T) {
+	level, err := parseLogLevel(map[string]string{LogLevelKey: "Debug"})
+	assert.Nil(t, err)
+	assert.Equal(t, logger.DebugLevel, level)
+
+	level, err = parseLogLevel(map[string]string{})
+	assert.Nil(t, err)
+	assert.Equal(t, logger.UndefinedLevel, level)
+
+	_, err = parseLogLevel(map[string]string{LogLevelKey: "verbose"})
+	assert.EqualError(t, err, "unsupported Minio logLevel verbose")
+}
+
+func TestWarnIfSlow(t *testing.T) {
+	log := &recordingLogger{Logger: logger.NewLogger("minio")}
+	m := NewMinio(log)
+	m.Bucket = "b"
+	m.endpoint = "minio:9000"
+	var err error
+	m.slow, err = parseSlowThresholds(map[string]string{SlowThresholdKey: "1s", SlowGetThresholdKey: "100ms"})
+	assert.Nil(t, err)
+
+	get := &bindings.InvokeRequest{Operation: bindings.GetOperation, Metadata: map[string]string{"objectName": "a.txt"}}
+	put := &bindings.InvokeRequest{Operation: bindings.CreateOperation, Data: []byte("abcd"), Metadata: map[string]string{"objectName": "a.txt"}}
+	m.warnIfSlow(get, nil, 50*time.Millisecond)
+	m.warnIfSlow(put, nil, 500*time.Millisecond)
+	m.warnIfSlow(get, &bindings.InvokeResponse{Data: []byte("abc")}, 200*time.Millisecond)
+	m.warnIfSlow(put, nil, 2*time.Second)
+
+	assert.Equal(t, []string{
+		"warn Minio binding slow operation=get endpoint=minio:9000 bucket=b key=a.txt duration=200ms threshold=100ms sent=0 received=3",
+		"warn Minio binding slow operation=create endpoint=minio:9000 bucket=b key=a.txt duration=2s threshold=1s sent=4 received=0",
+	}, log.lines)
+
+	_, err = parseSlowThresholds(map[string]string{SlowListThresholdKey: "soon"})
+	assert.EqualError(t, err, "slowListThreshold soon is invalid")
+}
@@ -0,0 +1,118 @@
+package minio
+
+import (
+	"context"
+	"github.com/dapr/components-contrib/bindings"
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+	"sync"
+	"time"
+)
+
+var (
+	operationKey = tag.MustNewKey("operation")
+	bucketKey    = tag.MustNewKey("bucket")
+	codeKey      = tag.MustNewKey("code")
+
+	operationCount   = stats.Int64("minio/operations", "Number of Minio binding operations", stats.UnitDimensionless)
+	operationLatency = stats.Float64("minio/operation_latency", "Latency of Minio binding operations", stats.UnitMilliseconds)
+	bytesSent        = stats.Int64("minio/bytes_sent", "Payload bytes sent to Minio", stats.UnitBytes)
+	bytesReceived    = stats.Int64("minio/bytes_received", "Payload bytes received from Minio", stats.UnitBytes)
+
+	latencyBounds = []float64{1, 2, 5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000, 30000}
+)
+
+// MetricViews are the OpenCensus views of the binding's operation metrics.
+// The first Init registers them with the default registry, so a Prometheus
+// exporter on it, such as the Dapr sidecar's, picks them up; SetMeter records
+// them elsewhere. Operations are tagged with the error code, or "OK" on
+// success.
+var MetricViews = []*view.View{
+	{
+		Name:        "minio/operations",
+		Description: operationCount.Description(),
+		Measure:     operationCount,
+		TagKeys:     []tag.Key{operationKey, bucketKey, codeKey},
+		Aggregation: view.Count(),
+	},
+	{
+		Name:        "minio/operation_latency",
+		Description: operationLatency.Description(),
+		Measure:     operationLatency,
+		TagKeys:     []tag.Key{operationKey, bucketKey, codeKey},
+		Aggregation: view.Distribution(latencyBounds...),
+	},
+	{
+		Name:        "minio/bytes_sent",
+		Description: bytesSent.Description(),
+		Measure:     bytesSent,
+		TagKeys:     []tag.Key{operationKey, bucketKey},
+		Aggregation: view.Sum(),
+	},
+	{
+		Name:        "minio/bytes_received",
+		Description: bytesReceived.Description(),
+		Measure:     bytesReceived,
+		TagKeys:     []tag.Key{operationKey, bucketKey},
+		Aggregation: view.Sum(),
+	},
+}
+
+var (
+	registerViewsOnce sync.Once
+	registerViewsErr  error
+)
+
+// registerMetricViews registers MetricViews with the default registry once
+// per process; every binding records into the same views.
+func registerMetricViews() error {
+	registerViewsOnce.Do(func() {
+		registerViewsErr = view.Register(MetricViews...)
+	})
+	return registerViewsErr
+}
+
+// SetMeter records the binding's metrics with meter instead of the default
+// registry, for callers that export them through their own registry or
+// exporter. It registers MetricViews with meter; starting it and attaching
+// exporters is up to the caller.
+func (m *Minio) SetMeter(meter view.Meter) error {
+	if err := meter.Register(MetricViews...); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.meter = meter
+	return nil
+}
+
+func (m *Minio) recordMetrics(req *bindings.InvokeRequest, resp *bindings.InvokeResponse, err error, elapsed time.Duration) {
+	code := "OK"
+	if err != nil {
+		if code = ErrorCode(err); code == "" {
+			code = "Error"
+		}
+	}
+	received := 0
+	if resp != nil {
+		received = len(resp.Data)
+	}
+	mutators := []tag.Mutator{
+		tag.Upsert(operationKey, string(req.Operation)),
+		tag.Upsert(bucketKey, m.Bucket),
+		tag.Upsert(codeKey, code),
+	}
+	if err := stats.RecordWithOptions(context.Background(),
+		stats.WithRecorder(m.meter),
+		stats.WithTags(mutators...),
+		stats.WithMeasurements(
+			operationCount.M(1),
+			operationLatency.M(float64(elapsed)/float64(time.Millisecond)),
+			bytesSent.M(int64(len(req.Data))),
+			bytesReceived.M(int64(received)),
+		),
+	); err != nil {
+		m.logger.Debugf("Minio binding failed to record metrics: %s", err)
+	}
+}
@@ -0,0 +1,51 @@
+package minio
+
+import (
+	"github.com/dapr/components-contrib/bindings"
+	"github.com/dapr/kit/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opencensus.io/stats/view"
+	"testing"
+	"time"
+)
+
+func TestRecordMetrics(t *testing.T) {
+	m := NewMinio(logger.NewLogger("minio"))
+	m.Bucket = "metered"
+
+	meter := view.NewMeter()
+	meter.Start()
+	defer meter.Stop()
+	require.NoError(t, m.SetMeter(meter))
+
+	m.recordMetrics(&bindings.InvokeRequest{Operation: bindings.CreateOperation, Data: []byte("abc")}, nil, nil, 5*time.Millisecond)
+	m.recordMetrics(&bindings.InvokeRequest{Operation: bindings.GetOperation}, nil, &Error{Code: ErrCodeNotFound}, time.Millisecond)
+
+	rows, err := meter.RetrieveData("minio/operations")
+	assert.Nil(t, err)
+	codes := map[string]int64{}
+	for _, row := range rows {
+		for _, tag := range row.Tags {
+			if tag.Key == codeKey {
+				codes[tag.Value] += row.Data.(*view.CountData).Value
+			}
+		}
+	}
+	assert.Equal(t, int64(1), codes["OK"])
+	assert.Equal(t, int64(1), codes[ErrCodeNotFound])
+
+	// nothing reaches the default registry
+	rows, _ = view.RetrieveData("minio/operations")
+	for _, row := range rows {
+		for _, tag := range row.Tags {
+			assert.False(t, tag.Key == bucketKey && tag.Value == "metered")
+		}
+	}
+}
+
+func TestRegisterMetricViews(t *testing.T) {
+	assert.NoError(t, registerMetricViews())
+	assert.NoError(t, registerMetricViews())
+	assert.NotNil(t, view.Find("minio/operations"))
+}
@@ -3,14 +3,22 @@ package minio
 import (
 	"bytes"
 	"context"
+	"crypto/cipher"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"github.com/dapr/components-contrib/bindings"
 	"github.com/dapr/kit/logger"
+	"github.com/google/uuid"
 	"github.com/minio/minio-go/v7"
 	"github.com/minio/minio-go/v7/pkg/credentials"
 	"github.com/pkg/errors"
+	"go.opencensus.io/stats/view"
+	"go.opentelemetry.io/otel/trace"
+	"io"
 	"strconv"
+	"sync"
 	"time"
 )
 
@@ -21,16 +29,90 @@ const (
 	SSLKey = "ssl"
 	BucketKey = "bucket"
 	RegionKey = "region"
+	AllowCredentialOverrideKey = "allowCredentialOverride"
+	GetTimeoutKey = "getTimeout"
+	PutTimeoutKey = "putTimeout"
+	ListTimeoutKey = "listTimeout"
+	DeleteTimeoutKey = "deleteTimeout"
+	DecodeQuotedKey = "decodeQuoted"
+	EncodingKey = "encoding"
+	GenerateObjectNameKey = "generateObjectName"
+	StrictKey = "strict"
+	MaxGetSizeKey = "maxGetSize"
+	OversizedGetKey = "oversizedGet"
+
+	OversizedGetError = "error"
+	OversizedGetPresign = "presign"
+	PartSizeKey = "partSize"
+	NumThreadsKey = "numThreads"
+	DisableContentSha256Key = "disableContentSha256"
+	DisableMultipartMd5Key = "disableMultipartMd5"
+	ObjectNameKey = "objectName"
+	KeyKey = "key"
+	MissingAsEmptyKey = "missingAsEmpty"
+	BypassGovernanceKey = "bypassGovernance"
+
+	EncodingBase64 = "base64"
 
 	PresignedGetOperation bindings.OperationKind = "presignedGet"
+	RotateCredentialsOperation bindings.OperationKind = "rotateCredentials"
 	ReadBufferMax = 0x40000
+
+	defaultOversizedPresignExpiry = 15 * time.Minute
+	minPartSize                   = 5 << 20
+	defaultStreamPartSize         = 16 << 20
 )
 
 type Minio struct {
-	minioClient	*minio.Client
+	minioClient	objectClient
 	logger 		logger.Logger
 	Bucket		string
 	Region		string
+
+	endpoint      string
+	endpoints     []string
+	readEndpoints []string
+	secure        bool
+	bucketLookup  minio.BucketLookupType
+	transport     http.RoundTripper
+	properties    map[string]string
+	credentials   *rotatingProvider
+	rotated       map[string]string
+
+	allowCredentialOverride bool
+	input                   inputConfig
+	checkpoints             CheckpointStore
+	publisher               Publisher
+	meter                   view.Meter
+	tracerProvider          trace.TracerProvider
+	timeouts                map[bindings.OperationKind]time.Duration
+	retry                   retryPolicy
+	envelope                string
+	maxGetSize              int64
+	oversizedGet            string
+	putOptions              minio.PutObjectOptions
+	clientCipher            cipher.AEAD
+	audit                   auditConfig
+	auditWriter             *auditWriter
+	slow                    slowThresholds
+	admin                   adminClient
+	usage                   usageHistory
+	regional                *regionalClients
+	failoverCooldown        time.Duration
+	mirror                  *mirror
+	presignEndpoint         string
+	presignSecure           bool
+	signingRegion           string
+	allowedRegions          map[string]bool
+	previewBytes            int64
+	maxUnpackSize           int64
+	defaultPresignExpiry    time.Duration
+	maxPresignExpiry        time.Duration
+
+	mu     sync.RWMutex
+	closed bool
+	ctx    context.Context
+	cancel context.CancelFunc
 }
 
 var _ = bindings.OutputBinding(&Minio{})
@@ -42,17 +124,39 @@ func NewMinio(logger logger.Logger) *Minio{
 func (m *Minio) Init(metadata bindings.Metadata) error {
 	m.logger.Debug("Initializing Minio binding")
 	p := metadata.Properties
-	endpoint, ok := p[Endpoint]
-	if !ok || endpoint == "" {
+	logLevel, err := parseLogLevel(p)
+	if err != nil {
+		return err
+	}
+	log := m.logger
+	if logLevel != logger.UndefinedLevel {
+		log = componentLogger(metadata.Name, logLevel)
+	}
+	if err := m.validateMetadata(p); err != nil {
+		return err
+	}
+	endpoints, secure, err := parseEndpoints(p[Endpoint], propertyToBool(p, SSLKey), p[PortKey])
+	if err != nil {
+		return err
+	}
+	if len(endpoints) == 0 {
 		return errors.Errorf("missing Minio endpoint string")
 	}
-	accessKey, ok := p[AccessKey]
-	if !ok || accessKey == "" {
-		return errors.Errorf("missing Minio accessKey string")
+	if _, ok := p[SSLKey]; ok && secure != propertyToBool(p, SSLKey) {
+		m.logger.Warnf("Minio %s is ignored, the scheme of the endpoint sets it to %t", SSLKey, secure)
 	}
-	secretKey, ok := p[SecretAccessKey]
-	if !ok || secretKey == "" {
-		return errors.Errorf("missing Minio secretKey string")
+	// STS and the admin API use the primary endpoint
+	endpoint := endpoints[0]
+	readEndpoints, readSecure, err := parseEndpoints(p[ReadEndpointKey], secure, p[PortKey])
+	if err != nil {
+		return err
+	}
+	if readSecure != secure {
+		return errors.Errorf("Minio %s must use the scheme of the endpoint", ReadEndpointKey)
+	}
+	failoverCooldown, err := durationProperty(p, FailoverCooldownKey, defaultFailoverCooldown)
+	if err != nil {
+		return err
 	}
 	bucket, ok := p[BucketKey]
 	if !ok || bucket == "" {
@@ -62,19 +166,215 @@ func (m *Minio) Init(metadata bindings.Metadata) error {
 	if !ok {
 		region = ""
 	}
-	secure := propertyToBool(p, SSLKey)
+	signingRegion := p[SigningRegionKey]
+	if signingRegion == "" {
+		signingRegion = region
+	}
+	bucketLookup, err := parseBucketLookup(p)
+	if err != nil {
+		return err
+	}
+	presignEndpoint, presignSecure, err := parsePresignEndpoint(p, secure)
+	if err != nil {
+		return err
+	}
+	defaultPresignExpiry, maxPresignExpiry, err := parsePresignExpiries(p)
+	if err != nil {
+		return err
+	}
 
-	client, err := minio.New(endpoint, &minio.Options{
-		Creds: credentials.NewStaticV4(accessKey, secretKey, ""),
-		Secure: secure,
-	})
+	transport, err := m.newTransport(p, secure)
+	if err != nil {
+		return err
+	}
+
+	creds, err := newCredentials(p, endpoint, secure, transport)
+	if err != nil {
+		return err
+	}
+	allowOverride := propertyToBool(p, AllowCredentialOverrideKey)
+	if allowOverride && transport == nil {
+		// share one connection pool between the per-request clients
+		if transport, err = minio.DefaultTransport(secure); err != nil {
+			return err
+		}
+	}
+
+	rotating := newRotatingProvider(creds)
+
+	options := func() *minio.Options {
+		return &minio.Options{
+			Creds:        rotating.clientCredentials(),
+			Secure:       secure,
+			Transport:    transport,
+			Region:       signingRegion,
+			BucketLookup: bucketLookup,
+		}
+	}
+	client, rawClients, err := newRoutedClient(endpoints, readEndpoints, options, failoverCooldown, m.logger)
+	if err != nil {
+		return err
+	}
+	mirror, err := newMirror(p, primaryClient(client), bucket, *options(), failoverCooldown, m.logger)
+	if err != nil {
+		return err
+	}
+	input, err := parseInputConfig(p)
+	if err != nil {
+		return err
+	}
+	timeouts, err := parseTimeouts(p)
+	if err != nil {
+		return err
+	}
+	retry, err := parseRetryPolicy(p)
+	if err != nil {
+		return err
+	}
+	envelope, err := parseEnvelopeVersion(p)
+	if err != nil {
+		return err
+	}
+	maxGetSize, err := sizeProperty(p, MaxGetSizeKey, 0)
+	if err != nil {
+		return err
+	}
+	previewBytes, err := sizeProperty(p, PreviewBytesKey, defaultPreviewBytes)
+	if err != nil {
+		return err
+	}
+	maxUnpackSize, err := sizeProperty(p, MaxUnpackSizeKey, defaultMaxUnpackSize)
+	if err != nil {
+		return err
+	}
+	putOptions, err := uploadOptions(p, minio.PutObjectOptions{})
+	if err != nil {
+		return err
+	}
+	if _, err := serverSideEncryption(p, nil); err != nil {
+		return err
+	}
+	clientCipher, err := newClientCipher(p)
+	if err != nil {
+		return err
+	}
+	slow, err := parseSlowThresholds(p)
 	if err != nil {
 		return err
 	}
+	audit, err := parseAuditConfig(p)
+	if err != nil {
+		return err
+	}
+	var admin adminClient
+	if propertyToBool(p, AdminOperationsKey) {
+		if admin, err = newAdminClient(endpoint, rotating.clientCredentials(), secure, transport); err != nil {
+			return err
+		}
+	}
+	oversizedGet := p[OversizedGetKey]
+	switch oversizedGet {
+	case "":
+		oversizedGet = OversizedGetError
+	case OversizedGetError, OversizedGetPresign:
+	default:
+		return errors.Errorf("unsupported Minio oversizedGet %s", oversizedGet)
+	}
+
+	// anonymous clients are meant for public read-only buckets and usually
+	// aren't allowed to query or create the bucket.
+	if !propertyToBool(p, AnonymousKey) {
+		if err := ensureBucket(client, bucket, region); err != nil {
+			return err
+		}
+	}
+	if propertyToBool(p, RequireEncryptedBucketKey) {
+		if err := checkBucketEncryption(client, bucket); err != nil {
+			return err
+		}
+	}
+
+	if propertyToBool(p, TraceRequestsKey) {
+		m.logger.Warn("Minio traceRequests is enabled, every request and response is logged. Do not use this in production")
+		for _, raw := range rawClients {
+			raw.TraceOn(&redactingWriter{emit: func(line string) { m.logger.Info(line) }})
+		}
+	}
+
+	m.mu.RLock()
+	meter := m.meter
+	m.mu.RUnlock()
+	if meter == nil {
+		if err := registerMetricViews(); err != nil {
+			m.logger.Warnf("Minio binding failed to register metric views: %s", err)
+		}
+	}
+
+	// a re-init swaps the configuration only after it has been validated, and
+	// waits for in-flight operations on the previous client to finish. A
+	// running Read stops and has to be called again.
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.cancel != nil {
+		m.cancel()
+	}
+	m.logger = log
 	m.minioClient = client
 	m.Bucket = bucket
 	m.Region = region
+	m.signingRegion = p[SigningRegionKey]
+	m.allowedRegions = parseAllowedRegions(p)
+	m.endpoint = endpoint
+	m.endpoints = endpoints
+	m.readEndpoints = readEndpoints
+	m.failoverCooldown = failoverCooldown
+	m.secure = secure
+	m.bucketLookup = bucketLookup
+	m.presignEndpoint = presignEndpoint
+	m.presignSecure = presignSecure
+	m.defaultPresignExpiry = defaultPresignExpiry
+	m.maxPresignExpiry = maxPresignExpiry
+	m.transport = transport
+	m.properties = p
+	m.credentials = rotating
+	m.rotated = nil
+	m.allowCredentialOverride = allowOverride
+	m.input = input
+	m.timeouts = timeouts
+	m.retry = retry
+	m.envelope = envelope
+	m.maxGetSize = maxGetSize
+	m.previewBytes = previewBytes
+	m.maxUnpackSize = maxUnpackSize
+	m.oversizedGet = oversizedGet
+	m.putOptions = putOptions
+	m.clientCipher = clientCipher
+	m.audit = audit
+	m.auditWriter = nil
+	if audit.target == AuditLogBucket {
+		m.auditWriter = newAuditWriter(client, bucket, log)
+	}
+	m.slow = slow
+	m.admin = admin
+	m.regional = &regionalClients{}
+	m.mirror = mirror
+	// a store set with SetCheckpointStore is kept, the default one follows
+	// the new configuration
+	if _, ok := m.checkpoints.(*objectCheckpointStore); ok || m.checkpoints == nil {
+		m.checkpoints = newObjectCheckpointStore(p, metadata.Name, client, bucket)
+	}
+	m.ctx, m.cancel = context.WithCancel(context.Background())
+	m.closed = false
+	if mirror != nil {
+		go mirror.run(m.ctx)
+	}
+	if m.auditWriter != nil {
+		go m.auditWriter.run(m.ctx)
+	}
+	return nil
+}
 
+func ensureBucket(client objectClient, bucket, region string) error {
 	ctx := context.Background()
 
 	exists, err := client.BucketExists(ctx, bucket)
@@ -90,18 +390,48 @@ func (m *Minio) Init(metadata bindings.Metadata) error {
 	return nil
 }
 
+// Close cancels in-flight operations and the input binding. It is safe to
+// call more than once.
 func (m *Minio) Close() error {
+	m.mu.RLock()
+	cancel := m.cancel
+	m.mu.RUnlock()
+	if cancel != nil {
+		cancel()
+	}
+
+	m.mu.Lock()
+	m.closed = true
+	m.mu.Unlock()
 	return nil
 }
 
 func (m *Minio) Operations() []bindings.OperationKind {
-	return []bindings.OperationKind{
+	operations := []bindings.OperationKind{
 		bindings.CreateOperation,
 		bindings.GetOperation,
 		bindings.DeleteOperation,
 		bindings.ListOperation,
 		PresignedGetOperation,
+		PresignedPutOperation,
+		PresignedPostOperation,
+		RotateCredentialsOperation,
+		GetBatchOperation,
+		CreateBatchOperation,
+		MakeBucketOperation,
+		PreviewOperation,
+		ChecksumOperation,
+		HasChangedOperation,
+		CopyOperation,
+		MovePrefixOperation,
+		SyncPrefixOperation,
+		ArchiveOperation,
+		UnpackOperation,
 	}
+	if m.admin != nil {
+		operations = append(operations, adminOperations...)
+	}
+	return operations
 }
 
 type createResponse struct {
@@ -109,69 +439,175 @@ type createResponse struct {
 	VersionID string `json:"versionID"`
 	Key string `json:"key"`
 }
-func (m *Minio) create(req *bindings.InvokeRequest) (*bindings.InvokeResponse, error) {
-	ctx := context.Background()
+func (m *Minio) create(ctx context.Context, req *bindings.InvokeRequest) (*bindings.InvokeResponse, error) {
+	p := req.Metadata
 
-	d, err := strconv.Unquote(string(req.Data))
-	if err == nil {
-		req.Data = []byte(d)
+	r, size, err := m.payloadReader(req.Data, p)
+	if err != nil {
+		return nil, err
 	}
 
-	r := bytes.NewReader(req.Data)
+	return m.upload(ctx, p, r, size)
+}
 
-	p := req.Metadata
+// CreateFromReader uploads the content of r without buffering it, for
+// callers that can hand the binding a stream instead of a byte slice. The
+// Dapr runtime hands output bindings the request data as a byte slice, so
+// create streams from that slice instead. The size is unknown up front, so
+// MinIO receives it as a multipart upload of partSize parts. Request
+// metadata is the same as for create, except that the content is always
+// uploaded as-is.
+func (m *Minio) CreateFromReader(ctx context.Context, r io.Reader, metadata map[string]string) (*bindings.InvokeResponse, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	req := &bindings.InvokeRequest{Operation: bindings.CreateOperation, Metadata: metadata}
+	return m.run(ctx, req, func(ctx context.Context) (*bindings.InvokeResponse, error) {
+		return m.upload(ctx, metadata, r, -1)
+	})
+}
 
-	objectName, ok := p["objectName"]
-	if !ok || objectName == "" {
-		return nil, errors.Errorf("missing name field")
+func (m *Minio) upload(ctx context.Context, p map[string]string, r io.Reader, size int64) (*bindings.InvokeResponse, error) {
+	objectName, err := m.newObjectName(p)
+	if err != nil {
+		return nil, err
 	}
+	return m.uploadObject(ctx, objectName, p, r, size)
+}
 
-	resultUpload, err := m.minioClient.PutObject(ctx, m.Bucket, objectName, r, r.Size(), minio.PutObjectOptions{})
+// uploadObject uploads to objectName as given, without applying keyTemplate.
+func (m *Minio) uploadObject(ctx context.Context, objectName string, p map[string]string, r io.Reader, size int64) (*bindings.InvokeResponse, error) {
+	client, err := m.clientFor(p)
+	if err != nil {
+		return nil, err
+	}
+
+	opts, err := uploadOptions(p, m.putOptions)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.checkPreconditions(ctx, client, objectName, p); err != nil {
+		return nil, err
+	}
+	conditionalPut(&opts, p)
+	if opts.ServerSideEncryption, err = serverSideEncryption(m.properties, p); err != nil {
+		return nil, err
+	}
+	if r, size, opts.UserMetadata, err = compressUpload(ctx, p, r, size, opts.UserMetadata); err != nil {
+		return nil, err
+	}
+	if m.clientCipher != nil {
+		if r, size, opts.UserMetadata, err = encryptUpload(m.clientCipher, r, opts.UserMetadata); err != nil {
+			return nil, err
+		}
+	}
+	// minio-go sizes parts for the 5 TiB maximum when the length is unknown,
+	// buffering hundreds of MiB per part unless a smaller size is given
+	if size < 0 && opts.PartSize == 0 {
+		opts.PartSize = defaultStreamPartSize
+	}
+
+	resultUpload, err := client.PutObject(ctx, m.Bucket, objectName, r, size, opts)
 	if err != nil {
 		return nil, fmt.Errorf("minio binding error. Uploading: %w", err)
 	}
+	m.mirror.enqueue(uploadMirrorTask(objectName, opts.ServerSideEncryption))
 	jsonResponse, err := json.Marshal(createResponse{
 		Location:  resultUpload.Location,
 		VersionID: resultUpload.VersionID,
-		Key: resultUpload.Key,
+		Key:       resultUpload.Key,
 	})
+	if err != nil {
+		return nil, err
+	}
 
 	return &bindings.InvokeResponse{
 		Data: jsonResponse,
+		Metadata: map[string]string{
+			"key":       resultUpload.Key,
+			"etag":      resultUpload.ETag,
+			"versionID": resultUpload.VersionID,
+		},
 	}, nil
 }
 
-func (m *Minio) get(req *bindings.InvokeRequest) (*bindings.InvokeResponse, error) {
-	ctx := context.Background()
-
+func (m *Minio) get(ctx context.Context, req *bindings.InvokeRequest) (*bindings.InvokeResponse, error) {
 	p := req.Metadata
 
-	objectName, ok := p["objectName"]
-	if !ok || objectName== "" {
+	objectName := objectNameOf(p)
+	if objectName == "" {
 		return nil, errors.Errorf("missing name field")
 	}
 
-	reader, err := m.minioClient.GetObject(ctx, m.Bucket, objectName, minio.GetObjectOptions{})
+	client, err := m.clientFor(p)
 	if err != nil {
-		return nil, fmt.Errorf("get object error: %w", err)
+		return nil, err
 	}
 
-	defer reader.Close()
-
-	stat, err := reader.Stat()
+	sse, err := readEncryption(m.properties, p)
 	if err != nil {
-		return nil, fmt.Errorf("io streaming stat is error: %w", err)
+		return nil, err
 	}
 
-	resultData := readByBuffer(reader, stat.Size)
-	if resultData == nil {
-		return nil, errors.Errorf("read io buffer error")
+	var (
+		stat       minio.ObjectInfo
+		resultData []byte
+		oversized  bool
+	)
+	err = m.withRetry(ctx, func() error {
+		reader, err := client.GetObject(ctx, m.Bucket, objectName, minio.GetObjectOptions{ServerSideEncryption: sse})
+		if err != nil {
+			return fmt.Errorf("get object error: %w", err)
+		}
+
+		defer reader.Close()
+
+		stat, err = reader.Stat()
+		if err != nil {
+			return fmt.Errorf("io streaming stat is error: %w", err)
+		}
+
+		// the whole object is buffered, so refuse sizes that could exhaust memory
+		if oversized = m.maxGetSize > 0 && stat.Size > m.maxGetSize; oversized {
+			return nil
+		}
+
+		resultData, err = readByBuffer(reader, stat.Size)
+		return err
+	})
+	if err != nil {
+		// with missingAsEmpty a missing object reads as no content, which is
+		// what the Dapr conformance tests expect after a delete
+		if m.requestFlag(p, MissingAsEmptyKey) && ErrorCode(newError(bindings.GetOperation, err)) == ErrCodeNotFound {
+			return &bindings.InvokeResponse{Metadata: map[string]string{"key": objectName, "exists": "false"}}, nil
+		}
+		return nil, err
+	}
+	if oversized {
+		presigner, err := m.presignClientFor(p)
+		if err != nil {
+			return nil, err
+		}
+		return m.oversizedGetResponse(ctx, presigner, stat)
 	}
 
+	// contentType lets HTTP callers serve the content with its MIME type
 	info := map[string]string{
-		"size":      strconv.FormatInt(stat.Size, 10),
-		"versionID": stat.VersionID,
-		"key":       stat.Key,
+		"size":        strconv.FormatInt(stat.Size, 10),
+		"versionID":   stat.VersionID,
+		"key":         stat.Key,
+		"etag":        stat.ETag,
+		"contentType": stat.ContentType,
+	}
+	if isClientEncrypted(stat) {
+		if resultData, err = decryptObject(m.clientCipher, resultData); err != nil {
+			return nil, err
+		}
+	}
+	if m.requestFlag(p, DecompressKey) && isGzipped(stat) {
+		if resultData, err = gunzip(resultData, m.maxGetSize); err != nil {
+			return nil, err
+		}
+		info["decompressed"] = "true"
 	}
 	return &bindings.InvokeResponse{
 		Data: resultData,
@@ -179,22 +615,122 @@ func (m *Minio) get(req *bindings.InvokeRequest) (*bindings.InvokeResponse, erro
 	}, nil
 }
 
-func (m *Minio) delete(req *bindings.InvokeRequest) (*bindings.InvokeResponse, error) {
-	ctx := context.Background()
+// deleteResponse reports whether the object existed before the delete and
+// whether the delete left a delete marker, as MinIO confirmed it.
+type deleteResponse struct {
+	Key                   string `json:"key"`
+	VersionID             string `json:"versionID,omitempty"`
+	DeleteMarker          bool   `json:"deleteMarker"`
+	DeleteMarkerVersionID string `json:"deleteMarkerVersionID,omitempty"`
+	Existed               bool   `json:"existed"`
+}
 
+// oversizedGetResponse fails with ErrCodeTooLarge, or with oversizedGet set to
+// presign, returns a presigned URL for the caller to download the object from.
+func (m *Minio) oversizedGetResponse(ctx context.Context, client objectClient, stat minio.ObjectInfo) (*bindings.InvokeResponse, error) {
+	if m.oversizedGet != OversizedGetPresign {
+		return nil, &Error{
+			Code:      ErrCodeTooLarge,
+			Operation: bindings.GetOperation,
+			Bucket:    m.Bucket,
+			Key:       stat.Key,
+			Err:       errors.Errorf("object %s is %d bytes, larger than maxGetSize %d", stat.Key, stat.Size, m.maxGetSize),
+		}
+	}
+
+	expires := defaultOversizedPresignExpiry
+	if m.maxPresignExpiry > 0 && expires > m.maxPresignExpiry {
+		expires = m.maxPresignExpiry
+	}
+	u, err := client.PresignedGetObject(ctx, m.Bucket, stat.Key, expires, nil)
+	if err != nil {
+		return nil, fmt.Errorf("presigned object error: %w", err)
+	}
+	return &bindings.InvokeResponse{
+		Metadata: map[string]string{
+			"size":         strconv.FormatInt(stat.Size, 10),
+			"versionID":    stat.VersionID,
+			"key":          stat.Key,
+			"etag":         stat.ETag,
+			"contentType":  stat.ContentType,
+			"presignedURL": u.String(),
+		},
+	}, nil
+}
+
+func (m *Minio) delete(ctx context.Context, req *bindings.InvokeRequest) (*bindings.InvokeResponse, error) {
 	p := req.Metadata
 
-	objectName, ok := p["objectName"]
-	if !ok || objectName== "" {
+	objectName := objectNameOf(p)
+	if objectName == "" {
 		return nil, errors.Errorf("missing name field")
 	}
 
-	err := m.minioClient.RemoveObject(ctx, m.Bucket, objectName, minio.RemoveObjectOptions{GovernanceBypass: true})
+	client, err := m.clientFor(p)
+	if err != nil {
+		return nil, err
+	}
+
+	versionID := p["versionID"]
+	// S3 answers a delete the same whether or not the object was there, so
+	// it is read first to report that and to check preconditions against
+	sse, err := readEncryption(m.properties, p)
+	if err != nil {
+		return nil, err
+	}
+	existed := true
+	var current minio.ObjectInfo
+	err = m.withRetry(ctx, func() error {
+		current, err = primaryClient(client).StatObject(ctx, m.Bucket, objectName, minio.StatObjectOptions{
+			VersionID:            versionID,
+			ServerSideEncryption: sse,
+		})
+		return err
+	})
+	if err != nil {
+		if errorCode(minio.ToErrorResponse(err)) != ErrCodeNotFound {
+			return nil, fmt.Errorf("minio binding error. stat: %w", err)
+		}
+		existed = false
+	}
+	if hasPreconditions(p) {
+		if err := evaluatePreconditions(p, m.Bucket, objectName, existed, current.ETag); err != nil {
+			return nil, err
+		}
+	}
+
+	// object lock governance retention is only bypassed on request
+	var result minio.RemoveObjectResult
+	err = m.withRetry(ctx, func() error {
+		result, err = client.RemoveObjectWithResult(ctx, m.Bucket, objectName, minio.RemoveObjectOptions{
+			GovernanceBypass: propertyToBool(p, BypassGovernanceKey),
+			VersionID:        versionID,
+		})
+		return err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("minio binding error. remove: %w", err)
 	}
+	// versions are particular to each bucket, so only deletes of the latest
+	// version are mirrored
+	if versionID == "" {
+		m.mirror.enqueue(mirrorTask{key: objectName, delete: true})
+	}
 
-	return nil, err
+	jsonResponse, err := json.Marshal(deleteResponse{
+		Key:                   objectName,
+		VersionID:             versionID,
+		DeleteMarker:          result.DeleteMarker,
+		DeleteMarkerVersionID: result.DeleteMarkerVersionID,
+		Existed:               existed,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &bindings.InvokeResponse{
+		Data:     jsonResponse,
+		Metadata: map[string]string{"key": objectName},
+	}, nil
 }
 
 type fileInfoResponse struct {
@@ -202,21 +738,54 @@ type fileInfoResponse struct {
 	VersionID string `json:"versionID"`
 	Key string `json:"key"`
 }
-func (m *Minio) list(req *bindings.InvokeRequest) (*bindings.InvokeResponse, error) {
-	var resultList []fileInfoResponse
-	for object := range m.minioClient.ListObjects(context.Background(), m.Bucket, minio.ListObjectsOptions{
-		UseV1:     true,
-		Recursive: true,
-	}) {
-		if object.Err != nil {
-			fmt.Println(object.Err)
-			continue
-		}
-		resultList = append(resultList, fileInfoResponse{
-			Size:      strconv.FormatInt(object.Size, 10),
-			VersionID: object.VersionID,
-			Key:       object.Key,
-		})
+func (m *Minio) list(ctx context.Context, req *bindings.InvokeRequest) (*bindings.InvokeResponse, error) {
+	client, err := m.clientFor(req.Metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	switch format := req.Metadata[ListFormatKey]; format {
+	case "", "json":
+	case ListFormatNDJSON:
+		return m.listPage(ctx, client, req.Metadata)
+	default:
+		return nil, errors.Errorf("unsupported Minio list format %s", format)
+	}
+
+	var (
+		resultList []fileInfoResponse
+		listErrors []string
+	)
+	err = m.withRetry(ctx, func() error {
+		resultList, listErrors = nil, nil
+		listCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		for object := range client.ListObjects(listCtx, m.Bucket, minio.ListObjectsOptions{
+			UseV1:     true,
+			Recursive: true,
+		}) {
+			if object.Err != nil {
+				// restart the listing on transient failures
+				if isTransient(object.Err) {
+					return object.Err
+				}
+				m.logger.Warnf("Minio binding list error: %s", object.Err)
+				if propertyToBool(req.Metadata, StrictKey) {
+					return object.Err
+				}
+				listErrors = append(listErrors, object.Err.Error())
+				continue
+			}
+			resultList = append(resultList, fileInfoResponse{
+				Size:      strconv.FormatInt(object.Size, 10),
+				VersionID: object.VersionID,
+				Key:       object.Key,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("minio binding error. list: %w", err)
 	}
 
 	jsonResponse, err := json.Marshal(resultList)
@@ -224,82 +793,448 @@ func (m *Minio) list(req *bindings.InvokeRequest) (*bindings.InvokeResponse, err
 		return nil, fmt.Errorf("minio binding error. list operation. cannot marshal blobs to json: %w", err)
 	}
 
-	return &bindings.InvokeResponse{
+	resp := &bindings.InvokeResponse{
 		Data: jsonResponse,
-	}, nil
+	}
+	// entries that failed to list are reported alongside the partial result
+	if len(listErrors) > 0 {
+		errorsJSON, err := json.Marshal(listErrors)
+		if err != nil {
+			return nil, err
+		}
+		resp.Metadata = map[string]string{
+			"errorCount": strconv.Itoa(len(listErrors)),
+			"errors":     string(errorsJSON),
+		}
+	}
+	return resp, nil
 }
 
-func (m *Minio) presignedGet(req *bindings.InvokeRequest) (*bindings.InvokeResponse, error) {
-	ctx := context.Background()
-
+func (m *Minio) presignedGet(ctx context.Context, req *bindings.InvokeRequest) (*bindings.InvokeResponse, error) {
 	p := req.Metadata
 
-	objectName, ok := p["objectName"]
-	if !ok || objectName== "" {
+	objectName := objectNameOf(p)
+	if objectName == "" {
 		return nil, errors.Errorf("missing name field")
 	}
-	// expires time.Duration
-	duration, ok := p["expires"]
-	if !ok || objectName== "" {
-		return nil, errors.Errorf("missing duration field")
+	expires, err := m.presignExpires(p)
+	if err != nil {
+		return nil, err
 	}
-	expires, err := time.ParseDuration(duration)
-	if err != nil  {
-		return nil, errors.Errorf("expires %s is invalid", duration)
+	// an SSE-C object can only be read with its key headers
+	sse, err := readEncryption(m.properties, p)
+	if err != nil {
+		return nil, err
+	}
+	params, err := presignParams(p)
+	if err != nil {
+		return nil, err
+	}
+	params = withDownloadFileName(params, p)
+
+	client, err := m.presignClientFor(p)
+	if err != nil {
+		return nil, err
 	}
 
-	// reqParams := make(url.Values)
-	// reqParams.Set("response-content-disposition", "attachment; filename=\"" + "" + "\"")
-	result, err := m.minioClient.PresignedGetObject(ctx, m.Bucket, objectName, expires, nil)
+	if sse != nil {
+		h := http.Header{}
+		sse.Marshal(h)
+		return presignWithHeaders(ctx, client, http.MethodGet, m.Bucket, objectName, expires, params, h)
+	}
+	result, err := client.PresignedGetObject(ctx, m.Bucket, objectName, expires, params)
 	if err != nil {
 		return nil, fmt.Errorf("presigned object error: %w", err)
 	}
+	return newPresignResponse(http.MethodGet, result, expires, nil)
+}
 
-	return &bindings.InvokeResponse{
-		Data: []byte(result.String()),
-		Metadata: nil,
-	}, nil
+// clientFor returns the client to run a request with. When
+// allowCredentialOverride is set, requests may carry their own
+// accessKey/secretKey/sessionToken so a single binding can act on behalf of
+// different tenants. A request region other than the component's gets a
+// client signing for that region. With readFromPrimary set, reads bypass the
+// read replicas.
+func (m *Minio) clientFor(p map[string]string) (objectClient, error) {
+	client, err := m.requestClient(p)
+	if err != nil || !m.requestFlag(p, ReadFromPrimaryKey) {
+		return client, err
+	}
+	return primaryClient(client), nil
+}
+
+func (m *Minio) requestClient(p map[string]string) (objectClient, error) {
+	accessKey, secretKey := p[AccessKey], p[SecretAccessKey]
+	region := m.requestRegion(p)
+	if err := m.checkRegion(region); err != nil {
+		return nil, err
+	}
+	if accessKey == "" && secretKey == "" {
+		if m.signingRegionFor(region) == m.signingRegionFor(m.Region) {
+			return m.minioClient, nil
+		}
+		return m.regionClient(region)
+	}
+	if !m.allowCredentialOverride {
+		return nil, errors.Errorf("minio binding error. request credentials require %s", AllowCredentialOverrideKey)
+	}
+	if accessKey == "" || secretKey == "" {
+		return nil, errors.Errorf("minio binding error. request accessKey and secretKey must be set together")
+	}
+
+	client, _, err := m.newClient(credentials.NewStaticV4(accessKey, secretKey, p[SessionTokenKey]), region)
+	return client, err
 }
 
+// rotationKeys are the only request metadata rotateCredentials accepts, so a
+// caller can't switch the authentication mode or its endpoints.
+var rotationKeys = map[string]bool{
+	AccessKey:       true,
+	SecretAccessKey: true,
+	SessionTokenKey: true,
+}
 
+// rotateCredentials rebuilds the credentials from the component properties
+// overlaid with a new accessKey/secretKey/sessionToken from the request
+// metadata and swaps them into the running client. The component properties
+// themselves are left unchanged.
+func (m *Minio) rotateCredentials(ctx context.Context, req *bindings.InvokeRequest) (*bindings.InvokeResponse, error) {
+	rotated := make(map[string]string, len(m.rotated)+len(req.Metadata))
+	for k, v := range m.rotated {
+		rotated[k] = v
+	}
+	for k, v := range req.Metadata {
+		if !rotationKeys[k] {
+			return nil, errors.Errorf("unsupported Minio rotateCredentials field %s", k)
+		}
+		rotated[k] = v
+	}
+	p := make(map[string]string, len(m.properties)+len(rotated))
+	for k, v := range m.properties {
+		p[k] = v
+	}
+	for k, v := range rotated {
+		p[k] = v
+	}
+
+	creds, err := newCredentials(p, m.endpoint, m.secure, m.transport)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := creds.Get(); err != nil {
+		return nil, fmt.Errorf("minio binding error. rotate credentials: %w", err)
+	}
+	m.credentials.rotate(creds)
+	m.rotated = rotated
+	m.logger.Info("Minio credentials rotated")
+
+	return &bindings.InvokeResponse{}, nil
+}
+
+// Invoke implements bindings.OutputBinding. The Dapr runtime passes no
+// context through this interface, so operations invoked by it are bounded
+// only by the operation timeouts and cancelled by Close.
 func (m *Minio) Invoke(req *bindings.InvokeRequest) (*bindings.InvokeResponse, error) {
+	return m.InvokeWithContext(context.Background(), req)
+}
+
+// InvokeWithContext runs an operation under ctx, so its cancellation and
+// deadline reach MinIO. It serves callers that hold a context, such as the
+// configuration store in this module; the Dapr runtime calls Invoke.
+func (m *Minio) InvokeWithContext(ctx context.Context, req *bindings.InvokeRequest) (*bindings.InvokeResponse, error) {
 	if req == nil {
 		return nil, errors.Errorf("invoke request required")
 	}
+	// rotation replaces shared state, everything else only reads it
+	if req.Operation == RotateCredentialsOperation {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+	} else {
+		m.mu.RLock()
+		defer m.mu.RUnlock()
+	}
+	return m.run(ctx, req, func(ctx context.Context) (*bindings.InvokeResponse, error) {
+		return m.invoke(ctx, req)
+	})
+}
+
+// run executes an operation with the binding's lifecycle, timeouts, error
+// codes and response envelope applied. The caller holds m.mu.
+func (m *Minio) run(ctx context.Context, req *bindings.InvokeRequest, fn func(context.Context) (*bindings.InvokeResponse, error)) (resp *bindings.InvokeResponse, err error) {
+	op := req.Operation
+	start := time.Now()
+	ctx, span := m.startSpan(ctx, req)
+	defer func() {
+		elapsed := time.Since(start)
+		endSpan(span, resp, err)
+		m.recordMetrics(req, resp, err, elapsed)
+		m.logOperation(req, resp, err, elapsed)
+		m.warnIfSlow(req, resp, elapsed)
+		m.recordAudit(req, resp, err, start)
+	}()
+
+	if m.closed {
+		return nil, errors.Errorf("minio binding error. binding is closed")
+	}
+	if m.minioClient == nil {
+		return nil, errors.Errorf("minio binding error. binding is not initialized")
+	}
+	// Close cancels the operation even when the caller's context doesn't
+	closing := m.ctx.Done()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-closing:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	if timeout, ok := m.timeouts[op]; ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	resp, err = fn(ctx)
+	if err != nil {
+		return nil, newError(op, err)
+	}
+	if m.envelope != "" {
+		return wrapResponse(m.envelope, op, resp)
+	}
+	return resp, nil
+}
+
+func (m *Minio) invoke(ctx context.Context, req *bindings.InvokeRequest) (*bindings.InvokeResponse, error) {
 	switch req.Operation {
 	case PresignedGetOperation:
-		return m.presignedGet(req)
+		return m.presignedGet(ctx, req)
+	case PresignedPutOperation:
+		return m.presignedPut(ctx, req)
+	case PresignedPostOperation:
+		return m.presignedPost(ctx, req)
+	case RotateCredentialsOperation:
+		return m.rotateCredentials(ctx, req)
 	case bindings.CreateOperation:
-		return m.create(req)
+		return m.create(ctx, req)
 	case bindings.GetOperation:
-		return m.get(req)
+		return m.get(ctx, req)
 	case bindings.DeleteOperation:
-		return m.delete(req)
+		return m.delete(ctx, req)
 	case bindings.ListOperation:
-		return m.list(req)
+		return m.list(ctx, req)
+	case GetBatchOperation:
+		return m.getBatch(ctx, req)
+	case CreateBatchOperation:
+		return m.createBatch(ctx, req)
+	case MakeBucketOperation:
+		return m.makeBucket(ctx, req)
+	case PreviewOperation:
+		return m.preview(ctx, req)
+	case ChecksumOperation:
+		return m.checksum(ctx, req)
+	case HasChangedOperation:
+		return m.hasChanged(ctx, req)
+	case CopyOperation:
+		return m.copy(ctx, req)
+	case MovePrefixOperation:
+		return m.movePrefix(ctx, req)
+	case SyncPrefixOperation:
+		return m.syncPrefix(ctx, req)
+	case ArchiveOperation:
+		return m.archive(ctx, req)
+	case UnpackOperation:
+		return m.unpack(ctx, req)
 	default:
+		if isAdminOperation(req.Operation) {
+			return m.invokeAdmin(ctx, req)
+		}
 		return nil, errors.Errorf("minio binding error. unsupported operation %s", req.Operation)
 	}
 }
 
-func readByBuffer(reader *minio.Object, size int64) []byte{
-	totalSize := size
-	resultData := make([]byte, totalSize)
-	i := int64(0)
-	l := ReadBufferMax
-	for i < totalSize{
-		_i := i + ReadBufferMax
-		if _i > totalSize {
-			l = int(ReadBufferMax - (_i - totalSize))
+// readByBuffer reads exactly size bytes, failing on a short or broken read
+// instead of returning a partially filled buffer.
+func readByBuffer(reader io.Reader, size int64) ([]byte, error) {
+	resultData := make([]byte, size)
+	if _, err := io.ReadFull(reader, resultData); err != nil {
+		return nil, fmt.Errorf("minio binding error. read object: %w", err)
+	}
+	return resultData, nil
+}
+
+// parseTimeouts reads the optional per-operation deadlines, so a hung MinIO
+// node can't stall the calling goroutine indefinitely.
+func parseTimeouts(p map[string]string) (map[bindings.OperationKind]time.Duration, error) {
+	keys := map[string]bindings.OperationKind{
+		GetTimeoutKey:    bindings.GetOperation,
+		PutTimeoutKey:    bindings.CreateOperation,
+		ListTimeoutKey:   bindings.ListOperation,
+		DeleteTimeoutKey: bindings.DeleteOperation,
+	}
+	timeouts := map[bindings.OperationKind]time.Duration{}
+	for key, operation := range keys {
+		timeout, err := durationProperty(p, key, 0)
+		if err != nil {
+			return nil, err
 		}
-		n, err := reader.ReadAt(resultData[i:l], i)
-		if n != l && err != nil {
-			_ = fmt.Errorf("readat error: %w size: %d/%d", err, n, l)
-			return nil
+		if timeout > 0 {
+			timeouts[operation] = timeout
 		}
-		i = _i
 	}
-	return resultData
+	return timeouts, nil
+}
+
+// durationProperty parses an optional positive duration property.
+func durationProperty(props map[string]string, key string, defaultValue time.Duration) (time.Duration, error) {
+	v, ok := props[key]
+	if !ok || v == "" {
+		return defaultValue, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		return 0, errors.Errorf("%s %s is invalid", key, v)
+	}
+	return d, nil
+}
+
+// sizeProperty parses an optional non-negative byte count property.
+func sizeProperty(props map[string]string, key string, defaultValue int64) (int64, error) {
+	v, ok := props[key]
+	if !ok || v == "" {
+		return defaultValue, nil
+	}
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || n < 0 {
+		return 0, errors.Errorf("%s %s is invalid", key, v)
+	}
+	return n, nil
+}
+
+// uploadOptions applies the multipart and checksum settings from the request
+// metadata, falling back to the component's settings in defaults.
+func uploadOptions(p map[string]string, defaults minio.PutObjectOptions) (minio.PutObjectOptions, error) {
+	opts := defaults
+	partSize, err := sizeProperty(p, PartSizeKey, int64(defaults.PartSize))
+	if err != nil {
+		return opts, err
+	}
+	if partSize != 0 && partSize < minPartSize {
+		return opts, errors.Errorf("partSize %d is below the 5 MiB minimum", partSize)
+	}
+	numThreads, err := sizeProperty(p, NumThreadsKey, int64(defaults.NumThreads))
+	if err != nil {
+		return opts, err
+	}
+	opts.PartSize = uint64(partSize)
+	opts.NumThreads = uint(numThreads)
+	// skipping the payload checksums trades integrity checks for throughput,
+	// which is only sensible over TLS on a trusted network
+	if _, ok := p[DisableContentSha256Key]; ok {
+		opts.DisableContentSha256 = propertyToBool(p, DisableContentSha256Key)
+	}
+	if _, ok := p[DisableMultipartMd5Key]; ok {
+		opts.SendContentMd5 = !propertyToBool(p, DisableMultipartMd5Key)
+	}
+	return opts, nil
+}
+
+// objectNameOf returns the objectName request metadata, or key as used by the
+// other Dapr storage bindings and the conformance tests.
+func objectNameOf(p map[string]string) string {
+	if objectName := p[ObjectNameKey]; objectName != "" {
+		return objectName
+	}
+	return p[KeyKey]
+}
+
+// newObjectName expands the component's keyTemplate when one is configured.
+// Otherwise it returns the requested objectName, or a generated UUID when
+// generateObjectName is enabled and the caller didn't name the object.
+func (m *Minio) newObjectName(p map[string]string) (string, error) {
+	if template := m.properties[KeyTemplateKey]; template != "" {
+		return expandKeyTemplate(template, p, time.Now())
+	}
+	if objectName := objectNameOf(p); objectName != "" {
+		return objectName, nil
+	}
+	if !propertyToBool(m.properties, GenerateObjectNameKey) {
+		return "", errors.Errorf("missing name field")
+	}
+	return uuid.New().String(), nil
+}
+
+// decodePayload uploads the payload byte-exact unless the request asks for
+// base64 decoding, or decodeQuoted is set on the request or the component, in
+// which case a JSON-quoted string is unquoted.
+func (m *Minio) decodePayload(data []byte, p map[string]string) ([]byte, error) {
+	switch encoding := p[EncodingKey]; encoding {
+	case "":
+	case EncodingBase64:
+		decoded, err := base64.StdEncoding.DecodeString(string(data))
+		if err != nil {
+			return nil, fmt.Errorf("minio binding error. base64 decode: %w", err)
+		}
+		return decoded, nil
+	default:
+		return nil, errors.Errorf("unsupported Minio encoding %s", encoding)
+	}
+
+	if !m.requestFlag(p, DecodeQuotedKey) {
+		return data, nil
+	}
+	if d, err := strconv.Unquote(string(data)); err == nil {
+		return []byte(d), nil
+	}
+	return data, nil
+}
+
+// payloadReader streams the payload of a create from the request data.
+// Base64 is decoded as the upload reads it rather than into a copy of the
+// data; only decodeQuoted still makes one.
+func (m *Minio) payloadReader(data []byte, p map[string]string) (io.Reader, int64, error) {
+	if p[EncodingKey] != EncodingBase64 {
+		decoded, err := m.decodePayload(data, p)
+		if err != nil {
+			return nil, 0, err
+		}
+		return bytes.NewReader(decoded), int64(len(decoded)), nil
+	}
+	size, err := base64DecodedLen(data)
+	if err != nil {
+		return nil, 0, fmt.Errorf("minio binding error. base64 decode: %w", err)
+	}
+	return base64.NewDecoder(base64.StdEncoding, bytes.NewReader(data)), size, nil
+}
+
+// base64DecodedLen is the exact length data decodes to, ignoring the line
+// breaks the decoder skips. Invalid characters are only found while decoding.
+func base64DecodedLen(data []byte) (int64, error) {
+	var n, padding int64
+	for _, c := range data {
+		switch c {
+		case '\r', '\n':
+		case '=':
+			n++
+			padding++
+		default:
+			n++
+			padding = 0
+		}
+	}
+	if n%4 != 0 || padding > 2 {
+		return 0, base64.CorruptInputError(len(data))
+	}
+	return n/4*3 - padding, nil
+}
+
+// requestFlag reads a boolean from the request metadata, falling back to the
+// component property of the same name.
+func (m *Minio) requestFlag(p map[string]string, key string) bool {
+	if _, ok := p[key]; ok {
+		return propertyToBool(p, key)
+	}
+	return propertyToBool(m.properties, key)
 }
 
 func propertyToBool(props map[string]string, key string) bool {
@@ -1,93 +1,229 @@
 package minio
 
 import (
-	"encoding/json"
-	"fmt"
+	"bytes"
+	"context"
 	"github.com/dapr/components-contrib/bindings"
 	"github.com/dapr/kit/logger"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
 	"github.com/stretchr/testify/assert"
+	"io"
 	"io/ioutil"
 	"testing"
+	"time"
 )
 
-// put
-func TestGetMinio(t *testing.T){
-	minio := NewMinio(logger.NewLogger("minio"))
-	meta :=bindings.Metadata{Properties: map[string]string{
-		Endpoint: "192.168.75.80:9000",
-		AccessKey: "minio",
-		SecretAccessKey: "putao520",
-		SSLKey: "false",
-		BucketKey: "fos",
-		RegionKey: "lb-1",
-	}}
-	if err := minio.Init(meta); err != nil {
-		t.Fatal(err)
-	}
-	defer minio.Close()
-	// read file
-	f, err := ioutil.ReadFile("./test.txt")
-	if err != nil{
-		t.Fatal("test file open fatal")
-	}
-	// put object
-	t.Run("return err if is error", func(t *testing.T){
-		inputCreate := map[string]string{}
-		inputCreate["objectName"] = "test_file"
-		r1 := bindings.InvokeRequest{
-			Data:      f,
-			Metadata:  inputCreate,
-			Operation: bindings.CreateOperation,
-		}
-		result1, err := minio.create(&r1)
-		assert.Nil(t, err)
-		data := createResponse{}
-		err = json.Unmarshal(result1.Data, &data)
-		if err != nil {
-			t.Fatal(err)
-		}
-		objectName := data.Key
-
-		inputGet := map[string]string{}
-		inputGet["objectName"] = objectName
-		r2 := bindings.InvokeRequest{
-			Data:      nil,
-			Metadata:  inputGet,
-			Operation: bindings.GetOperation,
-		}
-		result2, err := minio.get(&r2)
+func TestClientFor(t *testing.T) {
+	m := NewMinio(logger.NewLogger("minio"))
+
+	t.Run("component client without request credentials", func(t *testing.T) {
+		c, err := m.clientFor(map[string]string{"objectName": "a"})
 		assert.Nil(t, err)
-		assert.Equal(t, string(result2.Data), "test content")
-
-		presignedGet := map[string]string{}
-		presignedGet["objectName"] = objectName
-		presignedGet["expires"] = "60s"
-		r5 := bindings.InvokeRequest{
-			Data:      nil,
-			Metadata:  presignedGet,
-			Operation: PresignedGetOperation,
-		}
-		result5, err := minio.presignedGet(&r5)
+		assert.Equal(t, m.minioClient, c)
+	})
+
+	t.Run("request credentials require the override flag", func(t *testing.T) {
+		_, err := m.clientFor(map[string]string{AccessKey: "tenant", SecretAccessKey: "secret"})
+		assert.NotNil(t, err)
+	})
+
+	t.Run("request credentials must be complete", func(t *testing.T) {
+		m.allowCredentialOverride = true
+		_, err := m.clientFor(map[string]string{AccessKey: "tenant"})
+		assert.NotNil(t, err)
+	})
+}
+
+func TestParseTimeouts(t *testing.T) {
+	timeouts, err := parseTimeouts(map[string]string{GetTimeoutKey: "5s", PutTimeoutKey: "1m"})
+	assert.Nil(t, err)
+	assert.Equal(t, 5*time.Second, timeouts[bindings.GetOperation])
+	assert.Equal(t, time.Minute, timeouts[bindings.CreateOperation])
+	_, ok := timeouts[bindings.ListOperation]
+	assert.False(t, ok)
+
+	_, err = parseTimeouts(map[string]string{ListTimeoutKey: "-1s"})
+	assert.EqualError(t, err, "listTimeout -1s is invalid")
+}
+
+func TestDecodePayload(t *testing.T) {
+	m := NewMinio(logger.NewLogger("minio"))
+	quoted := []byte(`"hello"`)
+
+	decode := func(data []byte, p map[string]string) []byte {
+		b, err := m.decodePayload(data, p)
 		assert.Nil(t, err)
-		fmt.Println(string(result5.Data))
-
-		r3 := bindings.InvokeRequest{
-			Data:      nil,
-			Metadata:  nil,
-			Operation: bindings.ListOperation,
-		}
-		result3, err := minio.list(&r3)
+		return b
+	}
+
+	assert.Equal(t, quoted, decode(quoted, nil))
+	assert.Equal(t, []byte("hello"), decode(quoted, map[string]string{DecodeQuotedKey: "true"}))
+
+	m.properties = map[string]string{DecodeQuotedKey: "true"}
+	assert.Equal(t, []byte("hello"), decode(quoted, nil))
+	assert.Equal(t, quoted, decode(quoted, map[string]string{DecodeQuotedKey: "false"}))
+	assert.Equal(t, []byte(`"unterminated`), decode([]byte(`"unterminated`), nil))
+
+	t.Run("base64", func(t *testing.T) {
+		assert.Equal(t, []byte{0, 1, 0xff}, decode([]byte("AAH/"), map[string]string{EncodingKey: EncodingBase64}))
+
+		_, err := m.decodePayload([]byte("not base64!"), map[string]string{EncodingKey: EncodingBase64})
+		assert.NotNil(t, err)
+		_, err = m.decodePayload(nil, map[string]string{EncodingKey: "hex"})
+		assert.EqualError(t, err, "unsupported Minio encoding hex")
+	})
+}
+
+func TestPayloadReader(t *testing.T) {
+	m := NewMinio(logger.NewLogger("minio"))
+	base64Encoding := map[string]string{EncodingKey: EncodingBase64}
+
+	for encoded, want := range map[string]string{"aGVsbG8=": "hello", "aGVs\r\nbG8h": "hello!", "aGk=": "hi", "": ""} {
+		r, size, err := m.payloadReader([]byte(encoded), base64Encoding)
 		assert.Nil(t, err)
-		fmt.Println(string(result3.Data))
-
-		inputRemove := map[string]string{}
-		inputRemove["objectName"] = objectName
-		r4 := bindings.InvokeRequest{
-			Data:      nil,
-			Metadata:  inputRemove,
-			Operation: bindings.DeleteOperation,
-		}
-		_, err = minio.delete(&r4)
+		assert.Equal(t, int64(len(want)), size)
+		b, err := ioutil.ReadAll(r)
 		assert.Nil(t, err)
-	})
+		assert.Equal(t, want, string(b))
+	}
+
+	_, _, err := m.payloadReader([]byte("aGVsbG8"), base64Encoding)
+	assert.NotNil(t, err)
+
+	r, size, err := m.payloadReader([]byte("plain"), nil)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(5), size)
+	b, _ := ioutil.ReadAll(r)
+	assert.Equal(t, "plain", string(b))
+
+	fm, fake := newFakeMinio()
+	_, err = fm.Invoke(&bindings.InvokeRequest{Operation: bindings.CreateOperation, Data: []byte("no!base6"), Metadata: map[string]string{"objectName": "a", EncodingKey: EncodingBase64}})
+	assert.NotNil(t, err)
+	_, ok := fake.buckets["b"]["a"]
+	assert.False(t, ok)
+}
+
+func TestNewObjectName(t *testing.T) {
+	m := NewMinio(logger.NewLogger("minio"))
+
+	name, err := m.newObjectName(map[string]string{"objectName": "a.txt"})
+	assert.Nil(t, err)
+	assert.Equal(t, "a.txt", name)
+
+	_, err = m.newObjectName(nil)
+	assert.EqualError(t, err, "missing name field")
+
+	m.properties = map[string]string{GenerateObjectNameKey: "true"}
+	first, err := m.newObjectName(nil)
+	assert.Nil(t, err)
+	second, _ := m.newObjectName(map[string]string{})
+	assert.Len(t, first, 36)
+	assert.NotEqual(t, first, second)
+}
+
+func TestNewObjectNameFromTemplate(t *testing.T) {
+	m := NewMinio(logger.NewLogger("minio"))
+	m.properties = map[string]string{KeyTemplateKey: "tenants/{tenant}/{objectName}"}
+
+	name, err := m.newObjectName(map[string]string{"tenant": "acme", "objectName": "a.txt"})
+	assert.Nil(t, err)
+	assert.Equal(t, "tenants/acme/a.txt", name)
+}
+
+func TestReadByBuffer(t *testing.T) {
+	content := bytes.Repeat([]byte("0123456789abcdef"), ReadBufferMax/16*3+5)
+
+	data, err := readByBuffer(bytes.NewReader(content), int64(len(content)))
+	assert.Nil(t, err)
+	assert.Equal(t, content, data)
+
+	_, err = readByBuffer(bytes.NewReader(content[:100]), 200)
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
+
+func TestInvokeLifecycle(t *testing.T) {
+	m := NewMinio(logger.NewLogger("minio"))
+	req := &bindings.InvokeRequest{Operation: bindings.GetOperation, Metadata: map[string]string{"objectName": "a"}}
+
+	_, err := m.Invoke(req)
+	assert.EqualError(t, err, "minio binding error. binding is not initialized")
+
+	assert.Nil(t, m.Close())
+	assert.Nil(t, m.Close())
+	_, err = m.Invoke(req)
+	assert.EqualError(t, err, "minio binding error. binding is closed")
+}
+
+func TestOversizedGet(t *testing.T) {
+	m := NewMinio(logger.NewLogger("minio"))
+	m.Bucket = "b"
+	m.maxGetSize = 10
+	m.oversizedGet = OversizedGetError
+
+	_, err := m.oversizedGetResponse(context.Background(), nil, minio.ObjectInfo{Key: "big.bin", Size: 11})
+	assert.Equal(t, ErrCodeTooLarge, ErrorCode(err))
+	assert.EqualError(t, err, "minio binding error. get TooLarge: object big.bin is 11 bytes, larger than maxGetSize 10")
+}
+
+func TestUploadOptions(t *testing.T) {
+	defaults, err := uploadOptions(map[string]string{PartSizeKey: "16777216", NumThreadsKey: "4"}, minio.PutObjectOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(16<<20), defaults.PartSize)
+	assert.Equal(t, uint(4), defaults.NumThreads)
+
+	opts, err := uploadOptions(map[string]string{NumThreadsKey: "8"}, defaults)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(16<<20), opts.PartSize)
+	assert.Equal(t, uint(8), opts.NumThreads)
+
+	_, err = uploadOptions(map[string]string{PartSizeKey: "1024"}, defaults)
+	assert.EqualError(t, err, "partSize 1024 is below the 5 MiB minimum")
+
+	fast, err := uploadOptions(map[string]string{DisableContentSha256Key: "true", DisableMultipartMd5Key: "true"}, minio.PutObjectOptions{SendContentMd5: true})
+	assert.Nil(t, err)
+	assert.True(t, fast.DisableContentSha256)
+	assert.False(t, fast.SendContentMd5)
+
+	opts, err = uploadOptions(map[string]string{}, fast)
+	assert.Nil(t, err)
+	assert.True(t, opts.DisableContentSha256)
+}
+
+func TestCreateFromReaderBeforeInit(t *testing.T) {
+	m := NewMinio(logger.NewLogger("minio"))
+	_, err := m.CreateFromReader(context.Background(), bytes.NewReader([]byte("x")), map[string]string{"objectName": "a"})
+	assert.EqualError(t, err, "minio binding error. binding is not initialized")
+}
+
+func TestRotateCredentials(t *testing.T) {
+	m, _ := newFakeMinio()
+	m.properties = map[string]string{AccessKey: "old", SecretAccessKey: "old-secret"}
+	m.credentials = newRotatingProvider(credentials.NewStaticV4("old", "old-secret", ""))
+	c, admin := m.credentials.clientCredentials(), m.credentials.clientCredentials()
+	_, err := admin.Get()
+	assert.Nil(t, err)
+
+	_, err = m.Invoke(&bindings.InvokeRequest{Operation: RotateCredentialsOperation, Metadata: map[string]string{AccessKey: "new", SecretAccessKey: "new-secret"}})
+	assert.Nil(t, err)
+	v, err := c.Get()
+	assert.Nil(t, err)
+	assert.Equal(t, "new", v.AccessKeyID)
+	v, err = admin.Get()
+	assert.Nil(t, err)
+	assert.Equal(t, "new", v.AccessKeyID)
+	assert.Equal(t, "old", m.properties[AccessKey])
+	assert.Equal(t, "new", m.principal(&bindings.InvokeRequest{}))
+
+	// a later rotation keeps the keys it doesn't replace
+	_, err = m.Invoke(&bindings.InvokeRequest{Operation: RotateCredentialsOperation, Metadata: map[string]string{SessionTokenKey: "token"}})
+	assert.Nil(t, err)
+	v, err = c.Get()
+	assert.Nil(t, err)
+	assert.Equal(t, "new", v.AccessKeyID)
+	assert.Equal(t, "token", v.SessionToken)
+
+	_, err = m.Invoke(&bindings.InvokeRequest{Operation: RotateCredentialsOperation, Metadata: map[string]string{AuthModeKey: AuthModeWebIdentity}})
+	assert.EqualError(t, err, "unsupported Minio rotateCredentials field authMode")
+	assert.NotContains(t, m.properties, AuthModeKey)
 }
@@ -0,0 +1,193 @@
+package minio
+
+import (
+	"context"
+	"github.com/dapr/kit/logger"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/minio/minio-go/v7/pkg/encrypt"
+	"github.com/pkg/errors"
+	"time"
+)
+
+const (
+	// MirrorEndpointKey enables mirroring: every successful create and delete
+	// is repeated asynchronously on this endpoint.
+	MirrorEndpointKey = "mirrorEndpoint"
+	// MirrorBucketKey is the bucket mirrored to, the binding's bucket by default.
+	MirrorBucketKey        = "mirrorBucket"
+	MirrorAccessKeyKey     = "mirrorAccessKey"
+	MirrorSecretKeyKey     = "mirrorSecretKey"
+	MirrorQueueSizeKey     = "mirrorQueueSize"
+	MirrorRetryIntervalKey = "mirrorRetryInterval"
+	MirrorMaxAttemptsKey   = "mirrorMaxAttempts"
+
+	defaultMirrorQueueSize     = 1000
+	defaultMirrorRetryInterval = 5 * time.Second
+	defaultMirrorMaxAttempts   = 10
+)
+
+// mirror copies changes to a second bucket, a lightweight DR path for
+// deployments without bucket replication. Changes are applied in order by a
+// single worker; a change that keeps failing is retried every interval until
+// maxAttempts, holding back the ones queued after it. Changes that don't fit
+// in the queue, or are still queued when the binding closes, are logged and
+// dropped.
+type mirror struct {
+	source       objectClient
+	sourceBucket string
+	target       objectClient
+	bucket       string
+	queue        chan mirrorTask
+	interval     time.Duration
+	maxAttempts  int64
+	logger       logger.Logger
+}
+
+type mirrorTask struct {
+	key    string
+	delete bool
+	// readSSE is the SSE-C key of the source object, writeSSE the encryption
+	// it was uploaded with
+	readSSE  encrypt.ServerSide
+	writeSSE encrypt.ServerSide
+}
+
+// uploadMirrorTask mirrors an object uploaded with serverSide encryption.
+func uploadMirrorTask(key string, serverSide encrypt.ServerSide) mirrorTask {
+	task := mirrorTask{key: key, writeSSE: serverSide}
+	if serverSide != nil && serverSide.Type() == encrypt.SSEC {
+		task.readSSE = serverSide
+	}
+	return task
+}
+
+// newMirror returns nil when mirroring isn't configured. The mirror connects
+// with options, and with mirrorAccessKey and mirrorSecretKey when set.
+func newMirror(p map[string]string, source objectClient, bucket string, options minio.Options, cooldown time.Duration, log logger.Logger) (*mirror, error) {
+	endpoints, secure, err := parseEndpoints(p[MirrorEndpointKey], options.Secure, "")
+	if err != nil || len(endpoints) == 0 {
+		return nil, err
+	}
+	options.Secure = secure
+	// the endpoint itself has been validated by Init
+	primary, _, _ := parseEndpoints(p[Endpoint], false, p[PortKey])
+	target := p[MirrorBucketKey]
+	if target == "" {
+		target = bucket
+	}
+	if target == bucket && len(primary) > 0 && endpoints[0] == primary[0] {
+		return nil, errors.Errorf("Minio %s must be another endpoint or %s another bucket", MirrorEndpointKey, MirrorBucketKey)
+	}
+	accessKey, secretKey := p[MirrorAccessKeyKey], p[MirrorSecretKeyKey]
+	if (accessKey == "") != (secretKey == "") {
+		return nil, errors.Errorf("Minio %s and %s must be set together", MirrorAccessKeyKey, MirrorSecretKeyKey)
+	}
+	if accessKey != "" {
+		options.Creds = credentials.NewStaticV4(accessKey, secretKey, "")
+	}
+	queueSize, err := sizeProperty(p, MirrorQueueSizeKey, defaultMirrorQueueSize)
+	if err != nil {
+		return nil, err
+	}
+	interval, err := durationProperty(p, MirrorRetryIntervalKey, defaultMirrorRetryInterval)
+	if err != nil {
+		return nil, err
+	}
+	maxAttempts, err := sizeProperty(p, MirrorMaxAttemptsKey, defaultMirrorMaxAttempts)
+	if err != nil {
+		return nil, err
+	}
+	client, _, err := newObjectClient(endpoints, func() *minio.Options {
+		o := options
+		return &o
+	}, cooldown, log)
+	if err != nil {
+		return nil, err
+	}
+	return &mirror{
+		source:       source,
+		sourceBucket: bucket,
+		target:       client,
+		bucket:       target,
+		queue:        make(chan mirrorTask, queueSize),
+		interval:     interval,
+		maxAttempts:  maxAttempts,
+		logger:       log,
+	}, nil
+}
+
+// enqueue schedules a change without waiting for it. It is a no-op when
+// mirroring is disabled.
+func (mr *mirror) enqueue(task mirrorTask) {
+	if mr == nil {
+		return
+	}
+	select {
+	case mr.queue <- task:
+	default:
+		mr.logger.Errorf("Minio mirror queue is full, %s is not mirrored to %s", task.key, mr.bucket)
+	}
+}
+
+// run applies queued changes until ctx is done.
+func (mr *mirror) run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			if n := len(mr.queue); n > 0 {
+				mr.logger.Warnf("Minio mirror stopped with %d changes not mirrored to %s", n, mr.bucket)
+			}
+			return
+		case task := <-mr.queue:
+			mr.process(ctx, task)
+		}
+	}
+}
+
+func (mr *mirror) process(ctx context.Context, task mirrorTask) {
+	for attempt := int64(1); ; attempt++ {
+		err := mr.apply(ctx, task)
+		if err == nil {
+			return
+		}
+		if attempt >= mr.maxAttempts {
+			mr.logger.Errorf("Minio mirror gave up on %s after %d attempts: %s", task.key, attempt, err)
+			return
+		}
+		mr.logger.Warnf("Minio mirror failed on %s (attempt %d), retrying: %s", task.key, attempt, err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(mr.interval):
+		}
+	}
+}
+
+// apply copies the current content of the object, so an upload is mirrored
+// even when it was streamed. An object deleted since is skipped, its delete
+// being queued after it.
+func (mr *mirror) apply(ctx context.Context, task mirrorTask) error {
+	if task.delete {
+		return mr.target.RemoveObject(ctx, mr.bucket, task.key, minio.RemoveObjectOptions{})
+	}
+	reader, err := mr.source.GetObject(ctx, mr.sourceBucket, task.key, minio.GetObjectOptions{ServerSideEncryption: task.readSSE})
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	info, err := reader.Stat()
+	if err != nil {
+		if errorCode(minio.ToErrorResponse(err)) == ErrCodeNotFound {
+			return nil
+		}
+		return err
+	}
+	_, err = mr.target.PutObject(ctx, mr.bucket, task.key, reader, info.Size, minio.PutObjectOptions{
+		ContentType:          info.ContentType,
+		ContentEncoding:      info.Metadata.Get("Content-Encoding"),
+		UserMetadata:         info.UserMetadata,
+		ServerSideEncryption: task.writeSSE,
+	})
+	return err
+}
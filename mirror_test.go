@@ -0,0 +1,139 @@
+package minio
+
+import (
+	"context"
+	"github.com/dapr/components-contrib/bindings"
+	"github.com/dapr/kit/logger"
+	"github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+// newMirrorMinio mirrors a fake binding to bucket dr of a second fake. The
+// worker isn't started, tests apply queued changes with drain.
+func newMirrorMinio() (*Minio, *fakeClient, *mirror) {
+	m, primary := newFakeMinio()
+	target := newFakeClient("dr")
+	m.mirror = &mirror{
+		source:       primary,
+		sourceBucket: "b",
+		target:       target,
+		bucket:       "dr",
+		queue:        make(chan mirrorTask, 2),
+		interval:     time.Millisecond,
+		maxAttempts:  3,
+		logger:       logger.NewLogger("minio"),
+	}
+	return m, target, m.mirror
+}
+
+func drain(mr *mirror) {
+	for len(mr.queue) > 0 {
+		mr.process(context.Background(), <-mr.queue)
+	}
+}
+
+func TestMirrorCreateAndDelete(t *testing.T) {
+	m, target, mr := newMirrorMinio()
+
+	_, err := m.Invoke(&bindings.InvokeRequest{
+		Operation: bindings.CreateOperation,
+		Data:      []byte("hello"),
+		Metadata:  map[string]string{"key": "a.txt"},
+	})
+	assert.Nil(t, err)
+	drain(mr)
+	assert.Equal(t, "hello", string(target.buckets["dr"]["a.txt"].data))
+
+	_, err = m.Invoke(&bindings.InvokeRequest{
+		Operation: bindings.DeleteOperation,
+		Metadata:  map[string]string{"key": "a.txt"},
+	})
+	assert.Nil(t, err)
+	drain(mr)
+	assert.NotContains(t, target.buckets["dr"], "a.txt")
+}
+
+func TestMirrorSkipsFailedWrites(t *testing.T) {
+	m, _, mr := newMirrorMinio()
+
+	_, err := m.Invoke(&bindings.InvokeRequest{
+		Operation: bindings.CreateOperation,
+		Data:      []byte("hello"),
+		Metadata:  map[string]string{"key": "a.txt", "ifMatch": "abc"},
+	})
+	assert.Error(t, err)
+	assert.Len(t, mr.queue, 0)
+}
+
+func TestMirrorRetries(t *testing.T) {
+	m, target, mr := newMirrorMinio()
+	target.err = errRefused
+
+	_, err := m.Invoke(&bindings.InvokeRequest{
+		Operation: bindings.CreateOperation,
+		Data:      []byte("hello"),
+		Metadata:  map[string]string{"key": "a.txt"},
+	})
+	assert.Nil(t, err)
+
+	task := <-mr.queue
+	assert.Error(t, mr.apply(context.Background(), task))
+	target.err = nil
+	mr.process(context.Background(), task)
+	assert.Equal(t, "hello", string(target.buckets["dr"]["a.txt"].data))
+}
+
+func TestMirrorQueueFull(t *testing.T) {
+	m, _, mr := newMirrorMinio()
+
+	for _, key := range []string{"a.txt", "b.txt", "c.txt"} {
+		_, err := m.Invoke(&bindings.InvokeRequest{
+			Operation: bindings.CreateOperation,
+			Data:      []byte("hello"),
+			Metadata:  map[string]string{"key": key},
+		})
+		// the write itself never waits for the mirror
+		assert.Nil(t, err)
+	}
+	assert.Len(t, mr.queue, 2)
+}
+
+func TestMirrorDeletedSince(t *testing.T) {
+	_, target, mr := newMirrorMinio()
+
+	assert.Nil(t, mr.apply(context.Background(), mirrorTask{key: "gone.txt"}))
+	assert.Empty(t, target.buckets["dr"])
+}
+
+func TestNewMirror(t *testing.T) {
+	log := logger.NewLogger("minio")
+	mr, err := newMirror(map[string]string{"endpoint": "minio-0:9000"}, nil, "b", minio.Options{}, time.Minute, log)
+	assert.Nil(t, err)
+	assert.Nil(t, mr)
+
+	mr, err = newMirror(map[string]string{"endpoint": "minio-0:9000", "mirrorEndpoint": "dr:9000", "mirrorQueueSize": "10"}, nil, "b", minio.Options{}, time.Minute, log)
+	assert.Nil(t, err)
+	assert.Equal(t, "b", mr.bucket)
+	assert.Equal(t, 10, cap(mr.queue))
+
+	for _, p := range []map[string]string{
+		{"endpoint": "minio-0:9000", "mirrorEndpoint": "minio-0:9000"},
+		{"endpoint": "minio-0:9000", "mirrorEndpoint": "dr:9000", "mirrorAccessKey": "dr"},
+		{"endpoint": "minio-0:9000", "mirrorEndpoint": "dr:9000", "mirrorRetryInterval": "soon"},
+	} {
+		_, err := newMirror(p, nil, "b", minio.Options{}, time.Minute, log)
+		assert.Error(t, err, "%v", p)
+	}
+}
+
+func TestNewMirrorScheme(t *testing.T) {
+	log := logger.NewLogger("minio")
+	mr, err := newMirror(map[string]string{"endpoint": "minio-0:9000", "mirrorEndpoint": "https://dr:9000"}, nil, "b", minio.Options{}, time.Minute, log)
+	assert.Nil(t, err)
+	assert.NotNil(t, mr)
+
+	_, err = newMirror(map[string]string{"endpoint": "http://minio-0:9000", "mirrorEndpoint": "https://minio-0:9000"}, nil, "b", minio.Options{}, time.Minute, log)
+	assert.Error(t, err)
+}
@@ -0,0 +1,183 @@
+package minio
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/dapr/components-contrib/bindings"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/encrypt"
+	"github.com/pkg/errors"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+const (
+	// MovePrefixOperation renames every object under sourcePrefix to
+	// destinationPrefix with a server-side copy followed by a delete of the
+	// original. Objects are moved with a bounded concurrency; one that fails
+	// is reported and left in place.
+	MovePrefixOperation bindings.OperationKind = "movePrefix"
+
+	SourcePrefixKey      = "sourcePrefix"
+	DestinationPrefixKey = "destinationPrefix"
+)
+
+type moveResult struct {
+	Key         string `json:"key"`
+	Destination string `json:"destination"`
+	Error       string `json:"error,omitempty"`
+	Code        string `json:"code,omitempty"`
+}
+
+type movePrefixResponse struct {
+	SourcePrefix      string       `json:"sourcePrefix"`
+	DestinationPrefix string       `json:"destinationPrefix"`
+	Total             int          `json:"total"`
+	Moved             int64        `json:"moved"`
+	Failed            int64        `json:"failed"`
+	Objects           []moveResult `json:"objects"`
+}
+
+// parsePrefixes reads a source and destination prefix that don't contain one
+// another, so objects written under the destination are never listed again
+// as part of the source.
+func parsePrefixes(p map[string]string) (string, string, error) {
+	source, destination := p[SourcePrefixKey], p[DestinationPrefixKey]
+	if source == "" {
+		return "", "", errors.Errorf("missing %s field", SourcePrefixKey)
+	}
+	if destination == "" {
+		return "", "", errors.Errorf("missing %s field", DestinationPrefixKey)
+	}
+	if strings.HasPrefix(source, destination) || strings.HasPrefix(destination, source) {
+		return "", "", errors.Errorf("Minio %s %s and %s %s overlap", SourcePrefixKey, source, DestinationPrefixKey, destination)
+	}
+	return source, destination, nil
+}
+
+// listPrefix returns every object under prefix in bucket. The listing is
+// complete before anything is changed, so it isn't affected by the changes.
+func (m *Minio) listPrefix(ctx context.Context, client objectClient, bucket, prefix string) ([]minio.ObjectInfo, error) {
+	var objects []minio.ObjectInfo
+	err := m.withRetry(ctx, func() error {
+		objects = nil
+		listCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		for object := range client.ListObjects(listCtx, bucket, minio.ListObjectsOptions{Prefix: prefix, Recursive: true}) {
+			if object.Err != nil {
+				return object.Err
+			}
+			objects = append(objects, object)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("minio binding error. list: %w", err)
+	}
+	return objects, nil
+}
+
+func (m *Minio) movePrefix(ctx context.Context, req *bindings.InvokeRequest) (*bindings.InvokeResponse, error) {
+	p := req.Metadata
+
+	source, destination, err := parsePrefixes(p)
+	if err != nil {
+		return nil, err
+	}
+	concurrency, err := batchConcurrency(p)
+	if err != nil {
+		return nil, err
+	}
+	client, err := m.clientFor(p)
+	if err != nil {
+		return nil, err
+	}
+	client = primaryClient(client)
+	sourceSSE, err := readEncryption(m.properties, p)
+	if err != nil {
+		return nil, err
+	}
+	destSSE, err := serverSideEncryption(m.properties, p)
+	if err != nil {
+		return nil, err
+	}
+
+	objects, err := m.listPrefix(ctx, client, m.Bucket, source)
+	if err != nil {
+		return nil, err
+	}
+	resp := movePrefixResponse{
+		SourcePrefix:      source,
+		DestinationPrefix: destination,
+		Total:             len(objects),
+		Objects:           make([]moveResult, len(objects)),
+	}
+	runBounded(concurrency, len(objects), func(i int) {
+		key := objects[i].Key
+		result := moveResult{Key: key, Destination: destination + strings.TrimPrefix(key, source)}
+		if err := m.moveOne(ctx, client, key, result.Destination, sourceSSE, destSSE); err != nil {
+			err = newError(MovePrefixOperation, err)
+			result.Error, result.Code = err.Error(), ErrorCode(err)
+			atomic.AddInt64(&resp.Failed, 1)
+		} else {
+			atomic.AddInt64(&resp.Moved, 1)
+		}
+		resp.Objects[i] = result
+	})
+	m.logger.Infof("Minio binding moved %d of %d objects from %s to %s", resp.Moved, resp.Total, source, destination)
+
+	jsonResponse, err := json.Marshal(resp)
+	if err != nil {
+		return nil, err
+	}
+	return &bindings.InvokeResponse{
+		Data: jsonResponse,
+		Metadata: map[string]string{
+			"total":  strconv.Itoa(resp.Total),
+			"moved":  strconv.FormatInt(resp.Moved, 10),
+			"failed": strconv.FormatInt(resp.Failed, 10),
+		},
+	}, nil
+}
+
+// moveOne copies key to destination and then removes key. A failed remove
+// leaves both in place.
+func (m *Minio) moveOne(ctx context.Context, client objectClient, key, destination string, sourceSSE, destSSE encrypt.ServerSide) error {
+	if err := m.copyObject(ctx, client, key, m.Bucket, destination, sourceSSE, destSSE); err != nil {
+		return err
+	}
+	return m.removeObject(ctx, client, m.Bucket, key)
+}
+
+// copyObject and removeObject mirror a change only when it is in the
+// binding's bucket, the one the mirror follows.
+func (m *Minio) copyObject(ctx context.Context, client objectClient, key, bucket, destination string, sourceSSE, destSSE encrypt.ServerSide) error {
+	err := m.withRetry(ctx, func() error {
+		_, err := client.CopyObject(ctx,
+			minio.CopyDestOptions{Bucket: bucket, Object: destination, Encryption: destSSE},
+			minio.CopySrcOptions{Bucket: m.Bucket, Object: key, Encryption: sourceSSE})
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("minio binding error. copy: %w", err)
+	}
+	if bucket == m.Bucket {
+		m.mirror.enqueue(uploadMirrorTask(destination, destSSE))
+	}
+	return nil
+}
+
+func (m *Minio) removeObject(ctx context.Context, client objectClient, bucket, key string) error {
+	err := m.withRetry(ctx, func() error {
+		return client.RemoveObject(ctx, bucket, key, minio.RemoveObjectOptions{GovernanceBypass: true})
+	})
+	if err != nil {
+		return fmt.Errorf("minio binding error. remove: %w", err)
+	}
+	if bucket == m.Bucket {
+		m.mirror.enqueue(mirrorTask{key: key, delete: true})
+	}
+	return nil
+}
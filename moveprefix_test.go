@@ -0,0 +1,65 @@
+package minio
+
+import (
+	"encoding/json"
+	"github.com/dapr/components-contrib/bindings"
+	"github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestMovePrefix(t *testing.T) {
+	m, fake := newFakeMinio()
+	for _, key := range []string{"in/a.txt", "in/sub/b.txt", "inbox/c.txt", "other.txt"} {
+		fake.put("b", key, []byte(key), minio.ObjectInfo{ContentType: "text/plain"})
+	}
+
+	resp, err := m.Invoke(&bindings.InvokeRequest{Operation: MovePrefixOperation, Metadata: map[string]string{"sourcePrefix": "in/", "destinationPrefix": "done/"}})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"total": "2", "moved": "2", "failed": "0"}, resp.Metadata)
+
+	var moved movePrefixResponse
+	assert.Nil(t, json.Unmarshal(resp.Data, &moved))
+	assert.Equal(t, []moveResult{
+		{Key: "in/a.txt", Destination: "done/a.txt"},
+		{Key: "in/sub/b.txt", Destination: "done/sub/b.txt"},
+	}, moved.Objects)
+
+	assert.Equal(t, "in/sub/b.txt", string(fake.buckets["b"]["done/sub/b.txt"].data))
+	assert.Equal(t, "text/plain", fake.buckets["b"]["done/a.txt"].info.ContentType)
+	assert.NotContains(t, fake.buckets["b"], "in/a.txt")
+	assert.Contains(t, fake.buckets["b"], "inbox/c.txt")
+	assert.Contains(t, fake.buckets["b"], "other.txt")
+}
+
+func TestMovePrefixEmpty(t *testing.T) {
+	m, _ := newFakeMinio()
+
+	resp, err := m.Invoke(&bindings.InvokeRequest{Operation: MovePrefixOperation, Metadata: map[string]string{"sourcePrefix": "in/", "destinationPrefix": "done/"}})
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"sourcePrefix":"in/","destinationPrefix":"done/","total":0,"moved":0,"failed":0,"objects":[]}`, string(resp.Data))
+}
+
+func TestMovePrefixMirrored(t *testing.T) {
+	m, target, mr := newMirrorMinio()
+	m.minioClient.(*fakeClient).put("b", "in/a.txt", []byte("hello"), minio.ObjectInfo{})
+	target.put("dr", "in/a.txt", []byte("hello"), minio.ObjectInfo{})
+
+	_, err := m.Invoke(&bindings.InvokeRequest{Operation: MovePrefixOperation, Metadata: map[string]string{"sourcePrefix": "in/", "destinationPrefix": "done/"}})
+	assert.Nil(t, err)
+	drain(mr)
+	assert.Equal(t, "hello", string(target.buckets["dr"]["done/a.txt"].data))
+	assert.NotContains(t, target.buckets["dr"], "in/a.txt")
+}
+
+func TestParsePrefixes(t *testing.T) {
+	for msg, p := range map[string]map[string]string{
+		"missing sourcePrefix field":                               {"destinationPrefix": "b/"},
+		"missing destinationPrefix field":                          {"sourcePrefix": "a/"},
+		"Minio sourcePrefix a/ and destinationPrefix a/b/ overlap": {"sourcePrefix": "a/", "destinationPrefix": "a/b/"},
+		"Minio sourcePrefix a/b/ and destinationPrefix a/ overlap": {"sourcePrefix": "a/b/", "destinationPrefix": "a/"},
+	} {
+		_, _, err := parsePrefixes(p)
+		assert.EqualError(t, err, msg)
+	}
+}
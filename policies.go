@@ -0,0 +1,86 @@
+package minio
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/dapr/components-contrib/bindings"
+	"github.com/pkg/errors"
+)
+
+const (
+	PolicyNameKey = "policyName"
+	GroupKey      = "group"
+
+	CreatePolicyOperation bindings.OperationKind = "createPolicy"
+	AttachPolicyOperation bindings.OperationKind = "attachPolicy"
+)
+
+type policyResponse struct {
+	Policy string `json:"policy"`
+	User   string `json:"user,omitempty"`
+	Group  string `json:"group,omitempty"`
+}
+
+func policyName(p map[string]string) (string, error) {
+	name := p[PolicyNameKey]
+	if name == "" {
+		return "", errors.Errorf("missing %s field", PolicyNameKey)
+	}
+	return name, nil
+}
+
+func policyResult(resp policyResponse) (*bindings.InvokeResponse, error) {
+	jsonResponse, err := json.Marshal(resp)
+	if err != nil {
+		return nil, err
+	}
+	return &bindings.InvokeResponse{
+		Data:     jsonResponse,
+		Metadata: map[string]string{"policy": resp.Policy},
+	}, nil
+}
+
+// createPolicy adds or replaces a canned IAM policy. The request data is the
+// policy document.
+func createPolicy(ctx context.Context, admin adminClient, req *bindings.InvokeRequest) (*bindings.InvokeResponse, error) {
+	name, err := policyName(req.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	if len(req.Data) == 0 {
+		return nil, errors.Errorf("missing policy document")
+	}
+	if !json.Valid(req.Data) {
+		return nil, errors.Errorf("policy document %s is not valid JSON", name)
+	}
+	if err := admin.AddCannedPolicy(ctx, name, req.Data); err != nil {
+		return nil, fmt.Errorf("minio binding error. create policy: %w", err)
+	}
+	return policyResult(policyResponse{Policy: name})
+}
+
+// attachPolicy sets the policies of a user or a group. policyName may list
+// several comma-separated policies, and replaces the ones attached before.
+func attachPolicy(ctx context.Context, admin adminClient, p map[string]string) (*bindings.InvokeResponse, error) {
+	name, err := policyName(p)
+	if err != nil {
+		return nil, err
+	}
+	user, group := p[UserAccessKeyKey], p[GroupKey]
+	switch {
+	case user == "" && group == "":
+		return nil, errors.Errorf("missing %s or %s field", UserAccessKeyKey, GroupKey)
+	case user != "" && group != "":
+		return nil, errors.Errorf("%s and %s are mutually exclusive", UserAccessKeyKey, GroupKey)
+	}
+
+	entity := user
+	if group != "" {
+		entity = group
+	}
+	if err := admin.SetPolicy(ctx, name, entity, group != ""); err != nil {
+		return nil, fmt.Errorf("minio binding error. attach policy: %w", err)
+	}
+	return policyResult(policyResponse{Policy: name, User: user, Group: group})
+}
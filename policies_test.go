@@ -0,0 +1,83 @@
+package minio
+
+import (
+	"github.com/dapr/components-contrib/bindings"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+const tenantPolicy = `{
+	"Version": "2012-10-17",
+	"Statement": [{
+		"Effect": "Allow",
+		"Action": ["s3:GetObject", "s3:PutObject"],
+		"Resource": ["arn:aws:s3:::b/tenant-a/*"]
+	}]
+}`
+
+func TestCreatePolicy(t *testing.T) {
+	m, admin := newFakeAdminMinio()
+
+	resp, err := m.Invoke(&bindings.InvokeRequest{
+		Operation: CreatePolicyOperation,
+		Data:      []byte(tenantPolicy),
+		Metadata:  map[string]string{"policyName": "tenant-a-rw"},
+	})
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"policy":"tenant-a-rw"}`, string(resp.Data))
+	assert.Equal(t, tenantPolicy, string(admin.policies["tenant-a-rw"]))
+
+	_, err = m.Invoke(&bindings.InvokeRequest{
+		Operation: CreatePolicyOperation,
+		Data:      []byte(`{"Version":`),
+		Metadata:  map[string]string{"policyName": "broken"},
+	})
+	assert.Error(t, err)
+	_, err = m.Invoke(&bindings.InvokeRequest{
+		Operation: CreatePolicyOperation,
+		Metadata:  map[string]string{"policyName": "empty"},
+	})
+	assert.Error(t, err)
+	_, err = m.Invoke(&bindings.InvokeRequest{
+		Operation: CreatePolicyOperation,
+		Data:      []byte(tenantPolicy),
+	})
+	assert.Error(t, err)
+	assert.Len(t, admin.policies, 1)
+}
+
+func TestAttachPolicy(t *testing.T) {
+	m, admin := newFakeAdminMinio()
+
+	resp, err := m.Invoke(&bindings.InvokeRequest{
+		Operation: AttachPolicyOperation,
+		Metadata:  map[string]string{"policyName": "tenant-a-rw", "userAccessKey": "tenant-a"},
+	})
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"policy":"tenant-a-rw","user":"tenant-a"}`, string(resp.Data))
+
+	resp, err = m.Invoke(&bindings.InvokeRequest{
+		Operation: AttachPolicyOperation,
+		Metadata:  map[string]string{"policyName": "readonly,tenant-a-rw", "group": "tenant-a-admins"},
+	})
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"policy":"readonly,tenant-a-rw","group":"tenant-a-admins"}`, string(resp.Data))
+	assert.Equal(t, map[string]string{
+		"user:tenant-a":         "tenant-a-rw",
+		"group:tenant-a-admins": "readonly,tenant-a-rw",
+	}, admin.attached)
+}
+
+func TestAttachPolicyValidation(t *testing.T) {
+	m, admin := newFakeAdminMinio()
+
+	for _, metadata := range []map[string]string{
+		{"userAccessKey": "tenant-a"},
+		{"policyName": "tenant-a-rw"},
+		{"policyName": "tenant-a-rw", "userAccessKey": "tenant-a", "group": "admins"},
+	} {
+		_, err := m.Invoke(&bindings.InvokeRequest{Operation: AttachPolicyOperation, Metadata: metadata})
+		assert.Error(t, err, "%v", metadata)
+	}
+	assert.Empty(t, admin.attached)
+}
@@ -0,0 +1,86 @@
+package minio
+
+import (
+	"context"
+	"fmt"
+	"github.com/minio/minio-go/v7"
+	"github.com/pkg/errors"
+	"net/http"
+	"strings"
+)
+
+const (
+	IfMatchKey     = "ifMatch"
+	IfNoneMatchKey = "ifNoneMatch"
+)
+
+func hasPreconditions(p map[string]string) bool {
+	return p[IfMatchKey] != "" || p[IfNoneMatchKey] != ""
+}
+
+// checkPreconditions reads the current object and evaluates the ifMatch and
+// ifNoneMatch request metadata against it before a write. An upload also
+// sends an ifMatch ETag as If-Match, which MinIO evaluates atomically with
+// the write; the * forms and deletes rely on this check alone, so two
+// writers racing between the check and the write can both succeed.
+func (m *Minio) checkPreconditions(ctx context.Context, client objectClient, objectName string, p map[string]string) error {
+	if !hasPreconditions(p) {
+		return nil
+	}
+	serverSide, err := readEncryption(m.properties, p)
+	if err != nil {
+		return err
+	}
+	exists := true
+	var info minio.ObjectInfo
+	err = m.withRetry(ctx, func() error {
+		info, err = primaryClient(client).StatObject(ctx, m.Bucket, objectName, minio.StatObjectOptions{ServerSideEncryption: serverSide})
+		return err
+	})
+	if err != nil {
+		if errorCode(minio.ToErrorResponse(err)) != ErrCodeNotFound {
+			return fmt.Errorf("minio binding error. check preconditions: %w", err)
+		}
+		exists = false
+	}
+	return evaluatePreconditions(p, m.Bucket, objectName, exists, info.ETag)
+}
+
+// conditionalPut makes the upload conditional on the ifMatch ETag.
+func conditionalPut(opts *minio.PutObjectOptions, p map[string]string) {
+	if ifMatch := p[IfMatchKey]; ifMatch != "" && ifMatch != "*" {
+		opts.SetMatchETag(trimETag(ifMatch))
+	}
+}
+
+// evaluatePreconditions fails with a PreconditionFailed error when the object
+// doesn't carry the ifMatch ETag, or exists although ifNoneMatch is *.
+func evaluatePreconditions(p map[string]string, bucket, objectName string, exists bool, etag string) error {
+	ifMatch, ifNoneMatch := p[IfMatchKey], p[IfNoneMatchKey]
+	if ifNoneMatch != "" && ifNoneMatch != "*" {
+		return errors.Errorf("unsupported Minio ifNoneMatch %s, only * is supported", ifNoneMatch)
+	}
+
+	var reason string
+	switch {
+	case ifMatch != "" && !exists:
+		reason = "object does not exist"
+	case ifMatch != "" && ifMatch != "*" && trimETag(ifMatch) != trimETag(etag):
+		reason = fmt.Sprintf("ETag %s does not match ifMatch %s", trimETag(etag), trimETag(ifMatch))
+	case ifNoneMatch == "*" && exists:
+		reason = "object already exists"
+	default:
+		return nil
+	}
+	return minio.ErrorResponse{
+		Code:       "PreconditionFailed",
+		Message:    reason,
+		BucketName: bucket,
+		Key:        objectName,
+		StatusCode: http.StatusPreconditionFailed,
+	}
+}
+
+func trimETag(etag string) string {
+	return strings.Trim(etag, `"`)
+}
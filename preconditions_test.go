@@ -0,0 +1,64 @@
+package minio
+
+import (
+	"github.com/dapr/components-contrib/bindings"
+	"github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestEvaluatePreconditions(t *testing.T) {
+	tests := []struct {
+		name   string
+		p      map[string]string
+		exists bool
+		etag   string
+		failed bool
+	}{
+		{"no preconditions", map[string]string{}, true, "e1", false},
+		{"matching etag", map[string]string{IfMatchKey: "e1"}, true, "e1", false},
+		{"quoted etag", map[string]string{IfMatchKey: `"e1"`}, true, "e1", false},
+		{"stale etag", map[string]string{IfMatchKey: "e0"}, true, "e1", true},
+		{"etag of missing object", map[string]string{IfMatchKey: "e1"}, false, "", true},
+		{"any etag", map[string]string{IfMatchKey: "*"}, true, "e1", false},
+		{"create only", map[string]string{IfNoneMatchKey: "*"}, false, "", false},
+		{"create only on existing object", map[string]string{IfNoneMatchKey: "*"}, true, "e1", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := evaluatePreconditions(tt.p, "b", "k", tt.exists, tt.etag)
+			if tt.failed {
+				assert.Equal(t, ErrCodePreconditionFailed, errorCode(minio.ToErrorResponse(err)))
+			} else {
+				assert.Nil(t, err)
+			}
+		})
+	}
+
+	err := evaluatePreconditions(map[string]string{IfNoneMatchKey: "e1"}, "b", "k", true, "e1")
+	assert.EqualError(t, err, "unsupported Minio ifNoneMatch e1, only * is supported")
+}
+
+func TestConditionalWrites(t *testing.T) {
+	m, _ := newFakeMinio()
+	create := func(metadata map[string]string) (*bindings.InvokeResponse, error) {
+		metadata["objectName"] = "k"
+		return m.Invoke(&bindings.InvokeRequest{Operation: bindings.CreateOperation, Data: []byte("v"), Metadata: metadata})
+	}
+
+	resp, err := create(map[string]string{IfNoneMatchKey: "*"})
+	assert.Nil(t, err)
+	etag := resp.Metadata["etag"]
+
+	_, err = create(map[string]string{IfNoneMatchKey: "*"})
+	assert.Equal(t, ErrCodePreconditionFailed, ErrorCode(err))
+	_, err = create(map[string]string{IfMatchKey: "stale"})
+	assert.Equal(t, ErrCodePreconditionFailed, ErrorCode(err))
+	_, err = create(map[string]string{IfMatchKey: etag})
+	assert.Nil(t, err)
+
+	_, err = m.Invoke(&bindings.InvokeRequest{Operation: bindings.DeleteOperation, Metadata: map[string]string{"objectName": "k", IfMatchKey: "stale"}})
+	assert.Equal(t, ErrCodePreconditionFailed, ErrorCode(err))
+	_, err = m.Invoke(&bindings.InvokeRequest{Operation: bindings.DeleteOperation, Metadata: map[string]string{"objectName": "k", IfMatchKey: etag}})
+	assert.Nil(t, err)
+}
@@ -0,0 +1,348 @@
+package minio
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/dapr/components-contrib/bindings"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/pkg/errors"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	PresignedPutOperation bindings.OperationKind = "presignedPut"
+	// PresignedPostOperation returns a URL and the form fields a browser
+	// uploads an object with in a multipart/form-data POST. Unlike a PUT, its
+	// policy can limit the size of the upload.
+	PresignedPostOperation bindings.OperationKind = "presignedPost"
+
+	// MinContentLengthKey and MaxContentLengthKey bound the size of a
+	// presignedPost upload. contentType pins the Content-Type a presignedPut
+	// or presignedPost upload must be sent with.
+	MinContentLengthKey = "minContentLength"
+	MaxContentLengthKey = "maxContentLength"
+
+	// PresignEndpointKey is the public hostname, or CDN or accelerated
+	// endpoint, presigned URLs point to instead of the endpoint. Only the
+	// signature is computed locally, so the sidecar doesn't need to reach it.
+	PresignEndpointKey = "presignEndpoint"
+
+	// defaultPresignRegion is the MinIO default region, signed for when no
+	// region is configured, since looking up the bucket location would
+	// contact the presign endpoint.
+	defaultPresignRegion = "us-east-1"
+
+	// PresignParamPrefix marks request metadata added to the signed query
+	// string of presigned URLs, as in presignParam-versionId.
+	PresignParamPrefix = "presignParam-"
+
+	// DownloadFileNameKey is the file name browsers save a presignedGet
+	// download as.
+	DownloadFileNameKey = "downloadFileName"
+
+	// DefaultPresignExpiryKey is how long presigned URLs are valid for when
+	// the request has no expires.
+	DefaultPresignExpiryKey = "defaultPresignExpiry"
+	// MaxPresignExpiryKey is the longest expires a request may ask for, so
+	// presigned URLs can't outlive the policy for them.
+	MaxPresignExpiryKey = "maxPresignExpiry"
+
+	// presignExpiryLimit is the longest validity S3 accepts for a signature.
+	presignExpiryLimit = 7 * 24 * time.Hour
+)
+
+// presignExpires returns the request's expires, or defaultPresignExpiry when
+// it has none, refusing one longer than maxPresignExpiry.
+func (m *Minio) presignExpires(p map[string]string) (time.Duration, error) {
+	duration, ok := p["expires"]
+	if !ok || duration == "" {
+		if m.defaultPresignExpiry == 0 {
+			return 0, errors.Errorf("missing duration field")
+		}
+		return m.defaultPresignExpiry, nil
+	}
+	expires, err := time.ParseDuration(duration)
+	if err != nil {
+		return 0, errors.Errorf("expires %s is invalid", duration)
+	}
+	if m.maxPresignExpiry > 0 && expires > m.maxPresignExpiry {
+		return 0, errors.Errorf("expires %s is longer than the %s %s", duration, MaxPresignExpiryKey, m.maxPresignExpiry)
+	}
+	return expires, nil
+}
+
+// parsePresignExpiries reads defaultPresignExpiry and maxPresignExpiry,
+// neither longer than S3 allows, nor the default longer than the maximum.
+func parsePresignExpiries(p map[string]string) (time.Duration, time.Duration, error) {
+	var expiries [2]time.Duration
+	for i, key := range []string{DefaultPresignExpiryKey, MaxPresignExpiryKey} {
+		expiry, err := durationProperty(p, key, 0)
+		if err != nil {
+			return 0, 0, err
+		}
+		if expiry > presignExpiryLimit {
+			return 0, 0, errors.Errorf("Minio %s %s is longer than the %s S3 allows", key, p[key], presignExpiryLimit)
+		}
+		expiries[i] = expiry
+	}
+	defaultExpiry, maxExpiry := expiries[0], expiries[1]
+	if maxExpiry > 0 && defaultExpiry > maxExpiry {
+		return 0, 0, errors.Errorf("Minio %s %s is longer than %s %s", DefaultPresignExpiryKey, p[DefaultPresignExpiryKey], MaxPresignExpiryKey, p[MaxPresignExpiryKey])
+	}
+	return defaultExpiry, maxExpiry, nil
+}
+
+func (m *Minio) presignedPut(ctx context.Context, req *bindings.InvokeRequest) (*bindings.InvokeResponse, error) {
+	p := req.Metadata
+
+	objectName := objectNameOf(p)
+	if objectName == "" {
+		return nil, errors.Errorf("missing name field")
+	}
+	expires, err := m.presignExpires(p)
+	if err != nil {
+		return nil, err
+	}
+	sse, err := serverSideEncryption(m.properties, p)
+	if err != nil {
+		return nil, err
+	}
+	params, err := presignParams(p)
+	if err != nil {
+		return nil, err
+	}
+	// a PUT can't be limited to a size range, only a POST policy can
+	for _, key := range []string{MinContentLengthKey, MaxContentLengthKey} {
+		if p[key] != "" {
+			return nil, errors.Errorf("Minio %s is only enforced by %s", key, PresignedPostOperation)
+		}
+	}
+
+	client, err := m.presignClientFor(p)
+	if err != nil {
+		return nil, err
+	}
+
+	h := http.Header{}
+	if sse != nil {
+		sse.Marshal(h)
+	}
+	if contentType := p[ContentTypeKey]; contentType != "" {
+		h.Set("Content-Type", contentType)
+	}
+	if len(h) > 0 {
+		return presignWithHeaders(ctx, client, http.MethodPut, m.Bucket, objectName, expires, params, h)
+	}
+	var result *url.URL
+	if params != nil {
+		result, err = client.PresignHeader(ctx, http.MethodPut, m.Bucket, objectName, expires, params, nil)
+	} else {
+		result, err = client.PresignedPutObject(ctx, m.Bucket, objectName, expires)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("presigned object error: %w", err)
+	}
+	return newPresignResponse(http.MethodPut, result, expires, nil)
+}
+
+func (m *Minio) presignedPost(ctx context.Context, req *bindings.InvokeRequest) (*bindings.InvokeResponse, error) {
+	p := req.Metadata
+
+	objectName := objectNameOf(p)
+	if objectName == "" {
+		return nil, errors.Errorf("missing name field")
+	}
+	expires, err := m.presignExpires(p)
+	if err != nil {
+		return nil, err
+	}
+	// the encryption headers would have to be form fields the policy allows
+	sse, err := serverSideEncryption(m.properties, p)
+	if err != nil {
+		return nil, err
+	}
+	if sse != nil {
+		return nil, errors.Errorf("minio binding error. %s does not support encryption, use %s", PresignedPostOperation, PresignedPutOperation)
+	}
+	minLength, err := sizeProperty(p, MinContentLengthKey, 0)
+	if err != nil {
+		return nil, err
+	}
+	maxLength, err := sizeProperty(p, MaxContentLengthKey, 0)
+	if err != nil {
+		return nil, err
+	}
+	if minLength > 0 && maxLength == 0 {
+		return nil, errors.Errorf("missing %s field", MaxContentLengthKey)
+	}
+	if minLength > maxLength {
+		return nil, errors.Errorf("Minio %s %d is larger than %s %d", MinContentLengthKey, minLength, MaxContentLengthKey, maxLength)
+	}
+
+	expiresAt := time.Now().UTC().Add(expires).Truncate(time.Second)
+	policy := minio.NewPostPolicy()
+	if err := policy.SetBucket(m.Bucket); err != nil {
+		return nil, err
+	}
+	if err := policy.SetKey(objectName); err != nil {
+		return nil, err
+	}
+	if err := policy.SetExpires(expiresAt); err != nil {
+		return nil, err
+	}
+	if contentType := p[ContentTypeKey]; contentType != "" {
+		if err := policy.SetContentType(contentType); err != nil {
+			return nil, err
+		}
+	}
+	if maxLength > 0 {
+		if err := policy.SetContentLengthRange(minLength, maxLength); err != nil {
+			return nil, err
+		}
+	}
+
+	client, err := m.presignClientFor(p)
+	if err != nil {
+		return nil, err
+	}
+	u, formData, err := client.PresignedPostPolicy(ctx, policy)
+	if err != nil {
+		return nil, fmt.Errorf("presigned object error: %w", err)
+	}
+	jsonResponse, err := json.Marshal(presignResponse{URL: u.String(), Method: http.MethodPost, ExpiresAt: expiresAt, FormData: formData})
+	if err != nil {
+		return nil, err
+	}
+	return &bindings.InvokeResponse{Data: jsonResponse}, nil
+}
+
+// presignParams returns the presignParam-* request metadata as query
+// parameters, nil when there are none. The X-Amz-* parameters are reserved
+// for the signature.
+func presignParams(p map[string]string) (url.Values, error) {
+	var params url.Values
+	for k, v := range p {
+		if !strings.HasPrefix(k, PresignParamPrefix) {
+			continue
+		}
+		name := strings.TrimPrefix(k, PresignParamPrefix)
+		if name == "" || strings.HasPrefix(strings.ToLower(name), "x-amz-") {
+			return nil, errors.Errorf("unsupported Minio presign parameter %s", name)
+		}
+		if params == nil {
+			params = url.Values{}
+		}
+		params.Set(name, v)
+	}
+	return params, nil
+}
+
+// withDownloadFileName adds the response-content-disposition parameter for
+// downloadFileName to params. S3 answers with it as Content-Disposition;
+// names that aren't plain ASCII are encoded as RFC 2231 describes.
+func withDownloadFileName(params url.Values, p map[string]string) url.Values {
+	name := p[DownloadFileNameKey]
+	if name == "" {
+		return params
+	}
+	if params == nil {
+		params = url.Values{}
+	}
+	params.Set("response-content-disposition", mime.FormatMediaType("attachment", map[string]string{"filename": name}))
+	return params
+}
+
+// presignResponse describes a presigned request: the URL, the method to send
+// it with, when it stops being valid, and the headers that were signed and
+// must be sent along.
+type presignResponse struct {
+	URL       string            `json:"url"`
+	Method    string            `json:"method"`
+	ExpiresAt time.Time         `json:"expiresAt"`
+	Headers   map[string]string `json:"headers,omitempty"`
+	FormData  map[string]string `json:"formData,omitempty"`
+}
+
+// newPresignResponse returns the JSON presignResponse for a signed URL. The
+// signed headers are also returned as response metadata, as they were before
+// the response was JSON.
+func newPresignResponse(method string, u *url.URL, expires time.Duration, h http.Header) (*bindings.InvokeResponse, error) {
+	// the URL is valid for expires from the time it was signed at
+	signedAt, err := time.Parse("20060102T150405Z", u.Query().Get("X-Amz-Date"))
+	if err != nil {
+		signedAt = time.Now().UTC().Truncate(time.Second)
+	}
+	resp := presignResponse{URL: u.String(), Method: method, ExpiresAt: signedAt.Add(expires)}
+	var metadata map[string]string
+	if len(h) > 0 {
+		resp.Headers = make(map[string]string, len(h))
+		for k := range h {
+			resp.Headers[k] = h.Get(k)
+		}
+		metadata = resp.Headers
+	}
+	jsonResponse, err := json.Marshal(resp)
+	if err != nil {
+		return nil, err
+	}
+	return &bindings.InvokeResponse{Data: jsonResponse, Metadata: metadata}, nil
+}
+
+// presignWithHeaders signs headers, such as the encryption headers, into the
+// URL. S3 only accepts them as request headers, not query parameters, so they
+// are returned for the caller to send along; for SSE-C that includes the
+// caller's own key.
+func presignWithHeaders(ctx context.Context, client objectClient, method, bucket, objectName string, expires time.Duration, params url.Values, h http.Header) (*bindings.InvokeResponse, error) {
+	result, err := client.PresignHeader(ctx, method, bucket, objectName, expires, params, h)
+	if err != nil {
+		return nil, fmt.Errorf("presigned object error: %w", err)
+	}
+	return newPresignResponse(method, result, expires, h)
+}
+
+// presignClientFor returns the client to presign a request's URLs with: the
+// request's own client, or with presignEndpoint set, one for that endpoint
+// with the same credentials and region.
+func (m *Minio) presignClientFor(p map[string]string) (objectClient, error) {
+	client, err := m.clientFor(p)
+	if err != nil || m.presignEndpoint == "" {
+		return client, err
+	}
+	creds := m.credentials.clientCredentials()
+	if accessKey := p[AccessKey]; accessKey != "" {
+		creds = credentials.NewStaticV4(accessKey, p[SecretAccessKey], p[SessionTokenKey])
+	}
+	region := m.signingRegionFor(m.requestRegion(p))
+	if region == "" {
+		region = defaultPresignRegion
+	}
+	client, _, err = newObjectClient([]string{m.presignEndpoint}, func() *minio.Options {
+		return &minio.Options{
+			Creds:        creds,
+			Secure:       m.presignSecure,
+			Transport:    m.transport,
+			Region:       region,
+			BucketLookup: m.bucketLookup,
+		}
+	}, m.failoverCooldown, m.logger)
+	return client, err
+}
+
+// parsePresignEndpoint returns the presignEndpoint host and whether it uses
+// TLS, secure unless it is given as a URL.
+func parsePresignEndpoint(p map[string]string, secure bool) (string, bool, error) {
+	endpoints, secure, err := parseEndpoints(p[PresignEndpointKey], secure, "")
+	if err != nil || len(endpoints) == 0 {
+		return "", secure, err
+	}
+	if len(endpoints) > 1 {
+		return "", secure, errors.Errorf("Minio %s must be a single endpoint", PresignEndpointKey)
+	}
+	return endpoints[0], secure, nil
+}
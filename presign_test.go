@@ -0,0 +1,236 @@
+package minio
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/dapr/components-contrib/bindings"
+	"github.com/dapr/kit/logger"
+	"github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// presigned decodes the JSON response of a presign operation.
+func presigned(t *testing.T, data []byte) presignResponse {
+	var resp presignResponse
+	assert.Nil(t, json.Unmarshal(data, &resp))
+	return resp
+}
+
+func TestPresignResponse(t *testing.T) {
+	m, _ := newFakeMinio()
+
+	before := time.Now().Truncate(time.Second)
+	resp, err := m.Invoke(&bindings.InvokeRequest{Operation: PresignedGetOperation, Metadata: map[string]string{"key": "a.txt", "expires": "10m"}})
+	assert.Nil(t, err)
+	get := presigned(t, resp.Data)
+	assert.Equal(t, http.MethodGet, get.Method)
+	assert.Contains(t, get.URL, "/b/a.txt?")
+	assert.False(t, get.ExpiresAt.Before(before.Add(10*time.Minute)))
+	assert.False(t, get.ExpiresAt.After(time.Now().Add(10*time.Minute)))
+	assert.Empty(t, get.Headers)
+
+	resp, err = m.Invoke(&bindings.InvokeRequest{Operation: PresignedPutOperation, Metadata: map[string]string{"key": "a.txt", "expires": "10m"}})
+	assert.Nil(t, err)
+	assert.Equal(t, http.MethodPut, presigned(t, resp.Data).Method)
+}
+
+func TestNewPresignResponse(t *testing.T) {
+	u, _ := url.Parse("https://minio:9000/b/a.txt?X-Amz-Date=20240102T030405Z&X-Amz-Expires=3600")
+	h := http.Header{}
+	h.Set("X-Amz-Server-Side-Encryption", "aws:kms")
+
+	resp, err := newPresignResponse(http.MethodPut, u, time.Hour, h)
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{
+		"url": "https://minio:9000/b/a.txt?X-Amz-Date=20240102T030405Z&X-Amz-Expires=3600",
+		"method": "PUT",
+		"expiresAt": "2024-01-02T04:04:05Z",
+		"headers": {"X-Amz-Server-Side-Encryption": "aws:kms"}
+	}`, string(resp.Data))
+	// the headers are still returned as metadata
+	assert.Equal(t, "aws:kms", resp.Metadata["X-Amz-Server-Side-Encryption"])
+}
+
+func TestPresignExpires(t *testing.T) {
+	m := NewMinio(logger.NewLogger("minio"))
+	expires, err := m.presignExpires(map[string]string{"expires": "90s"})
+	assert.Nil(t, err)
+	assert.Equal(t, 90*time.Second, expires)
+
+	_, err = m.presignExpires(map[string]string{})
+	assert.EqualError(t, err, "missing duration field")
+
+	_, err = m.presignExpires(map[string]string{"expires": "later"})
+	assert.EqualError(t, err, "expires later is invalid")
+}
+
+func TestDefaultPresignExpiry(t *testing.T) {
+	m, _ := newFakeMinio()
+	m.defaultPresignExpiry = 15 * time.Minute
+
+	expires, err := m.presignExpires(map[string]string{})
+	assert.Nil(t, err)
+	assert.Equal(t, 15*time.Minute, expires)
+	expires, _ = m.presignExpires(map[string]string{"expires": "1m"})
+	assert.Equal(t, time.Minute, expires)
+
+	resp, err := m.Invoke(&bindings.InvokeRequest{Operation: PresignedGetOperation, Metadata: map[string]string{"key": "a.txt"}})
+	assert.Nil(t, err)
+	assert.Contains(t, presigned(t, resp.Data).URL, "X-Amz-Expires=900")
+}
+
+func TestParsePresignExpiries(t *testing.T) {
+	defaultExpiry, maxExpiry, err := parsePresignExpiries(map[string]string{})
+	assert.Nil(t, err)
+	assert.Equal(t, time.Duration(0), defaultExpiry)
+	assert.Equal(t, time.Duration(0), maxExpiry)
+
+	defaultExpiry, maxExpiry, err = parsePresignExpiries(map[string]string{"defaultPresignExpiry": "15m", "maxPresignExpiry": "1h"})
+	assert.Nil(t, err)
+	assert.Equal(t, 15*time.Minute, defaultExpiry)
+	assert.Equal(t, time.Hour, maxExpiry)
+
+	_, _, err = parsePresignExpiries(map[string]string{"defaultPresignExpiry": "200h"})
+	assert.EqualError(t, err, "Minio defaultPresignExpiry 200h is longer than the 168h0m0s S3 allows")
+	_, _, err = parsePresignExpiries(map[string]string{"maxPresignExpiry": "200h"})
+	assert.EqualError(t, err, "Minio maxPresignExpiry 200h is longer than the 168h0m0s S3 allows")
+	_, _, err = parsePresignExpiries(map[string]string{"defaultPresignExpiry": "2h", "maxPresignExpiry": "1h"})
+	assert.EqualError(t, err, "Minio defaultPresignExpiry 2h is longer than maxPresignExpiry 1h")
+	_, _, err = parsePresignExpiries(map[string]string{"defaultPresignExpiry": "-1m"})
+	assert.Error(t, err)
+}
+
+func TestMaxPresignExpiry(t *testing.T) {
+	m, _ := newFakeMinio()
+	m.maxPresignExpiry = time.Hour
+
+	expires, err := m.presignExpires(map[string]string{"expires": "1h"})
+	assert.Nil(t, err)
+	assert.Equal(t, time.Hour, expires)
+
+	for _, op := range []bindings.OperationKind{PresignedGetOperation, PresignedPutOperation} {
+		_, err = m.Invoke(&bindings.InvokeRequest{Operation: op, Metadata: map[string]string{"key": "a.txt", "expires": "24h"}})
+		assert.EqualError(t, err, "expires 24h is longer than the maxPresignExpiry 1h0m0s")
+	}
+}
+
+func TestOversizedGetPresignCapped(t *testing.T) {
+	m, fake := newFakeMinio()
+	fake.put("b", "big.bin", make([]byte, 100), minio.ObjectInfo{})
+	m.maxGetSize = 10
+	m.oversizedGet = OversizedGetPresign
+	m.maxPresignExpiry = 5 * time.Minute
+
+	resp, err := m.Invoke(&bindings.InvokeRequest{Operation: bindings.GetOperation, Metadata: map[string]string{"key": "big.bin"}})
+	assert.Nil(t, err)
+	assert.Contains(t, resp.Metadata["presignedURL"], "X-Amz-Expires=300")
+}
+
+func TestPresignedPutValidation(t *testing.T) {
+	m := NewMinio(logger.NewLogger("minio"))
+
+	_, err := m.presignedPut(context.Background(), &bindings.InvokeRequest{Metadata: map[string]string{"expires": "1m"}})
+	assert.EqualError(t, err, "missing name field")
+
+	_, err = m.presignedPut(context.Background(), &bindings.InvokeRequest{Metadata: map[string]string{
+		"objectName":  "a",
+		"expires":     "1m",
+		EncryptionKey: EncryptionSSEC,
+	}})
+	assert.EqualError(t, err, "missing Minio sseCustomerKey string")
+}
+
+func TestParsePresignEndpoint(t *testing.T) {
+	endpoint, secure, err := parsePresignEndpoint(map[string]string{}, true)
+	assert.Nil(t, err)
+	assert.Equal(t, "", endpoint)
+	assert.True(t, secure)
+
+	endpoint, secure, err = parsePresignEndpoint(map[string]string{"presignEndpoint": "https://files.example.com"}, false)
+	assert.Nil(t, err)
+	assert.Equal(t, "files.example.com", endpoint)
+	assert.True(t, secure)
+
+	endpoint, secure, err = parsePresignEndpoint(map[string]string{"presignEndpoint": "files.example.com:9000"}, false)
+	assert.Nil(t, err)
+	assert.Equal(t, "files.example.com:9000", endpoint)
+	assert.False(t, secure)
+
+	_, _, err = parsePresignEndpoint(map[string]string{"presignEndpoint": "a.example.com,b.example.com"}, false)
+	assert.Error(t, err)
+}
+
+func TestPresignParams(t *testing.T) {
+	params, err := presignParams(map[string]string{"objectName": "a"})
+	assert.Nil(t, err)
+	assert.Nil(t, params)
+
+	params, err = presignParams(map[string]string{"objectName": "a", "presignParam-versionId": "v1", "presignParam-response-content-type": "text/csv"})
+	assert.Nil(t, err)
+	assert.Equal(t, "v1", params.Get("versionId"))
+	assert.Equal(t, "text/csv", params.Get("response-content-type"))
+
+	_, err = presignParams(map[string]string{"presignParam-X-Amz-Expires": "604800"})
+	assert.EqualError(t, err, "unsupported Minio presign parameter X-Amz-Expires")
+	_, err = presignParams(map[string]string{"presignParam-": "x"})
+	assert.Error(t, err)
+}
+
+func TestWithDownloadFileName(t *testing.T) {
+	assert.Nil(t, withDownloadFileName(nil, map[string]string{}))
+
+	params := withDownloadFileName(nil, map[string]string{"downloadFileName": "Q3 report.pdf"})
+	assert.Equal(t, `attachment; filename="Q3 report.pdf"`, params.Get("response-content-disposition"))
+
+	params = withDownloadFileName(url.Values{"versionId": {"v1"}}, map[string]string{"downloadFileName": "résumé.pdf"})
+	assert.Equal(t, "attachment; filename*=utf-8''r%C3%A9sum%C3%A9.pdf", params.Get("response-content-disposition"))
+	assert.Equal(t, "v1", params.Get("versionId"))
+
+	// header injection is not possible
+	params = withDownloadFileName(nil, map[string]string{"downloadFileName": "a\r\nSet-Cookie: x"})
+	assert.NotContains(t, params.Get("response-content-disposition"), "\n")
+}
+
+func TestPresignedPutContentType(t *testing.T) {
+	m, _ := newFakeMinio()
+
+	resp, err := m.Invoke(&bindings.InvokeRequest{Operation: PresignedPutOperation, Metadata: map[string]string{"key": "a.png", "expires": "1m", "contentType": "image/png"}})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"Content-Type": "image/png"}, presigned(t, resp.Data).Headers)
+
+	_, err = m.Invoke(&bindings.InvokeRequest{Operation: PresignedPutOperation, Metadata: map[string]string{"key": "a.png", "expires": "1m", "maxContentLength": "1024"}})
+	assert.EqualError(t, err, "Minio maxContentLength is only enforced by presignedPost")
+}
+
+func TestPresignedPost(t *testing.T) {
+	m, _ := newFakeMinio()
+
+	before := time.Now().Truncate(time.Second)
+	resp, err := m.Invoke(&bindings.InvokeRequest{Operation: PresignedPostOperation, Metadata: map[string]string{
+		"key": "a.png", "expires": "1m", "contentType": "image/png", "maxContentLength": "1048576",
+	}})
+	assert.Nil(t, err)
+	post := presigned(t, resp.Data)
+	assert.Equal(t, http.MethodPost, post.Method)
+	assert.Equal(t, "http://fake:9000/", post.URL)
+	assert.Equal(t, "fake", post.FormData["policy"])
+	assert.False(t, post.ExpiresAt.Before(before.Add(time.Minute)))
+	assert.False(t, post.ExpiresAt.After(time.Now().Add(time.Minute)))
+
+	m.maxPresignExpiry = time.Hour
+	for msg, p := range map[string]map[string]string{
+		"missing name field":                                    {"expires": "1m"},
+		"missing maxContentLength field":                        {"key": "a.png", "expires": "1m", "minContentLength": "10"},
+		"expires 2h is longer than the maxPresignExpiry 1h0m0s": {"key": "a.png", "expires": "2h"},
+		"minio binding error. presignedPost does not support encryption, use presignedPut": {"key": "a.png", "expires": "1m", EncryptionKey: EncryptionSSES3},
+	} {
+		_, err := m.Invoke(&bindings.InvokeRequest{Operation: PresignedPostOperation, Metadata: p})
+		assert.EqualError(t, err, msg)
+	}
+	_, err = m.Invoke(&bindings.InvokeRequest{Operation: PresignedPostOperation, Metadata: map[string]string{"key": "a.png", "expires": "1m", "minContentLength": "10", "maxContentLength": "5"}})
+	assert.EqualError(t, err, "Minio minContentLength 10 is larger than maxContentLength 5")
+}
@@ -0,0 +1,133 @@
+package minio
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"github.com/dapr/components-contrib/bindings"
+	"github.com/minio/minio-go/v7"
+	"github.com/pkg/errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+)
+
+const (
+	// PreviewOperation returns the first previewBytes of an object with its
+	// metadata, for content sniffing and previews of large objects.
+	PreviewOperation bindings.OperationKind = "preview"
+	// PreviewBytesKey is how many bytes preview returns, set on the component
+	// or per request.
+	PreviewBytesKey = "previewBytes"
+
+	defaultPreviewBytes = 4096
+)
+
+func (m *Minio) preview(ctx context.Context, req *bindings.InvokeRequest) (*bindings.InvokeResponse, error) {
+	p := req.Metadata
+
+	objectName := objectNameOf(p)
+	if objectName == "" {
+		return nil, errors.Errorf("missing name field")
+	}
+	limit, err := sizeProperty(p, PreviewBytesKey, m.previewBytes)
+	if err != nil {
+		return nil, err
+	}
+	if limit == 0 {
+		return nil, errors.Errorf("%s must be positive", PreviewBytesKey)
+	}
+	if m.maxGetSize > 0 && limit > m.maxGetSize {
+		limit = m.maxGetSize
+	}
+
+	client, err := m.clientFor(p)
+	if err != nil {
+		return nil, err
+	}
+	sse, err := readEncryption(m.properties, p)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		stat    minio.ObjectInfo
+		data    []byte
+		gzipped bool
+	)
+	err = m.withRetry(ctx, func() error {
+		stat, err = client.StatObject(ctx, m.Bucket, objectName, minio.StatObjectOptions{ServerSideEncryption: sse})
+		if err != nil {
+			return fmt.Errorf("stat object error: %w", err)
+		}
+		if isClientEncrypted(stat) {
+			return errors.Errorf("minio binding error. %s is client-side encrypted and can only be read whole", objectName)
+		}
+		if stat.Size == 0 {
+			data = nil
+			return nil
+		}
+		// a compressed object is decompressed until enough content is read,
+		// a plain one fetched with a range
+		opts := minio.GetObjectOptions{ServerSideEncryption: sse}
+		gzipped = m.requestFlag(p, DecompressKey) && isGzipped(stat)
+		if !gzipped {
+			if err := opts.SetRange(0, min64(limit, stat.Size)-1); err != nil {
+				return err
+			}
+		}
+		reader, err := client.GetObject(ctx, m.Bucket, objectName, opts)
+		if err != nil {
+			return fmt.Errorf("get object error: %w", err)
+		}
+		defer reader.Close()
+		var r io.Reader = reader
+		if gzipped {
+			gz, err := gzip.NewReader(reader)
+			if err != nil {
+				return fmt.Errorf("minio binding error. decompress: %w", err)
+			}
+			defer gz.Close()
+			r = gz
+		}
+		if !gzipped {
+			data, err = ioutil.ReadAll(io.LimitReader(r, limit))
+			return err
+		}
+		// the decompressed size isn't known up front, so one byte more tells
+		// whether there is content past the limit
+		data, err = ioutil.ReadAll(io.LimitReader(r, limit+1))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	truncated := int64(len(data)) < stat.Size
+	if gzipped {
+		if truncated = int64(len(data)) > limit; truncated {
+			data = data[:limit]
+		}
+	}
+	metadata := map[string]string{
+		"key":          objectName,
+		"size":         strconv.FormatInt(stat.Size, 10),
+		"versionID":    stat.VersionID,
+		"etag":         stat.ETag,
+		"contentType":  stat.ContentType,
+		"previewBytes": strconv.Itoa(len(data)),
+		"truncated":    strconv.FormatBool(truncated),
+	}
+	if len(data) > 0 {
+		metadata["detectedContentType"] = http.DetectContentType(data)
+	}
+	return &bindings.InvokeResponse{Data: data, Metadata: metadata}, nil
+}
+
+func min64(a, b int64) int64 {
+	if a < b {
+		return a
+	}
+	return b
+}
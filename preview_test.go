@@ -0,0 +1,84 @@
+package minio
+
+import (
+	"bytes"
+	"github.com/dapr/components-contrib/bindings"
+	"github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestPreview(t *testing.T) {
+	m, fake := newFakeMinio()
+	content := append([]byte("%PDF-1.4\n"), bytes.Repeat([]byte("x"), 10000)...)
+	fake.put("b", "report.pdf", content, minio.ObjectInfo{ContentType: "application/pdf"})
+
+	resp, err := m.Invoke(&bindings.InvokeRequest{Operation: PreviewOperation, Metadata: map[string]string{"key": "report.pdf"}})
+	assert.Nil(t, err)
+	assert.Equal(t, content[:4096], resp.Data)
+	assert.Equal(t, "10009", resp.Metadata["size"])
+	assert.Equal(t, "4096", resp.Metadata["previewBytes"])
+	assert.Equal(t, "true", resp.Metadata["truncated"])
+	assert.Equal(t, "application/pdf", resp.Metadata["contentType"])
+	assert.Equal(t, "application/pdf", resp.Metadata["detectedContentType"])
+
+	resp, err = m.Invoke(&bindings.InvokeRequest{Operation: PreviewOperation, Metadata: map[string]string{"key": "report.pdf", "previewBytes": "4"}})
+	assert.Nil(t, err)
+	assert.Equal(t, "%PDF", string(resp.Data))
+
+	fake.put("b", "small.txt", []byte("hello"), minio.ObjectInfo{})
+	resp, err = m.Invoke(&bindings.InvokeRequest{Operation: PreviewOperation, Metadata: map[string]string{"key": "small.txt"}})
+	assert.Nil(t, err)
+	assert.Equal(t, "hello", string(resp.Data))
+	assert.Equal(t, "false", resp.Metadata["truncated"])
+}
+
+func TestPreviewEmptyAndMissing(t *testing.T) {
+	m, fake := newFakeMinio()
+	fake.put("b", "empty", nil, minio.ObjectInfo{})
+
+	resp, err := m.Invoke(&bindings.InvokeRequest{Operation: PreviewOperation, Metadata: map[string]string{"key": "empty"}})
+	assert.Nil(t, err)
+	assert.Empty(t, resp.Data)
+	assert.Equal(t, "false", resp.Metadata["truncated"])
+
+	_, err = m.Invoke(&bindings.InvokeRequest{Operation: PreviewOperation, Metadata: map[string]string{"key": "missing"}})
+	assert.Equal(t, ErrCodeNotFound, ErrorCode(err))
+
+	_, err = m.Invoke(&bindings.InvokeRequest{Operation: PreviewOperation, Metadata: map[string]string{"key": "empty", "previewBytes": "0"}})
+	assert.Error(t, err)
+}
+
+func TestPreviewDecompress(t *testing.T) {
+	m, _ := newFakeMinio()
+	content := bytes.Repeat([]byte("0123456789"), 1000)
+
+	_, err := m.Invoke(&bindings.InvokeRequest{
+		Operation: bindings.CreateOperation,
+		Data:      content,
+		Metadata:  map[string]string{"key": "log.txt", CompressKey: CompressionGzip},
+	})
+	assert.Nil(t, err)
+
+	resp, err := m.Invoke(&bindings.InvokeRequest{Operation: PreviewOperation, Metadata: map[string]string{"key": "log.txt", "previewBytes": "10", DecompressKey: "true"}})
+	assert.Nil(t, err)
+	assert.Equal(t, "0123456789", string(resp.Data))
+	assert.Equal(t, "true", resp.Metadata["truncated"])
+
+	// content of exactly previewBytes is complete
+	resp, err = m.Invoke(&bindings.InvokeRequest{Operation: PreviewOperation, Metadata: map[string]string{"key": "log.txt", "previewBytes": "10000", DecompressKey: "true"}})
+	assert.Nil(t, err)
+	assert.Equal(t, content, resp.Data)
+	assert.Equal(t, "10000", resp.Metadata["previewBytes"])
+	assert.Equal(t, "false", resp.Metadata["truncated"])
+}
+
+func TestPreviewMaxGetSize(t *testing.T) {
+	m, fake := newFakeMinio()
+	m.maxGetSize = 3
+	fake.put("b", "a.txt", []byte("hello"), minio.ObjectInfo{})
+
+	resp, err := m.Invoke(&bindings.InvokeRequest{Operation: PreviewOperation, Metadata: map[string]string{"key": "a.txt"}})
+	assert.Nil(t, err)
+	assert.Equal(t, "hel", string(resp.Data))
+}
@@ -0,0 +1,48 @@
+package minio
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/dapr/components-contrib/pubsub"
+	"github.com/minio/minio-go/v7/pkg/notification"
+)
+
+const (
+	PublishPubsubNameKey = "publishPubsubName"
+	PublishTopicKey      = "publishTopic"
+)
+
+// Publisher is satisfied by the Dapr runtime's pub/sub publisher.
+type Publisher interface {
+	Publish(req *pubsub.PublishRequest) error
+}
+
+// SetPublisher enables republishing bucket events to publishTopic, so several
+// services can consume them without each holding a listener. Each event is
+// published once, before it is handed to the app. A failed publish is retried
+// like a delivery and then logged; it doesn't affect the delivery.
+func (m *Minio) SetPublisher(publisher Publisher) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.publisher = publisher
+}
+
+func (r *reader) publish(event notification.Event) error {
+	if r.publisher == nil || r.input.publishTopic == "" {
+		return nil
+	}
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("minio binding error. cannot marshal event to json: %w", err)
+	}
+	err = r.publisher.Publish(&pubsub.PublishRequest{
+		Data:       data,
+		PubsubName: r.input.publishPubsubName,
+		Topic:      r.input.publishTopic,
+		Metadata:   eventMetadata(event),
+	})
+	if err != nil {
+		return fmt.Errorf("minio binding error. publish to %s: %w", r.input.publishTopic, err)
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package minio
+
+import (
+	"encoding/json"
+	"github.com/dapr/components-contrib/bindings"
+	"github.com/dapr/components-contrib/pubsub"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/notification"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+type fakePublisher struct {
+	published []*pubsub.PublishRequest
+
+	// err, when set, fails every publish
+	err error
+}
+
+func (f *fakePublisher) Publish(req *pubsub.PublishRequest) error {
+	f.published = append(f.published, req)
+	return f.err
+}
+
+func TestPublish(t *testing.T) {
+	m, _ := newFakeMinio()
+	publisher := &fakePublisher{}
+	m.SetPublisher(publisher)
+	r, err := m.newReader()
+	require.NoError(t, err)
+
+	var event notification.Event
+	event.EventName = "s3:ObjectCreated:Put"
+	event.S3.Object.Key = "a.txt"
+
+	assert.NoError(t, r.publish(event))
+	assert.Empty(t, publisher.published)
+
+	r.input.publishPubsubName = "pubsub"
+	r.input.publishTopic = "objects"
+	assert.NoError(t, r.publish(event))
+	assert.Len(t, publisher.published, 1)
+	req := publisher.published[0]
+	assert.Equal(t, "pubsub", req.PubsubName)
+	assert.Equal(t, "objects", req.Topic)
+	assert.Equal(t, "a.txt", req.Metadata["key"])
+
+	var published notification.Event
+	assert.NoError(t, json.Unmarshal(req.Data, &published))
+	assert.Equal(t, "s3:ObjectCreated:Put", published.EventName)
+}
+
+func TestPublishOncePerEvent(t *testing.T) {
+	m, _ := newFakeMinio()
+	m.input = inputConfig{events: defaultNotificationEvents, deliveryRetries: 2, publishTopic: "objects"}
+	publisher := &fakePublisher{}
+	m.SetPublisher(publisher)
+	r, err := m.newReader()
+	require.NoError(t, err)
+
+	attempts := 0
+	r.dispatch(func(*bindings.ReadResponse) ([]byte, error) {
+		attempts++
+		return nil, errors.Errorf("handler failed")
+	}, newEvent(objectCreatedEvent, "b", minio.ObjectInfo{Key: "a.txt"}))
+	assert.Equal(t, 3, attempts)
+	assert.Len(t, publisher.published, 1)
+}
+
+func TestPublishFailureDoesNotFailDelivery(t *testing.T) {
+	m, fake := newFakeMinio()
+	m.input = inputConfig{events: defaultNotificationEvents, deliveryRetries: 2, publishTopic: "objects", deadLetterPrefix: "dlq/"}
+	publisher := &fakePublisher{err: errors.Errorf("pubsub unavailable")}
+	m.SetPublisher(publisher)
+	store := &memoryCheckpointStore{}
+	m.SetCheckpointStore(store)
+	r, err := m.newReader()
+	require.NoError(t, err)
+
+	handled := 0
+	r.dispatch(func(*bindings.ReadResponse) ([]byte, error) {
+		handled++
+		return nil, nil
+	}, newEvent(objectCreatedEvent, "b", minio.ObjectInfo{Key: "a.txt"}))
+	assert.Equal(t, 1, handled)
+	assert.Len(t, publisher.published, 3)
+	// the app handled the event, so it isn't dead-lettered and the
+	// checkpoint moves past it
+	assert.Empty(t, fake.buckets["b"])
+	assert.Len(t, store.saved, 1)
+}
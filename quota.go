@@ -0,0 +1,83 @@
+package minio
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/dapr/components-contrib/bindings"
+	"github.com/minio/madmin-go"
+	"github.com/pkg/errors"
+	"strconv"
+)
+
+const (
+	QuotaKey     = "quota"
+	QuotaTypeKey = "quotaType"
+
+	GetBucketQuotaOperation bindings.OperationKind = "getBucketQuota"
+	SetBucketQuotaOperation bindings.OperationKind = "setBucketQuota"
+
+	// fifoQuota is deprecated in MinIO and only accepted by older servers;
+	// current releases reject it, and madmin-go only names the hard type
+	fifoQuota madmin.QuotaType = "fifo"
+)
+
+type quotaResponse struct {
+	Bucket string `json:"bucket"`
+	Quota  uint64 `json:"quota"`
+	Type   string `json:"quotaType,omitempty"`
+}
+
+func quotaResult(bucket string, quota madmin.BucketQuota) (*bindings.InvokeResponse, error) {
+	jsonResponse, err := json.Marshal(quotaResponse{Bucket: bucket, Quota: quota.Quota, Type: string(quota.Type)})
+	if err != nil {
+		return nil, err
+	}
+	return &bindings.InvokeResponse{
+		Data: jsonResponse,
+		Metadata: map[string]string{
+			"bucket":    bucket,
+			"quota":     strconv.FormatUint(quota.Quota, 10),
+			"quotaType": string(quota.Type),
+		},
+	}, nil
+}
+
+func (m *Minio) getBucketQuota(ctx context.Context, admin adminClient, p map[string]string) (*bindings.InvokeResponse, error) {
+	bucket := m.targetBucket(p)
+	quota, err := admin.GetBucketQuota(ctx, bucket)
+	if err != nil {
+		return nil, fmt.Errorf("minio binding error. get bucket quota: %w", err)
+	}
+	return quotaResult(bucket, quota)
+}
+
+// setBucketQuota limits a bucket to quota bytes. A hard quota rejects writes
+// beyond it. A fifo quota deletes the oldest objects to make room, but only
+// older MinIO servers support it. A quota of 0 removes the limit.
+func (m *Minio) setBucketQuota(ctx context.Context, admin adminClient, p map[string]string) (*bindings.InvokeResponse, error) {
+	bucket := m.targetBucket(p)
+	if p[QuotaKey] == "" {
+		return nil, errors.Errorf("missing %s field", QuotaKey)
+	}
+	size, err := sizeProperty(p, QuotaKey, 0)
+	if err != nil {
+		return nil, err
+	}
+	quota := madmin.BucketQuota{Quota: uint64(size), Type: madmin.QuotaType(p[QuotaTypeKey])}
+	switch quota.Type {
+	case "":
+		quota.Type = madmin.HardQuota
+	case madmin.HardQuota, fifoQuota:
+	default:
+		return nil, errors.Errorf("unsupported Minio quotaType %s", quota.Type)
+	}
+	if size == 0 {
+		quota.Type = ""
+	}
+
+	if err := admin.SetBucketQuota(ctx, bucket, &quota); err != nil {
+		return nil, fmt.Errorf("minio binding error. set bucket quota: %w", err)
+	}
+	return quotaResult(bucket, quota)
+}
@@ -0,0 +1,67 @@
+package minio
+
+import (
+	"github.com/dapr/components-contrib/bindings"
+	"github.com/minio/madmin-go"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestBucketQuota(t *testing.T) {
+	m, admin := newFakeAdminMinio()
+
+	resp, err := m.Invoke(&bindings.InvokeRequest{
+		Operation: SetBucketQuotaOperation,
+		Metadata:  map[string]string{"quota": "1073741824"},
+	})
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"bucket":"b","quota":1073741824,"quotaType":"hard"}`, string(resp.Data))
+	assert.Equal(t, madmin.BucketQuota{Quota: 1 << 30, Type: madmin.HardQuota}, admin.quotas["b"])
+
+	resp, err = m.Invoke(&bindings.InvokeRequest{Operation: GetBucketQuotaOperation})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"bucket": "b", "quota": "1073741824", "quotaType": "hard"}, resp.Metadata)
+
+	// another tenant's bucket
+	_, err = m.Invoke(&bindings.InvokeRequest{
+		Operation: SetBucketQuotaOperation,
+		Metadata:  map[string]string{"bucket": "tenant-a", "quota": "1024", "quotaType": "fifo"},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, madmin.BucketQuota{Quota: 1024, Type: fifoQuota}, admin.quotas["tenant-a"])
+
+	// 0 removes the quota
+	resp, err = m.Invoke(&bindings.InvokeRequest{
+		Operation: SetBucketQuotaOperation,
+		Metadata:  map[string]string{"quota": "0"},
+	})
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"bucket":"b","quota":0}`, string(resp.Data))
+	assert.Equal(t, madmin.BucketQuota{}, admin.quotas["b"])
+}
+
+func TestBucketQuotaValidation(t *testing.T) {
+	m, admin := newFakeAdminMinio()
+
+	for _, metadata := range []map[string]string{
+		{},
+		{"quota": "-1"},
+		{"quota": "10GiB"},
+		{"quota": "1024", "quotaType": "soft"},
+	} {
+		_, err := m.Invoke(&bindings.InvokeRequest{Operation: SetBucketQuotaOperation, Metadata: metadata})
+		assert.Error(t, err, "%v", metadata)
+	}
+	assert.Empty(t, admin.quotas)
+}
+
+func TestBucketQuotaAudited(t *testing.T) {
+	m, _ := newFakeAdminMinio()
+	req := &bindings.InvokeRequest{
+		Operation: SetBucketQuotaOperation,
+		Metadata:  map[string]string{"bucket": "tenant-a", "quota": "1024"},
+	}
+	assert.True(t, auditedOperations[req.Operation])
+	assert.Equal(t, "tenant-a", m.newAuditRecord(req, nil, nil, time.Now()).Bucket)
+}
@@ -0,0 +1,59 @@
+package minio
+
+import (
+	"context"
+	"github.com/dapr/kit/logger"
+	"github.com/minio/minio-go/v7"
+	"time"
+)
+
+const (
+	// ReadEndpointKey lists read replicas, comma-separated, that serve get,
+	// stat and list while writes go to the endpoint.
+	ReadEndpointKey = "readEndpoint"
+	// ReadFromPrimaryKey sends the reads of one request to the primary, for
+	// callers that must see their own writes.
+	ReadFromPrimaryKey = "readFromPrimary"
+)
+
+// readRoutingClient sends reads to the replicas and everything else to the
+// primary.
+type readRoutingClient struct {
+	objectClient
+	reads objectClient
+}
+
+// newRoutedClient connects to the endpoints, and with read endpoints set,
+// routes reads to them. Reads fail over to the primary endpoints when no
+// replica can be reached.
+func newRoutedClient(endpoints, readEndpoints []string, options func() *minio.Options, cooldown time.Duration, log logger.Logger) (objectClient, []*minio.Client, error) {
+	client, rawClients, err := newObjectClient(endpoints, options, cooldown, log)
+	if err != nil || len(readEndpoints) == 0 {
+		return client, rawClients, err
+	}
+	reads, readClients, err := newObjectClient(append(append([]string{}, readEndpoints...), endpoints...), options, cooldown, log)
+	if err != nil {
+		return nil, nil, err
+	}
+	return readRoutingClient{objectClient: client, reads: reads}, append(rawClients, readClients...), nil
+}
+
+// primaryClient returns the client that writes go through.
+func primaryClient(c objectClient) objectClient {
+	if r, ok := c.(readRoutingClient); ok {
+		return r.objectClient
+	}
+	return c
+}
+
+func (r readRoutingClient) GetObject(ctx context.Context, bucketName, objectName string, opts minio.GetObjectOptions) (objectReader, error) {
+	return r.reads.GetObject(ctx, bucketName, objectName, opts)
+}
+
+func (r readRoutingClient) StatObject(ctx context.Context, bucketName, objectName string, opts minio.StatObjectOptions) (minio.ObjectInfo, error) {
+	return r.reads.StatObject(ctx, bucketName, objectName, opts)
+}
+
+func (r readRoutingClient) ListObjects(ctx context.Context, bucketName string, opts minio.ListObjectsOptions) <-chan minio.ObjectInfo {
+	return r.reads.ListObjects(ctx, bucketName, opts)
+}
@@ -0,0 +1,66 @@
+package minio
+
+import (
+	"github.com/dapr/components-contrib/bindings"
+	"github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// newReplicaMinio routes the reads of a fake binding to a lagging replica.
+func newReplicaMinio() (*Minio, *fakeClient, *fakeClient) {
+	m, primary := newFakeMinio()
+	replica := newFakeClient("b")
+	m.minioClient = readRoutingClient{objectClient: primary, reads: replica}
+	return m, primary, replica
+}
+
+func TestReadReplicaRouting(t *testing.T) {
+	m, primary, replica := newReplicaMinio()
+	replica.put("b", "a.txt", []byte("stale"), minio.ObjectInfo{})
+
+	_, err := m.Invoke(&bindings.InvokeRequest{
+		Operation: bindings.CreateOperation,
+		Data:      []byte("fresh"),
+		Metadata:  map[string]string{"key": "a.txt"},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "fresh", string(primary.buckets["b"]["a.txt"].data))
+	assert.Equal(t, "stale", string(replica.buckets["b"]["a.txt"].data))
+
+	resp, err := m.Invoke(&bindings.InvokeRequest{
+		Operation: bindings.GetOperation,
+		Metadata:  map[string]string{"key": "a.txt"},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "stale", string(resp.Data))
+
+	resp, err = m.Invoke(&bindings.InvokeRequest{
+		Operation: bindings.GetOperation,
+		Metadata:  map[string]string{"key": "a.txt", "readFromPrimary": "true"},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "fresh", string(resp.Data))
+}
+
+func TestReadReplicaPreconditionsUsePrimary(t *testing.T) {
+	m, primary, replica := newReplicaMinio()
+	primary.put("b", "a.txt", []byte("fresh"), minio.ObjectInfo{})
+	etag := primary.buckets["b"]["a.txt"].info.ETag
+
+	// the replica hasn't seen the object yet, the primary decides
+	_, err := m.Invoke(&bindings.InvokeRequest{
+		Operation: bindings.CreateOperation,
+		Data:      []byte("newer"),
+		Metadata:  map[string]string{"key": "a.txt", "ifMatch": etag},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "newer", string(primary.buckets["b"]["a.txt"].data))
+	assert.NotContains(t, replica.buckets["b"], "a.txt")
+}
+
+func TestPrimaryClient(t *testing.T) {
+	primary, replica := newFakeClient("b"), newFakeClient("b")
+	assert.Equal(t, objectClient(primary), primaryClient(readRoutingClient{objectClient: primary, reads: replica}))
+	assert.Equal(t, objectClient(primary), primaryClient(primary))
+}
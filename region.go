@@ -0,0 +1,135 @@
+package minio
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/dapr/components-contrib/bindings"
+	"github.com/minio/minio-go/v7"
+	"github.com/pkg/errors"
+	"strings"
+	"sync"
+)
+
+const (
+	MakeBucketOperation bindings.OperationKind = "makeBucket"
+
+	// SigningRegionKey is the region requests are signed for, for gateways
+	// expecting a fixed one whatever the region of the bucket. Creating a
+	// bucket outside us-east-1 is still signed for its region, as S3 requires.
+	SigningRegionKey = "signingRegion"
+	// AllowedRegionsKey lists the regions, comma separated, requests may ask
+	// for besides the component's own.
+	AllowedRegionsKey = "allowedRegions"
+)
+
+// requestRegion is the region a request is signed for, unless signingRegion
+// is set, and creates buckets in: the request's region, or the component's.
+func (m *Minio) requestRegion(p map[string]string) string {
+	if region := p[RegionKey]; region != "" {
+		return region
+	}
+	return m.Region
+}
+
+func parseAllowedRegions(p map[string]string) map[string]bool {
+	allowed := map[string]bool{}
+	for _, region := range strings.Split(p[AllowedRegionsKey], ",") {
+		if region = strings.TrimSpace(region); region != "" {
+			allowed[region] = true
+		}
+	}
+	return allowed
+}
+
+// checkRegion refuses request regions that aren't allowed, which would
+// otherwise each get a cached client of their own.
+func (m *Minio) checkRegion(region string) error {
+	if region == m.Region || m.allowedRegions[region] {
+		return nil
+	}
+	return errors.Errorf("minio binding error. region %s is not in %s", region, AllowedRegionsKey)
+}
+
+// signingRegionFor returns the region to sign requests for region with.
+func (m *Minio) signingRegionFor(region string) string {
+	if m.signingRegion != "" {
+		return m.signingRegion
+	}
+	return region
+}
+
+// regionalClients caches a client per region for requests overriding the
+// component region, so they share connections instead of dialing anew.
+type regionalClients struct {
+	mu      sync.Mutex
+	clients map[string]objectClient
+}
+
+func (r *regionalClients) get(region string, create func() (objectClient, error)) (objectClient, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if client, ok := r.clients[region]; ok {
+		return client, nil
+	}
+	client, err := create()
+	if err != nil {
+		return nil, err
+	}
+	if r.clients == nil {
+		r.clients = map[string]objectClient{}
+	}
+	r.clients[region] = client
+	return client, nil
+}
+
+// regionClient returns a client signing for region with the binding's own
+// credentials.
+func (m *Minio) regionClient(region string) (objectClient, error) {
+	return m.regional.get(region, func() (objectClient, error) {
+		client, _, err := m.newClient(m.credentials.clientCredentials(), region)
+		return client, err
+	})
+}
+
+type makeBucketResponse struct {
+	Bucket  string `json:"bucket"`
+	Region  string `json:"region,omitempty"`
+	Created bool   `json:"created"`
+}
+
+// makeBucket creates the binding's bucket, or the request's bucket, in the
+// request's region. Creating another bucket than the binding's requires
+// adminOperations. A bucket the caller already owns is not an error.
+func (m *Minio) makeBucket(ctx context.Context, req *bindings.InvokeRequest) (*bindings.InvokeResponse, error) {
+	p := req.Metadata
+	bucket := m.targetBucket(p)
+	region := m.requestRegion(p)
+	if bucket != m.Bucket {
+		if _, err := m.adminClient(); err != nil {
+			return nil, err
+		}
+	}
+
+	client, err := m.clientFor(p)
+	if err != nil {
+		return nil, err
+	}
+	created := true
+	err = client.MakeBucket(ctx, bucket, minio.MakeBucketOptions{Region: region})
+	if err != nil {
+		if minio.ToErrorResponse(err).Code != "BucketAlreadyOwnedByYou" {
+			return nil, fmt.Errorf("minio binding error. make bucket: %w", err)
+		}
+		created = false
+	}
+
+	jsonResponse, err := json.Marshal(makeBucketResponse{Bucket: bucket, Region: region, Created: created})
+	if err != nil {
+		return nil, err
+	}
+	return &bindings.InvokeResponse{
+		Data:     jsonResponse,
+		Metadata: map[string]string{"bucket": bucket, "region": region},
+	}, nil
+}
@@ -0,0 +1,110 @@
+package minio
+
+import (
+	"github.com/dapr/components-contrib/bindings"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+)
+
+func TestMakeBucket(t *testing.T) {
+	m, _ := newFakeMinio()
+	m.Region = "us-east-1"
+
+	// other buckets are only created with adminOperations
+	_, err := m.Invoke(&bindings.InvokeRequest{
+		Operation: MakeBucketOperation,
+		Metadata:  map[string]string{"bucket": "tenant-a"},
+	})
+	assert.EqualError(t, err, "minio binding error. admin operations require adminOperations")
+
+	m, _ = newFakeAdminMinio()
+	m.Region = "us-east-1"
+	fake := m.minioClient.(*fakeClient)
+
+	resp, err := m.Invoke(&bindings.InvokeRequest{
+		Operation: MakeBucketOperation,
+		Metadata:  map[string]string{"bucket": "tenant-a"},
+	})
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"bucket":"tenant-a","region":"us-east-1","created":true}`, string(resp.Data))
+	assert.Contains(t, fake.buckets, "tenant-a")
+
+	// the binding's own bucket already exists
+	resp, err = m.Invoke(&bindings.InvokeRequest{Operation: MakeBucketOperation})
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"bucket":"b","region":"us-east-1","created":false}`, string(resp.Data))
+}
+
+func TestRequestRegion(t *testing.T) {
+	m, _ := newFakeMinio()
+	m.Region = "us-east-1"
+	assert.Equal(t, "us-east-1", m.requestRegion(map[string]string{}))
+	assert.Equal(t, "eu-west-1", m.requestRegion(map[string]string{"region": "eu-west-1"}))
+
+	// the component region uses the binding's client
+	client, err := m.clientFor(map[string]string{"region": "us-east-1"})
+	assert.Nil(t, err)
+	assert.Equal(t, m.minioClient, client)
+
+	// other regions must be allowed
+	_, err = m.clientFor(map[string]string{"region": "eu-west-1"})
+	assert.EqualError(t, err, "minio binding error. region eu-west-1 is not in allowedRegions")
+	m.allowedRegions = parseAllowedRegions(map[string]string{AllowedRegionsKey: "eu-west-1, ap-south-1"})
+	_, err = m.clientFor(map[string]string{"region": "eu-west-1"})
+	assert.Nil(t, err)
+	_, err = m.clientFor(map[string]string{"region": "us-west-2"})
+	assert.Error(t, err)
+	assert.Len(t, m.regional.clients, 1)
+}
+
+func TestS3RegionOverride(t *testing.T) {
+	s := newS3Server(t)
+	m := newS3Minio(t, s, map[string]string{AdminOperationsKey: "true", AllowedRegionsKey: "eu-west-1"})
+
+	_, err := m.Invoke(&bindings.InvokeRequest{
+		Operation: MakeBucketOperation,
+		Metadata:  map[string]string{"bucket": "tenant-eu", "region": "eu-west-1"},
+	})
+	require.NoError(t, err)
+	put := s.last(http.MethodPut, "")
+	assert.Equal(t, "/tenant-eu/", put.Path)
+	assert.Contains(t, string(put.Body), "<LocationConstraint>eu-west-1</LocationConstraint>")
+	assert.Contains(t, put.Header.Get("Authorization"), "/eu-west-1/s3/aws4_request")
+
+	// object requests are signed for the request region, and the client is reused
+	for i := 0; i < 2; i++ {
+		_, err = m.Invoke(&bindings.InvokeRequest{
+			Operation: bindings.CreateOperation,
+			Data:      []byte("hello"),
+			Metadata:  map[string]string{"objectName": "a.txt", "region": "eu-west-1"},
+		})
+		require.NoError(t, err)
+		assert.Contains(t, s.last(http.MethodPut, "").Header.Get("Authorization"), "/eu-west-1/s3/aws4_request")
+	}
+	assert.Len(t, m.regional.clients, 1)
+
+	_, err = m.Invoke(&bindings.InvokeRequest{
+		Operation: bindings.CreateOperation,
+		Data:      []byte("hello"),
+		Metadata:  map[string]string{"objectName": "a.txt"},
+	})
+	require.NoError(t, err)
+	assert.Contains(t, s.last(http.MethodPut, "").Header.Get("Authorization"), "/us-east-1/s3/aws4_request")
+}
+
+func TestS3SigningRegion(t *testing.T) {
+	s := newS3Server(t)
+	m := newS3Minio(t, s, map[string]string{RegionKey: "eu-west-1", SigningRegionKey: "us-east-1", AllowedRegionsKey: "ap-south-1"})
+
+	_, err := m.Invoke(&bindings.InvokeRequest{
+		Operation: bindings.CreateOperation,
+		Data:      []byte("hello"),
+		Metadata:  map[string]string{"objectName": "a.txt", "region": "ap-south-1"},
+	})
+	require.NoError(t, err)
+	assert.Contains(t, s.last(http.MethodPut, "").Header.Get("Authorization"), "/us-east-1/s3/aws4_request")
+	assert.Empty(t, m.regional.clients)
+	assert.Equal(t, "eu-west-1", m.Region)
+}
@@ -0,0 +1,90 @@
+package minio
+
+import (
+	"context"
+	"github.com/minio/minio-go/v7"
+	"github.com/pkg/errors"
+	"io"
+	"net"
+	"net/http"
+	"time"
+)
+
+const (
+	MaxRetriesKey      = "maxRetries"
+	RetryBackoffKey    = "retryBackoff"
+	RetryMaxBackoffKey = "retryMaxBackoff"
+
+	defaultRetryBackoff    = 100 * time.Millisecond
+	defaultRetryMaxBackoff = 5 * time.Second
+)
+
+type retryPolicy struct {
+	maxRetries int64
+	backoff    time.Duration
+	maxBackoff time.Duration
+}
+
+func parseRetryPolicy(p map[string]string) (retryPolicy, error) {
+	var (
+		policy retryPolicy
+		err    error
+	)
+	if policy.maxRetries, err = sizeProperty(p, MaxRetriesKey, 0); err != nil {
+		return policy, err
+	}
+	if policy.backoff, err = durationProperty(p, RetryBackoffKey, defaultRetryBackoff); err != nil {
+		return policy, err
+	}
+	if policy.maxBackoff, err = durationProperty(p, RetryMaxBackoffKey, defaultRetryMaxBackoff); err != nil {
+		return policy, err
+	}
+	if policy.maxBackoff < policy.backoff {
+		policy.maxBackoff = policy.backoff
+	}
+	return policy, nil
+}
+
+// withRetry runs an idempotent operation again on transient failures, with
+// exponential backoff, until maxRetries is exhausted or ctx is done.
+func (m *Minio) withRetry(ctx context.Context, fn func() error) error {
+	backoff := m.retry.backoff
+	for attempt := int64(0); ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= m.retry.maxRetries || !isTransient(err) {
+			return err
+		}
+		m.logger.Debugf("Minio binding retrying after transient error (attempt %d): %s", attempt+1, err)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(withJitter(backoff)):
+		}
+		if backoff *= 2; backoff > m.retry.maxBackoff {
+			backoff = m.retry.maxBackoff
+		}
+	}
+}
+
+// isTransient reports network failures, throttling and 5xx responses.
+func isTransient(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	var resp minio.ErrorResponse
+	if !errors.As(err, &resp) {
+		return false
+	}
+	switch resp.Code {
+	case "InternalError", "SlowDown", "ServiceUnavailable", "RequestTimeout", "XMinioServerNotInitialized":
+		return true
+	}
+	return resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests
+}
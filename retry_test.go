@@ -0,0 +1,53 @@
+package minio
+
+import (
+	"context"
+	"fmt"
+	"github.com/dapr/kit/logger"
+	"github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestIsTransient(t *testing.T) {
+	assert.True(t, isTransient(io.ErrUnexpectedEOF))
+	assert.True(t, isTransient(&net.OpError{Op: "dial", Err: fmt.Errorf("connection refused")}))
+	assert.True(t, isTransient(fmt.Errorf("wrapped: %w", minio.ErrorResponse{Code: "SlowDown", StatusCode: 503})))
+	assert.True(t, isTransient(minio.ErrorResponse{StatusCode: 502}))
+	assert.False(t, isTransient(minio.ErrorResponse{Code: "NoSuchKey", StatusCode: 404}))
+	assert.False(t, isTransient(context.Canceled))
+	assert.False(t, isTransient(fmt.Errorf("missing name field")))
+}
+
+func TestWithRetry(t *testing.T) {
+	m := NewMinio(logger.NewLogger("test"))
+	m.retry = retryPolicy{maxRetries: 2, backoff: time.Millisecond, maxBackoff: time.Millisecond}
+
+	var calls int
+	err := m.withRetry(context.Background(), func() error {
+		calls++
+		return io.ErrUnexpectedEOF
+	})
+	assert.Equal(t, io.ErrUnexpectedEOF, err)
+	assert.Equal(t, 3, calls)
+
+	calls = 0
+	err = m.withRetry(context.Background(), func() error {
+		calls++
+		return minio.ErrorResponse{Code: "NoSuchKey", StatusCode: 404}
+	})
+	assert.Error(t, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestParseRetryPolicy(t *testing.T) {
+	policy, err := parseRetryPolicy(map[string]string{MaxRetriesKey: "3", RetryBackoffKey: "1s", RetryMaxBackoffKey: "10s"})
+	assert.NoError(t, err)
+	assert.Equal(t, retryPolicy{maxRetries: 3, backoff: time.Second, maxBackoff: 10 * time.Second}, policy)
+
+	_, err = parseRetryPolicy(map[string]string{MaxRetriesKey: "many"})
+	assert.EqualError(t, err, "maxRetries many is invalid")
+}
@@ -0,0 +1,683 @@
+package minio
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"github.com/dapr/components-contrib/bindings"
+	"github.com/dapr/kit/logger"
+	"github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// s3Server is a minimal in-process S3 endpoint for checking how the binding
+// shapes its requests. It serves path-style bucket and object requests from
+// memory and records every request it receives.
+type s3Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	requests []s3Request
+	objects  map[string]s3Object
+	uploads  map[string]*s3Upload
+	// beforePut, when set, runs before a PUT is applied
+	beforePut func(key string)
+}
+
+type s3Request struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Header http.Header
+	Body   []byte
+}
+
+type s3Object struct {
+	data   []byte
+	header http.Header
+}
+
+type s3Upload struct {
+	key    string
+	header http.Header
+	parts  map[int][]byte
+}
+
+func newS3Server(t *testing.T) *s3Server {
+	s := &s3Server{objects: map[string]s3Object{}, uploads: map[string]*s3Upload{}}
+	s.Server = httptest.NewTLSServer(http.HandlerFunc(s.serve))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// newS3Minio initializes a binding for bucket "bucket" on the server.
+func newS3Minio(t *testing.T, s *s3Server, properties map[string]string) *Minio {
+	m := NewMinio(logger.NewLogger("minio"))
+	require.NoError(t, m.Init(bindings.Metadata{Properties: s.properties(properties)}))
+	t.Cleanup(func() { m.Close() })
+	s.reset()
+	return m
+}
+
+// properties returns the component metadata for bucket "bucket" on the
+// server, with properties added.
+func (s *s3Server) properties(properties map[string]string) map[string]string {
+	p := map[string]string{
+		Endpoint:         strings.TrimPrefix(s.URL, "https://"),
+		AccessKey:        "access",
+		SecretAccessKey:  "secret",
+		SSLKey:           "true",
+		SkipTLSVerifyKey: "true",
+		BucketKey:        "bucket",
+		RegionKey:        "us-east-1",
+	}
+	for k, v := range properties {
+		p[k] = v
+	}
+	return p
+}
+
+func (s *s3Server) reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = nil
+}
+
+// last returns the most recent request with the given method and, when
+// set, query parameter.
+func (s *s3Server) last(method, param string) s3Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := len(s.requests) - 1; i >= 0; i-- {
+		if r := s.requests[i]; r.Method == method && (param == "" || r.Query.Has(param)) {
+			return r
+		}
+	}
+	return s3Request{}
+}
+
+func (s *s3Server) serve(w http.ResponseWriter, r *http.Request) {
+	body, err := readS3Body(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, s3Request{r.Method, r.URL.Path, r.URL.Query(), r.Header.Clone(), body})
+
+	parts := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/"), "/", 2)
+	if len(parts) == 1 || parts[1] == "" {
+		s.serveBucket(w, r, body)
+		return
+	}
+	key := parts[0] + "/" + parts[1]
+	query := r.URL.Query()
+	switch {
+	case query.Has("uploadId"):
+		s.serveMultipart(w, r, key, body)
+	case r.Method == http.MethodPost && query.Has("uploads"):
+		id := strconv.Itoa(len(s.uploads) + 1)
+		s.uploads[id] = &s3Upload{key: key, header: r.Header.Clone(), parts: map[int][]byte{}}
+		fmt.Fprintf(w, `<InitiateMultipartUploadResult><Bucket>%s</Bucket><Key>%s</Key><UploadId>%s</UploadId></InitiateMultipartUploadResult>`, parts[0], parts[1], id)
+	case r.Method == http.MethodPut && r.Header.Get("X-Amz-Copy-Source") != "":
+		s.serveCopy(w, r, key)
+	case r.Method == http.MethodPut:
+		if s.beforePut != nil {
+			s.beforePut(key)
+		}
+		if match := r.Header.Get("If-Match"); match != "" && s.objects[key].header.Get("ETag") != match {
+			s3Error(w, http.StatusPreconditionFailed, "PreconditionFailed", "At least one of the pre-conditions you specified did not hold")
+			return
+		}
+		w.Header().Set("ETag", s.store(key, body, r.Header))
+	case r.Method == http.MethodGet || r.Method == http.MethodHead:
+		object, ok := s.objects[key]
+		if !ok {
+			s3Error(w, http.StatusNotFound, "NoSuchKey", "The specified key does not exist.")
+			return
+		}
+		for k, v := range object.header {
+			w.Header()[k] = v
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(len(object.data)))
+		if r.Method == http.MethodGet {
+			w.Write(object.data)
+		}
+	case r.Method == http.MethodDelete:
+		delete(s.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		s3Error(w, http.StatusNotImplemented, "NotImplemented", r.Method+" is not implemented")
+	}
+}
+
+// store saves an object with the content and user metadata headers of the
+// request that created it and returns its ETag.
+func (s *s3Server) store(key string, data []byte, requestHeader http.Header) string {
+	sum := md5.Sum(data)
+	header := http.Header{}
+	header.Set("ETag", `"`+hex.EncodeToString(sum[:])+`"`)
+	header.Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
+	header.Set("Content-Type", requestHeader.Get("Content-Type"))
+	for k, v := range requestHeader {
+		if strings.HasPrefix(k, "X-Amz-Meta-") || k == "Content-Encoding" {
+			header[k] = v
+		}
+	}
+	s.objects[key] = s3Object{data: data, header: header}
+	return header.Get("ETag")
+}
+
+// serveCopy copies the object named by X-Amz-Copy-Source, with its own
+// metadata unless the request replaces it.
+func (s *s3Server) serveCopy(w http.ResponseWriter, r *http.Request, key string) {
+	source, _ := url.PathUnescape(r.Header.Get("X-Amz-Copy-Source"))
+	source = strings.TrimPrefix(strings.SplitN(source, "?", 2)[0], "/")
+	object, ok := s.objects[source]
+	if !ok {
+		s3Error(w, http.StatusNotFound, "NoSuchKey", "The specified key does not exist.")
+		return
+	}
+	header := object.header
+	if r.Header.Get("X-Amz-Metadata-Directive") == "REPLACE" {
+		header = r.Header
+	}
+	etag := s.store(key, object.data, header)
+	fmt.Fprintf(w, `<CopyObjectResult><ETag>%s</ETag><LastModified>%s</LastModified></CopyObjectResult>`, etag, time.Now().UTC().Format(time.RFC3339))
+}
+
+func (s *s3Server) serveMultipart(w http.ResponseWriter, r *http.Request, key string, body []byte) {
+	query := r.URL.Query()
+	upload, ok := s.uploads[query.Get("uploadId")]
+	if !ok || upload.key != key {
+		s3Error(w, http.StatusNotFound, "NoSuchUpload", "The specified upload does not exist.")
+		return
+	}
+	switch r.Method {
+	case http.MethodPut:
+		n, err := strconv.Atoi(query.Get("partNumber"))
+		if err != nil {
+			s3Error(w, http.StatusBadRequest, "InvalidArgument", "invalid partNumber")
+			return
+		}
+		upload.parts[n] = body
+		sum := md5.Sum(body)
+		w.Header().Set("ETag", `"`+hex.EncodeToString(sum[:])+`"`)
+	case http.MethodPost:
+		var data []byte
+		for n := 1; n <= len(upload.parts); n++ {
+			data = append(data, upload.parts[n]...)
+		}
+		delete(s.uploads, query.Get("uploadId"))
+		etag := s.store(key, data, upload.header)
+		bucket := strings.SplitN(key, "/", 2)[0]
+		fmt.Fprintf(w, `<CompleteMultipartUploadResult><Bucket>%s</Bucket><Key>%s</Key><ETag>%s</ETag></CompleteMultipartUploadResult>`,
+			bucket, strings.TrimPrefix(key, bucket+"/"), etag)
+	case http.MethodDelete:
+		delete(s.uploads, query.Get("uploadId"))
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (s *s3Server) serveBucket(w http.ResponseWriter, r *http.Request, body []byte) {
+	query := r.URL.Query()
+	switch {
+	case r.Method == http.MethodPost && query.Has("delete"):
+		s.serveDelete(w, r, body)
+	case r.Method == http.MethodPost && strings.Contains(string(body), "Action=AssumeRole"):
+		fmt.Fprint(w, `<AssumeRoleResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/"><AssumeRoleResult><Credentials>`+
+			`<AccessKeyId>TEMPKEY</AccessKeyId><SecretAccessKey>temp-secret</SecretAccessKey><SessionToken>temp-token</SessionToken>`+
+			`<Expiration>`+time.Now().Add(time.Hour).UTC().Format(time.RFC3339)+`</Expiration></Credentials></AssumeRoleResult></AssumeRoleResponse>`)
+	case r.Method == http.MethodHead:
+	case r.Method == http.MethodPut && len(query) == 0:
+	case r.Method == http.MethodGet && query.Has("location"):
+		fmt.Fprint(w, `<LocationConstraint xmlns="http://s3.amazonaws.com/doc/2006-03-01/">us-east-1</LocationConstraint>`)
+	case r.Method == http.MethodGet && query.Has("versioning"):
+		fmt.Fprint(w, `<VersioningConfiguration xmlns="http://s3.amazonaws.com/doc/2006-03-01/"></VersioningConfiguration>`)
+	default:
+		s3Error(w, http.StatusNotImplemented, "NotImplemented", r.Method+" "+r.URL.RawQuery+" is not implemented")
+	}
+}
+
+// serveDelete removes the objects of a multi-object delete. Deleting the
+// latest version reports a delete marker, as a versioned bucket would.
+func (s *s3Server) serveDelete(w http.ResponseWriter, r *http.Request, body []byte) {
+	var request struct {
+		Objects []struct {
+			Key       string
+			VersionID string `xml:"VersionId"`
+		} `xml:"Object"`
+	}
+	if err := xml.Unmarshal(body, &request); err != nil {
+		s3Error(w, http.StatusBadRequest, "MalformedXML", err.Error())
+		return
+	}
+	bucket := strings.Trim(r.URL.Path, "/")
+	fmt.Fprint(w, `<DeleteResult>`)
+	for _, object := range request.Objects {
+		delete(s.objects, bucket+"/"+object.Key)
+		if object.VersionID != "" {
+			fmt.Fprintf(w, `<Deleted><Key>%s</Key><VersionId>%s</VersionId></Deleted>`, object.Key, object.VersionID)
+		} else {
+			fmt.Fprintf(w, `<Deleted><Key>%s</Key><DeleteMarker>true</DeleteMarker><DeleteMarkerVersionId>marker</DeleteMarkerVersionId></Deleted>`, object.Key)
+		}
+	}
+	fmt.Fprint(w, `</DeleteResult>`)
+}
+
+func s3Error(w http.ResponseWriter, status int, code, message string) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	fmt.Fprintf(w, `<Error><Code>%s</Code><Message>%s</Message></Error>`, code, message)
+}
+
+// readS3Body returns the request payload, decoding the aws-chunked framing
+// minio-go uses for streaming signatures.
+func readS3Body(r *http.Request) ([]byte, error) {
+	if !strings.HasPrefix(r.Header.Get("X-Amz-Content-Sha256"), "STREAMING-") {
+		return ioutil.ReadAll(r.Body)
+	}
+	var body bytes.Buffer
+	br := bufio.NewReader(r.Body)
+	for {
+		line, err := br.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size, err := strconv.ParseInt(strings.SplitN(strings.TrimSpace(line), ";", 2)[0], 16, 64)
+		if err != nil {
+			return nil, err
+		}
+		if size == 0 {
+			return body.Bytes(), nil
+		}
+		if _, err := io.CopyN(&body, br, size); err != nil {
+			return nil, err
+		}
+		if _, err := br.Discard(2); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func TestS3CreateRequest(t *testing.T) {
+	s := newS3Server(t)
+	m := newS3Minio(t, s, map[string]string{DisableContentSha256Key: "true"})
+
+	_, err := m.Invoke(&bindings.InvokeRequest{
+		Operation: bindings.CreateOperation,
+		Data:      []byte("hello"),
+		Metadata:  map[string]string{"objectName": "dir/a b.txt"},
+	})
+	require.NoError(t, err)
+	put := s.last(http.MethodPut, "")
+	assert.Equal(t, "/bucket/dir/a b.txt", put.Path)
+	assert.Equal(t, "hello", string(put.Body))
+	assert.Equal(t, "UNSIGNED-PAYLOAD", put.Header.Get("X-Amz-Content-Sha256"))
+	assert.True(t, strings.HasPrefix(put.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=access/"))
+
+	// a compressed upload has no known size and goes through multipart
+	_, err = m.Invoke(&bindings.InvokeRequest{
+		Operation: bindings.CreateOperation,
+		Data:      []byte("hello hello hello"),
+		Metadata:  map[string]string{"objectName": "dir/a b.txt", CompressKey: CompressionGzip},
+	})
+	require.NoError(t, err)
+	initiate := s.last(http.MethodPost, "uploads")
+	assert.Equal(t, CompressionGzip, initiate.Header.Get("X-Amz-Meta-Compression"))
+	assert.Equal(t, "17", initiate.Header.Get("X-Amz-Meta-Original-Size"))
+
+	resp, err := m.Invoke(&bindings.InvokeRequest{
+		Operation: bindings.GetOperation,
+		Metadata:  map[string]string{"objectName": "dir/a b.txt", DecompressKey: "true"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "hello hello hello", string(resp.Data))
+}
+
+func TestS3EncryptionHeaders(t *testing.T) {
+	s := newS3Server(t)
+	key := "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY="
+
+	m := newS3Minio(t, s, map[string]string{EncryptionKey: "sse-c", SSECustomerKeyKey: key})
+	_, err := m.Invoke(&bindings.InvokeRequest{Operation: bindings.CreateOperation, Data: []byte("x"), Metadata: map[string]string{"objectName": "c"}})
+	require.NoError(t, err)
+	put := s.last(http.MethodPut, "")
+	assert.Equal(t, "AES256", put.Header.Get("X-Amz-Server-Side-Encryption-Customer-Algorithm"))
+	assert.Equal(t, key, put.Header.Get("X-Amz-Server-Side-Encryption-Customer-Key"))
+
+	_, err = m.Invoke(&bindings.InvokeRequest{Operation: bindings.GetOperation, Metadata: map[string]string{"objectName": "c"}})
+	require.NoError(t, err)
+	assert.Equal(t, key, s.last(http.MethodGet, "").Header.Get("X-Amz-Server-Side-Encryption-Customer-Key"))
+
+	m = newS3Minio(t, s, map[string]string{EncryptionKey: "sse-kms", SSEKMSKeyIDKey: "my-key"})
+	_, err = m.Invoke(&bindings.InvokeRequest{Operation: bindings.CreateOperation, Data: []byte("x"), Metadata: map[string]string{"objectName": "k"}})
+	require.NoError(t, err)
+	put = s.last(http.MethodPut, "")
+	assert.Equal(t, "aws:kms", put.Header.Get("X-Amz-Server-Side-Encryption"))
+	assert.Equal(t, "my-key", put.Header.Get("X-Amz-Server-Side-Encryption-Aws-Kms-Key-Id"))
+}
+
+func TestS3DeleteRequest(t *testing.T) {
+	s := newS3Server(t)
+	m := newS3Minio(t, s, nil)
+
+	_, err := m.Invoke(&bindings.InvokeRequest{Operation: bindings.DeleteOperation, Metadata: map[string]string{"objectName": "a", "versionID": "v1"}})
+	require.NoError(t, err)
+	del := s.last(http.MethodPost, "delete")
+	assert.Equal(t, "/bucket/", del.Path)
+	assert.Contains(t, string(del.Body), "<Key>a</Key><VersionId>v1</VersionId>")
+	assert.Empty(t, del.Header.Get("X-Amz-Bypass-Governance-Retention"))
+
+	// retention is only bypassed on request, the delete marker comes from
+	// the delete response and existence from a stat before it
+	_, err = m.Invoke(&bindings.InvokeRequest{Operation: bindings.CreateOperation, Data: []byte("a"), Metadata: map[string]string{"objectName": "a"}})
+	require.NoError(t, err)
+	resp, err := m.Invoke(&bindings.InvokeRequest{Operation: bindings.DeleteOperation, Metadata: map[string]string{"objectName": "a", BypassGovernanceKey: "true"}})
+	require.NoError(t, err)
+	assert.Equal(t, "true", s.last(http.MethodPost, "delete").Header.Get("X-Amz-Bypass-Governance-Retention"))
+	assert.JSONEq(t, `{"key":"a","deleteMarker":true,"deleteMarkerVersionID":"marker","existed":true}`, string(resp.Data))
+	assert.Empty(t, s.last(http.MethodGet, "versioning"))
+	assert.Equal(t, "/bucket/a", s.last(http.MethodHead, "").Path)
+
+	resp, err = m.Invoke(&bindings.InvokeRequest{Operation: bindings.DeleteOperation, Metadata: map[string]string{"objectName": "a"}})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"key":"a","deleteMarker":true,"deleteMarkerVersionID":"marker","existed":false}`, string(resp.Data))
+}
+
+func TestS3ConditionalPut(t *testing.T) {
+	s := newS3Server(t)
+	m := newS3Minio(t, s, nil)
+
+	resp, err := m.Invoke(&bindings.InvokeRequest{Operation: bindings.CreateOperation, Data: []byte("v1"), Metadata: map[string]string{"objectName": "k"}})
+	require.NoError(t, err)
+	assert.Empty(t, s.last(http.MethodPut, "").Header.Get("If-Match"))
+	etag := resp.Metadata["etag"]
+
+	resp, err = m.Invoke(&bindings.InvokeRequest{Operation: bindings.CreateOperation, Data: []byte("v2"), Metadata: map[string]string{"objectName": "k", IfMatchKey: etag}})
+	require.NoError(t, err)
+	assert.Equal(t, `"`+etag+`"`, s.last(http.MethodPut, "").Header.Get("If-Match"))
+	etag = resp.Metadata["etag"]
+
+	// a write landing between the check and the upload fails the upload
+	s.mu.Lock()
+	s.beforePut = func(key string) { s.store(key, []byte("other"), http.Header{}) }
+	s.mu.Unlock()
+	_, err = m.Invoke(&bindings.InvokeRequest{Operation: bindings.CreateOperation, Data: []byte("v3"), Metadata: map[string]string{"objectName": "k", IfMatchKey: etag}})
+	assert.Equal(t, ErrCodePreconditionFailed, ErrorCode(err))
+	assert.Equal(t, "v3", string(s.last(http.MethodPut, "").Body))
+}
+
+func TestS3TemporaryServiceAccount(t *testing.T) {
+	s := newS3Server(t)
+	m := newS3Minio(t, s, map[string]string{AdminOperationsKey: "true"})
+
+	resp, err := m.Invoke(&bindings.InvokeRequest{
+		Operation: CreateServiceAccountOperation,
+		Data:      []byte(`{"Version":"2012-10-17","Statement":[]}`),
+		Metadata:  map[string]string{DurationKey: "2h"},
+	})
+	require.NoError(t, err)
+	var account serviceAccountResponse
+	require.NoError(t, json.Unmarshal(resp.Data, &account))
+	assert.Equal(t, "TEMPKEY", account.AccessKey)
+	assert.Equal(t, "temp-secret", account.SecretKey)
+	assert.Equal(t, "temp-token", account.SessionToken)
+	assert.True(t, account.Expiration.After(time.Now().Add(119*time.Minute)), account.Expiration)
+
+	form, err := url.ParseQuery(string(s.last(http.MethodPost, "").Body))
+	require.NoError(t, err)
+	assert.Equal(t, "7200", form.Get("DurationSeconds"))
+	assert.Equal(t, `{"Version":"2012-10-17","Statement":[]}`, form.Get("Policy"))
+}
+
+func TestS3PresignParameters(t *testing.T) {
+	s := newS3Server(t)
+	m := newS3Minio(t, s, nil)
+
+	resp, err := m.Invoke(&bindings.InvokeRequest{Operation: PresignedGetOperation, Metadata: map[string]string{"objectName": "a.txt", "expires": "90s"}})
+	require.NoError(t, err)
+	u, err := url.Parse(presigned(t, resp.Data).URL)
+	require.NoError(t, err)
+	assert.Equal(t, "/bucket/a.txt", u.Path)
+	assert.Equal(t, "90", u.Query().Get("X-Amz-Expires"))
+	assert.Equal(t, "AWS4-HMAC-SHA256", u.Query().Get("X-Amz-Algorithm"))
+	assert.True(t, strings.HasPrefix(u.Query().Get("X-Amz-Credential"), "access/"))
+	assert.Equal(t, "host", u.Query().Get("X-Amz-SignedHeaders"))
+
+	key := "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY="
+	resp, err = m.Invoke(&bindings.InvokeRequest{Operation: PresignedPutOperation, Metadata: map[string]string{
+		"objectName": "b.txt", "expires": "1m", EncryptionKey: "sse-c", SSECustomerKeyKey: key,
+	}})
+	require.NoError(t, err)
+	u, err = url.Parse(presigned(t, resp.Data).URL)
+	require.NoError(t, err)
+	assert.Contains(t, u.Query().Get("X-Amz-SignedHeaders"), "x-amz-server-side-encryption-customer-key")
+	assert.Equal(t, key, resp.Metadata["X-Amz-Server-Side-Encryption-Customer-Key"])
+}
+
+func TestS3Failover(t *testing.T) {
+	retries := minio.MaxRetry
+	minio.MaxRetry = 1
+	defer func() { minio.MaxRetry = retries }()
+
+	// nothing listens on the primary
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	primary := listener.Addr().String()
+	listener.Close()
+
+	s := newS3Server(t)
+	m := newS3Minio(t, s, map[string]string{Endpoint: primary + "," + strings.TrimPrefix(s.URL, "https://")})
+
+	_, err = m.Invoke(&bindings.InvokeRequest{
+		Operation: bindings.CreateOperation,
+		Data:      []byte("hello"),
+		Metadata:  map[string]string{"objectName": "a.txt"},
+	})
+	require.NoError(t, err)
+	resp, err := m.Invoke(&bindings.InvokeRequest{Operation: bindings.GetOperation, Metadata: map[string]string{"objectName": "a.txt"}})
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(resp.Data))
+	assert.Equal(t, primary, m.endpoint)
+}
+
+func TestS3URLEndpoint(t *testing.T) {
+	s := newS3Server(t)
+	// the scheme of the URL wins over ssl
+	m := newS3Minio(t, s, map[string]string{Endpoint: s.URL, SSLKey: "false"})
+
+	_, err := m.Invoke(&bindings.InvokeRequest{
+		Operation: bindings.CreateOperation,
+		Data:      []byte("hello"),
+		Metadata:  map[string]string{"objectName": "a.txt"},
+	})
+	require.NoError(t, err)
+	assert.True(t, m.secure)
+	assert.Equal(t, strings.TrimPrefix(s.URL, "https://"), m.endpoint)
+}
+
+func TestS3PresignEndpoint(t *testing.T) {
+	s := newS3Server(t)
+	m := newS3Minio(t, s, map[string]string{PresignEndpointKey: "https://cdn.example.com"})
+
+	resp, err := m.Invoke(&bindings.InvokeRequest{Operation: PresignedGetOperation, Metadata: map[string]string{"objectName": "a.txt", "expires": "1m"}})
+	require.NoError(t, err)
+	u, err := url.Parse(presigned(t, resp.Data).URL)
+	require.NoError(t, err)
+	assert.Equal(t, "https", u.Scheme)
+	assert.Equal(t, "cdn.example.com", u.Host)
+	assert.Equal(t, "/bucket/a.txt", u.Path)
+
+	resp, err = m.Invoke(&bindings.InvokeRequest{Operation: PresignedPutOperation, Metadata: map[string]string{"objectName": "a.txt", "expires": "1m"}})
+	require.NoError(t, err)
+	u, err = url.Parse(presigned(t, resp.Data).URL)
+	require.NoError(t, err)
+	assert.Equal(t, "cdn.example.com", u.Host)
+	// signing doesn't contact the presign endpoint
+	assert.Empty(t, s.requests)
+}
+
+func TestS3PresignCustomParameters(t *testing.T) {
+	s := newS3Server(t)
+	m := newS3Minio(t, s, nil)
+
+	resp, err := m.Invoke(&bindings.InvokeRequest{Operation: PresignedGetOperation, Metadata: map[string]string{
+		"objectName": "a.txt", "expires": "1m", "presignParam-versionId": "v1",
+	}})
+	require.NoError(t, err)
+	u, err := url.Parse(presigned(t, resp.Data).URL)
+	require.NoError(t, err)
+	assert.Equal(t, "v1", u.Query().Get("versionId"))
+	assert.NotEmpty(t, u.Query().Get("X-Amz-Signature"))
+
+	resp, err = m.Invoke(&bindings.InvokeRequest{Operation: PresignedPutOperation, Metadata: map[string]string{
+		"objectName": "b.txt", "expires": "1m", "presignParam-partNumber": "2", "presignParam-uploadId": "u1",
+	}})
+	require.NoError(t, err)
+	u, err = url.Parse(presigned(t, resp.Data).URL)
+	require.NoError(t, err)
+	assert.Equal(t, "2", u.Query().Get("partNumber"))
+	assert.Equal(t, "u1", u.Query().Get("uploadId"))
+	assert.Equal(t, "/bucket/b.txt", u.Path)
+}
+
+func TestS3PortProperty(t *testing.T) {
+	s := newS3Server(t)
+	host, port, err := net.SplitHostPort(strings.TrimPrefix(s.URL, "https://"))
+	require.NoError(t, err)
+	m := newS3Minio(t, s, map[string]string{Endpoint: host, PortKey: port, PathStyleKey: "true"})
+
+	_, err = m.Invoke(&bindings.InvokeRequest{
+		Operation: bindings.CreateOperation,
+		Data:      []byte("hello"),
+		Metadata:  map[string]string{"objectName": "a.txt"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "/bucket/a.txt", s.last(http.MethodPut, "").Path)
+	assert.Equal(t, host+":"+port, m.endpoint)
+}
+
+func TestS3PresignDownloadFileName(t *testing.T) {
+	s := newS3Server(t)
+	m := newS3Minio(t, s, nil)
+
+	resp, err := m.Invoke(&bindings.InvokeRequest{Operation: PresignedGetOperation, Metadata: map[string]string{
+		"objectName": "exports/7f3a.csv", "expires": "1m", "downloadFileName": "orders.csv",
+	}})
+	require.NoError(t, err)
+	u, err := url.Parse(presigned(t, resp.Data).URL)
+	require.NoError(t, err)
+	assert.Equal(t, `attachment; filename=orders.csv`, u.Query().Get("response-content-disposition"))
+}
+
+func TestS3PreviewRange(t *testing.T) {
+	s := newS3Server(t)
+	m := newS3Minio(t, s, nil)
+	_, err := m.Invoke(&bindings.InvokeRequest{
+		Operation: bindings.CreateOperation,
+		Data:      []byte("hello world"),
+		Metadata:  map[string]string{"objectName": "a.txt"},
+	})
+	require.NoError(t, err)
+
+	resp, err := m.Invoke(&bindings.InvokeRequest{Operation: PreviewOperation, Metadata: map[string]string{"objectName": "a.txt", "previewBytes": "5"}})
+	require.NoError(t, err)
+	assert.Equal(t, "bytes=0-4", s.last(http.MethodGet, "").Header.Get("Range"))
+	assert.Equal(t, "hello", string(resp.Data))
+	assert.Equal(t, "11", resp.Metadata["size"])
+}
+
+func TestS3Checksum(t *testing.T) {
+	s := newS3Server(t)
+	m := newS3Minio(t, s, nil)
+	s.mu.Lock()
+	s.store("bucket/a.txt", []byte("hello"), http.Header{})
+	s.objects["bucket/a.txt"].header.Set("X-Amz-Checksum-Crc32c", "mnG7TA==")
+	s.mu.Unlock()
+
+	resp, err := m.Invoke(&bindings.InvokeRequest{Operation: ChecksumOperation, Metadata: map[string]string{"objectName": "a.txt"}})
+	require.NoError(t, err)
+	assert.Equal(t, "ENABLED", s.last(http.MethodHead, "").Header.Get("X-Amz-Checksum-Mode"))
+	assert.Contains(t, string(resp.Data), `"crc32c":"mnG7TA=="`)
+}
+
+func TestS3CopyDirectives(t *testing.T) {
+	s := newS3Server(t)
+	m := newS3Minio(t, s, nil)
+	s.mu.Lock()
+	s.store("bucket/a b.txt", []byte("hello"), http.Header{"Content-Type": {"text/plain"}, "X-Amz-Meta-Owner": {"alice"}})
+	s.mu.Unlock()
+
+	_, err := m.Invoke(&bindings.InvokeRequest{Operation: CopyOperation, Metadata: map[string]string{"objectName": "b.txt", "sourceKey": "a b.txt"}})
+	require.NoError(t, err)
+	put := s.last(http.MethodPut, "")
+	assert.Equal(t, "bucket/a%20b.txt", put.Header.Get("X-Amz-Copy-Source"))
+	assert.Empty(t, put.Header.Get("X-Amz-Metadata-Directive"))
+	assert.Empty(t, put.Header.Get("X-Amz-Tagging-Directive"))
+	assert.Equal(t, "alice", s.objects["bucket/b.txt"].header.Get("X-Amz-Meta-Owner"))
+
+	_, err = m.Invoke(&bindings.InvokeRequest{Operation: CopyOperation, Metadata: map[string]string{
+		"objectName":            "c.txt",
+		"sourceKey":             "a b.txt",
+		"metadataDirective":     "REPLACE",
+		"userMetadata-reviewer": "bob",
+		"contentType":           "text/markdown",
+		"taggingDirective":      "REPLACE",
+		"tag-team":              "b",
+	}})
+	require.NoError(t, err)
+	put = s.last(http.MethodPut, "")
+	assert.Equal(t, "REPLACE", put.Header.Get("X-Amz-Metadata-Directive"))
+	assert.Equal(t, "REPLACE", put.Header.Get("X-Amz-Tagging-Directive"))
+	assert.Equal(t, "team=b", put.Header.Get("X-Amz-Tagging"))
+	assert.Equal(t, "text/markdown", put.Header.Get("Content-Type"))
+	assert.Equal(t, "bob", put.Header.Get("X-Amz-Meta-Reviewer"))
+	assert.Empty(t, put.Header.Get("X-Amz-Meta-Owner"))
+}
+
+func TestS3PresignContentType(t *testing.T) {
+	s := newS3Server(t)
+	m := newS3Minio(t, s, nil)
+
+	resp, err := m.Invoke(&bindings.InvokeRequest{Operation: PresignedPutOperation, Metadata: map[string]string{"objectName": "a.png", "expires": "1m", "contentType": "image/png"}})
+	require.NoError(t, err)
+	put := presigned(t, resp.Data)
+	u, err := url.Parse(put.URL)
+	require.NoError(t, err)
+	assert.Equal(t, "content-type;host", u.Query().Get("X-Amz-SignedHeaders"))
+	assert.Equal(t, "image/png", put.Headers["Content-Type"])
+
+	resp, err = m.Invoke(&bindings.InvokeRequest{Operation: PresignedPostOperation, Metadata: map[string]string{
+		"objectName": "a.png", "expires": "1m", "contentType": "image/png", "maxContentLength": "1048576",
+	}})
+	require.NoError(t, err)
+	post := presigned(t, resp.Data)
+	assert.Equal(t, s.URL+"/bucket/", post.URL)
+	assert.Equal(t, "a.png", post.FormData["key"])
+	assert.Equal(t, "image/png", post.FormData["Content-Type"])
+	policy, err := base64.StdEncoding.DecodeString(post.FormData["policy"])
+	require.NoError(t, err)
+	assert.Contains(t, string(policy), `["content-length-range", 0, 1048576]`)
+	assert.Contains(t, string(policy), `["eq","$Content-Type","image/png"]`)
+	// signing doesn't contact the server
+	assert.Empty(t, s.requests)
+}
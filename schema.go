@@ -0,0 +1,215 @@
+package minio
+
+import (
+	"github.com/pkg/errors"
+	"sort"
+	"strings"
+)
+
+const StrictMetadataKey = "strictMetadata"
+
+const (
+	fieldString   = "string"
+	fieldBool     = "bool"
+	fieldInt      = "number"
+	fieldDuration = "duration"
+)
+
+// metadataSchema lists every component property the binding understands.
+// Request metadata such as objectName is not part of it.
+var metadataSchema = map[string]string{
+	Endpoint:                   fieldString,
+	AccessKey:                  fieldString,
+	SecretAccessKey:            fieldString,
+	SSLKey:                     fieldBool,
+	BucketKey:                  fieldString,
+	RegionKey:                  fieldString,
+	AllowCredentialOverrideKey: fieldBool,
+	GetTimeoutKey:              fieldDuration,
+	PutTimeoutKey:              fieldDuration,
+	ListTimeoutKey:             fieldDuration,
+	DeleteTimeoutKey:           fieldDuration,
+	DecodeQuotedKey:            fieldBool,
+	GenerateObjectNameKey:      fieldBool,
+	KeyTemplateKey:             fieldString,
+	ResponseEnvelopeKey:        fieldString,
+	StrictMetadataKey:          fieldBool,
+	MaxGetSizeKey:              fieldInt,
+	OversizedGetKey:            fieldString,
+	PartSizeKey:                fieldInt,
+	NumThreadsKey:              fieldInt,
+	DecompressKey:              fieldBool,
+	DisableContentSha256Key:    fieldBool,
+	DisableMultipartMd5Key:     fieldBool,
+	EncryptionKey:              fieldString,
+	SSECustomerKeyKey:          fieldString,
+	SSEKMSKeyIDKey:             fieldString,
+	SSEKMSContextKey:           fieldString,
+	ClientEncryptionKeyKey:     fieldString,
+	RequireEncryptedBucketKey:  fieldBool,
+	LogLevelKey:                fieldString,
+	TraceRequestsKey:           fieldBool,
+	AuditLogKey:                fieldString,
+	AuditPrefixKey:             fieldString,
+	MissingAsEmptyKey:          fieldBool,
+	SlowThresholdKey:           fieldDuration,
+	SlowGetThresholdKey:        fieldDuration,
+	SlowPutThresholdKey:        fieldDuration,
+	SlowListThresholdKey:       fieldDuration,
+	SlowDeleteThresholdKey:     fieldDuration,
+	AdminOperationsKey:         fieldBool,
+	FailoverCooldownKey:        fieldDuration,
+	ReadEndpointKey:            fieldString,
+	BucketLookupKey:            fieldString,
+	PresignEndpointKey:         fieldString,
+	SigningRegionKey:           fieldString,
+	AllowedRegionsKey:          fieldString,
+	DefaultPresignExpiryKey:    fieldDuration,
+	MaxPresignExpiryKey:        fieldDuration,
+	PortKey:                    fieldInt,
+	PathStyleKey:               fieldBool,
+	PreviewBytesKey:            fieldInt,
+	MaxUnpackSizeKey:           fieldInt,
+	MirrorEndpointKey:          fieldString,
+	MirrorBucketKey:            fieldString,
+	MirrorAccessKeyKey:         fieldString,
+	MirrorSecretKeyKey:         fieldString,
+	MirrorQueueSizeKey:         fieldInt,
+	MirrorRetryIntervalKey:     fieldDuration,
+	MirrorMaxAttemptsKey:       fieldInt,
+
+	MaxRetriesKey:      fieldInt,
+	RetryBackoffKey:    fieldDuration,
+	RetryMaxBackoffKey: fieldDuration,
+
+	AuthModeKey:             fieldString,
+	AnonymousKey:            fieldBool,
+	SessionTokenKey:         fieldString,
+	STSEndpointKey:          fieldString,
+	WebIdentityTokenKey:     fieldString,
+	WebIdentityTokenFileKey: fieldString,
+	WebIdentityDurationKey:  fieldDuration,
+	LDAPUsernameKey:         fieldString,
+	LDAPPasswordKey:         fieldString,
+	CredentialsFileKey:      fieldString,
+	CredentialsProfileKey:   fieldString,
+	SignatureVersionKey:     fieldString,
+	VaultAddrKey:            fieldString,
+	VaultTokenKey:           fieldString,
+	VaultTokenFileKey:       fieldString,
+	VaultPathKey:            fieldString,
+	VaultNamespaceKey:       fieldString,
+
+	ClientCertKey:    fieldString,
+	ClientKeyKey:     fieldString,
+	CACertKey:        fieldString,
+	CAPathKey:        fieldString,
+	SkipTLSVerifyKey: fieldBool,
+
+	MaxIdleConnsKey:          fieldInt,
+	MaxIdleConnsPerHostKey:   fieldInt,
+	IdleConnTimeoutKey:       fieldDuration,
+	TLSHandshakeTimeoutKey:   fieldDuration,
+	ResponseHeaderTimeoutKey: fieldDuration,
+
+	InputModeKey:           fieldString,
+	PollIntervalKey:        fieldDuration,
+	PollPrefixKey:          fieldString,
+	NotificationPrefixKey:  fieldString,
+	NotificationSuffixKey:  fieldString,
+	EventsKey:              fieldString,
+	ReconnectBackoffKey:    fieldDuration,
+	ReconnectMaxBackoffKey: fieldDuration,
+	FetchObjectOnEventKey:  fieldBool,
+	FetchMaxSizeKey:        fieldInt,
+	DeliveryRetriesKey:     fieldInt,
+	DeliveryBackoffKey:     fieldDuration,
+	DeadLetterPrefixKey:    fieldString,
+	DeadLetterBucketKey:    fieldString,
+	CheckpointKey:          fieldBool,
+	CheckpointObjectKey:    fieldString,
+	CheckpointIntervalKey:  fieldDuration,
+	PublishPubsubNameKey:   fieldString,
+	PublishTopicKey:        fieldString,
+}
+
+// GetComponentMetadata returns the supported component properties and their types.
+func (m *Minio) GetComponentMetadata() map[string]string {
+	fields := make(map[string]string, len(metadataSchema))
+	for k, v := range metadataSchema {
+		fields[k] = v
+	}
+	return fields
+}
+
+// validateMetadata reports properties outside the schema, suggesting the
+// closest known name. Unknown properties are only fatal with strictMetadata.
+func (m *Minio) validateMetadata(p map[string]string) error {
+	var unknown []string
+	for k := range p {
+		if _, ok := metadataSchema[k]; !ok {
+			unknown = append(unknown, k)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+	sort.Strings(unknown)
+
+	messages := make([]string, 0, len(unknown))
+	for _, k := range unknown {
+		msg := "unknown Minio property " + k
+		if s := suggestProperty(k); s != "" {
+			msg += ", did you mean " + s + "?"
+		}
+		messages = append(messages, msg)
+	}
+	if propertyToBool(p, StrictMetadataKey) {
+		return errors.Errorf("%s", strings.Join(messages, "; "))
+	}
+	for _, msg := range messages {
+		m.logger.Warn(msg)
+	}
+	return nil
+}
+
+func suggestProperty(name string) string {
+	best, bestDistance := "", len(name)/2+1
+	for k := range metadataSchema {
+		d := editDistance(strings.ToLower(name), strings.ToLower(k))
+		if d < bestDistance || (d == bestDistance && best != "" && k < best) {
+			best, bestDistance = k, d
+		}
+	}
+	return best
+}
+
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min3(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
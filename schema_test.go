@@ -0,0 +1,28 @@
+package minio
+
+import (
+	"github.com/dapr/kit/logger"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestGetComponentMetadata(t *testing.T) {
+	m := NewMinio(logger.NewLogger("minio"))
+	fields := m.GetComponentMetadata()
+	assert.Equal(t, fieldString, fields[Endpoint])
+	assert.Equal(t, fieldDuration, fields[GetTimeoutKey])
+	assert.Equal(t, fieldBool, fields[SkipTLSVerifyKey])
+}
+
+func TestValidateMetadata(t *testing.T) {
+	m := NewMinio(logger.NewLogger("minio"))
+
+	assert.Nil(t, m.validateMetadata(map[string]string{Endpoint: "localhost:9000", BucketKey: "b"}))
+	assert.Nil(t, m.validateMetadata(map[string]string{"bukcet": "b"}))
+
+	err := m.validateMetadata(map[string]string{"bukcet": "b", "endpiont": "x", StrictMetadataKey: "true"})
+	assert.EqualError(t, err, "unknown Minio property bukcet, did you mean bucket?; unknown Minio property endpiont, did you mean endpoint?")
+
+	err = m.validateMetadata(map[string]string{"somethingElse": "x", StrictMetadataKey: "true"})
+	assert.EqualError(t, err, "unknown Minio property somethingElse")
+}
@@ -0,0 +1,145 @@
+package minio
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/dapr/components-contrib/bindings"
+	"github.com/minio/madmin-go"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/pkg/errors"
+	"time"
+)
+
+const (
+	ServiceAccountKey = "serviceAccount"
+	// DurationKey makes createServiceAccount return temporary credentials
+	// that expire after this long instead of a service account.
+	DurationKey = "duration"
+
+	CreateServiceAccountOperation bindings.OperationKind = "createServiceAccount"
+	DeleteServiceAccountOperation bindings.OperationKind = "deleteServiceAccount"
+
+	// minTemporaryDuration is the shortest validity minio-go requests
+	minTemporaryDuration = time.Hour
+)
+
+type serviceAccountResponse struct {
+	AccessKey    string     `json:"accessKey"`
+	SecretKey    string     `json:"secretKey,omitempty"`
+	SessionToken string     `json:"sessionToken,omitempty"`
+	Expiration   *time.Time `json:"expiration,omitempty"`
+	TargetUser   string     `json:"targetUser,omitempty"`
+}
+
+// createServiceAccount mints an access key pair for the binding's user, or for
+// userAccessKey when given, so applications can talk to MinIO directly
+// without the binding's credentials. The optional request data is a policy
+// document narrowing the account to a subset of the parent's permissions.
+// Service accounts don't expire and are removed with deleteServiceAccount;
+// with duration set, the binding's user instead assumes a role through STS,
+// returning keys and a session token that expire after duration, at least an
+// hour.
+func (m *Minio) createServiceAccount(ctx context.Context, admin adminClient, req *bindings.InvokeRequest) (*bindings.InvokeResponse, error) {
+	opts := madmin.AddServiceAccountReq{TargetUser: req.Metadata[UserAccessKeyKey]}
+	if len(req.Data) > 0 {
+		if !json.Valid(req.Data) {
+			return nil, errors.Errorf("service account policy is not valid JSON")
+		}
+		opts.Policy = json.RawMessage(req.Data)
+	}
+	duration, err := durationProperty(req.Metadata, DurationKey, 0)
+	if err != nil {
+		return nil, err
+	}
+	if duration > 0 {
+		if opts.TargetUser != "" {
+			return nil, errors.Errorf("minio binding error. temporary credentials are only issued for the binding's user, %s is not supported with %s", UserAccessKeyKey, DurationKey)
+		}
+		if duration < minTemporaryDuration {
+			return nil, errors.Errorf("minio binding error. %s must be at least %s", DurationKey, minTemporaryDuration)
+		}
+		return m.assumeRole(string(opts.Policy), duration)
+	}
+
+	creds, err := admin.AddServiceAccount(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("minio binding error. create service account: %w", err)
+	}
+	jsonResponse, err := json.Marshal(serviceAccountResponse{
+		AccessKey:  creds.AccessKey,
+		SecretKey:  creds.SecretKey,
+		TargetUser: opts.TargetUser,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &bindings.InvokeResponse{
+		Data:     jsonResponse,
+		Metadata: map[string]string{ServiceAccountKey: creds.AccessKey},
+	}, nil
+}
+
+// assumeRole requests STS credentials for the binding's user, narrowed by the
+// session policy. MinIO only accepts AssumeRole signed with long-term keys.
+func (m *Minio) assumeRole(policy string, duration time.Duration) (*bindings.InvokeResponse, error) {
+	if m.credentials == nil {
+		return nil, errors.Errorf("minio binding error. binding is not initialized")
+	}
+	value, err := m.credentials.value()
+	if err != nil {
+		return nil, fmt.Errorf("minio binding error. assume role: %w", err)
+	}
+	if value.AccessKeyID == "" || value.SessionToken != "" {
+		return nil, errors.Errorf("minio binding error. temporary credentials require the binding to use static credentials")
+	}
+	sts := &credentials.STSAssumeRole{
+		Client:      stsClient(m.transport),
+		STSEndpoint: stsEndpoint(m.properties, m.endpoint, m.secure),
+		Options: credentials.STSAssumeRoleOptions{
+			AccessKey:       value.AccessKeyID,
+			SecretKey:       value.SecretAccessKey,
+			Policy:          policy,
+			DurationSeconds: int(duration.Seconds()),
+		},
+	}
+	expiration := time.Now().Add(duration).UTC()
+	temporary, err := sts.Retrieve()
+	if err != nil {
+		return nil, fmt.Errorf("minio binding error. assume role: %w", err)
+	}
+	jsonResponse, err := json.Marshal(serviceAccountResponse{
+		AccessKey:    temporary.AccessKeyID,
+		SecretKey:    temporary.SecretAccessKey,
+		SessionToken: temporary.SessionToken,
+		Expiration:   &expiration,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &bindings.InvokeResponse{
+		Data: jsonResponse,
+		Metadata: map[string]string{
+			ServiceAccountKey: temporary.AccessKeyID,
+			"expiration":      expiration.Format(time.RFC3339),
+		},
+	}, nil
+}
+
+func deleteServiceAccount(ctx context.Context, admin adminClient, p map[string]string) (*bindings.InvokeResponse, error) {
+	account := p[ServiceAccountKey]
+	if account == "" {
+		return nil, errors.Errorf("missing %s field", ServiceAccountKey)
+	}
+	if err := admin.DeleteServiceAccount(ctx, account); err != nil {
+		return nil, fmt.Errorf("minio binding error. delete service account: %w", err)
+	}
+	jsonResponse, err := json.Marshal(serviceAccountResponse{AccessKey: account})
+	if err != nil {
+		return nil, err
+	}
+	return &bindings.InvokeResponse{
+		Data:     jsonResponse,
+		Metadata: map[string]string{ServiceAccountKey: account},
+	}, nil
+}
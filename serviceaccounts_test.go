@@ -0,0 +1,72 @@
+package minio
+
+import (
+	"github.com/dapr/components-contrib/bindings"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestServiceAccountLifecycle(t *testing.T) {
+	m, admin := newFakeAdminMinio()
+
+	resp, err := m.Invoke(&bindings.InvokeRequest{
+		Operation: CreateServiceAccountOperation,
+		Data:      []byte(tenantPolicy),
+		Metadata:  map[string]string{"userAccessKey": "tenant-a"},
+	})
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"accessKey":"SVCACCT1","secretKey":"secret-SVCACCT1","targetUser":"tenant-a"}`, string(resp.Data))
+	assert.Equal(t, "SVCACCT1", resp.Metadata["serviceAccount"])
+	assert.Equal(t, "tenant-a", admin.serviceAccounts["SVCACCT1"].TargetUser)
+	assert.JSONEq(t, tenantPolicy, string(admin.serviceAccounts["SVCACCT1"].Policy))
+
+	// the audit record names the account but never its secret
+	record := m.newAuditRecord(&bindings.InvokeRequest{Operation: CreateServiceAccountOperation, Metadata: map[string]string{}}, resp, nil, time.Now())
+	assert.Equal(t, "SVCACCT1", record.ServiceAccount)
+
+	resp, err = m.Invoke(&bindings.InvokeRequest{
+		Operation: DeleteServiceAccountOperation,
+		Metadata:  map[string]string{"serviceAccount": "SVCACCT1"},
+	})
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"accessKey":"SVCACCT1"}`, string(resp.Data))
+	assert.Empty(t, admin.serviceAccounts)
+
+	_, err = m.Invoke(&bindings.InvokeRequest{
+		Operation: DeleteServiceAccountOperation,
+		Metadata:  map[string]string{"serviceAccount": "SVCACCT1"},
+	})
+	assert.Error(t, err)
+}
+
+func TestCreateServiceAccountWithoutPolicy(t *testing.T) {
+	m, admin := newFakeAdminMinio()
+
+	resp, err := m.Invoke(&bindings.InvokeRequest{Operation: CreateServiceAccountOperation, Metadata: map[string]string{}})
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"accessKey":"SVCACCT1","secretKey":"secret-SVCACCT1"}`, string(resp.Data))
+	assert.Nil(t, admin.serviceAccounts["SVCACCT1"].Policy)
+}
+
+func TestServiceAccountValidation(t *testing.T) {
+	m, admin := newFakeAdminMinio()
+
+	_, err := m.Invoke(&bindings.InvokeRequest{Operation: CreateServiceAccountOperation, Data: []byte("{")})
+	assert.Error(t, err)
+	_, err = m.Invoke(&bindings.InvokeRequest{Operation: DeleteServiceAccountOperation, Metadata: map[string]string{}})
+	assert.Error(t, err)
+	assert.Empty(t, admin.serviceAccounts)
+}
+
+func TestTemporaryServiceAccountValidation(t *testing.T) {
+	m, admin := newFakeAdminMinio()
+
+	_, err := m.Invoke(&bindings.InvokeRequest{Operation: CreateServiceAccountOperation, Metadata: map[string]string{DurationKey: "1h", UserAccessKeyKey: "tenant-a"}})
+	assert.EqualError(t, err, "minio binding error. temporary credentials are only issued for the binding's user, userAccessKey is not supported with duration")
+	_, err = m.Invoke(&bindings.InvokeRequest{Operation: CreateServiceAccountOperation, Metadata: map[string]string{DurationKey: "soon"}})
+	assert.EqualError(t, err, "duration soon is invalid")
+	_, err = m.Invoke(&bindings.InvokeRequest{Operation: CreateServiceAccountOperation, Metadata: map[string]string{DurationKey: "15m"}})
+	assert.EqualError(t, err, "minio binding error. duration must be at least 1h0m0s")
+	assert.Empty(t, admin.serviceAccounts)
+}
@@ -0,0 +1,164 @@
+package minio
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/dapr/components-contrib/bindings"
+	"github.com/minio/madmin-go"
+	"sort"
+	"strconv"
+)
+
+const SiteReplicationStatusOperation bindings.OperationKind = "siteReplicationStatus"
+
+type siteReplicationResponse struct {
+	Enabled    bool                      `json:"enabled"`
+	Name       string                    `json:"name,omitempty"`
+	InSync     bool                      `json:"inSync"`
+	Sites      []siteReplicationPeer     `json:"sites"`
+	Mismatches []siteReplicationMismatch `json:"mismatches,omitempty"`
+}
+
+type siteReplicationPeer struct {
+	Name         string                  `json:"name"`
+	Endpoint     string                  `json:"endpoint"`
+	DeploymentID string                  `json:"deploymentID"`
+	Summary      *siteReplicationSummary `json:"summary,omitempty"`
+}
+
+// siteReplicationSummary counts the items of a site and how many of them are
+// replicated to the other sites.
+type siteReplicationSummary struct {
+	Buckets            int `json:"buckets"`
+	ReplicatedBuckets  int `json:"replicatedBuckets"`
+	Users              int `json:"users"`
+	ReplicatedUsers    int `json:"replicatedUsers"`
+	Groups             int `json:"groups"`
+	ReplicatedGroups   int `json:"replicatedGroups"`
+	Policies           int `json:"policies"`
+	ReplicatedPolicies int `json:"replicatedPolicies"`
+}
+
+// siteReplicationMismatch is a bucket, user, group or policy that is missing
+// from, or configured differently on, the listed sites.
+type siteReplicationMismatch struct {
+	Type  string   `json:"type"`
+	Name  string   `json:"name"`
+	Sites []string `json:"sites"`
+}
+
+// siteReplicationStatus reports whether site replication is set up, the
+// sites taking part in it, how much of each site is replicated, and the
+// buckets, users, groups and policies that are out of sync. The replicator's
+// service account is not included.
+func siteReplicationStatus(ctx context.Context, admin adminClient) (*bindings.InvokeResponse, error) {
+	info, err := admin.SiteReplicationInfo(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("minio binding error. site replication status: %w", err)
+	}
+	resp := siteReplicationResponse{
+		Enabled: info.Enabled,
+		Name:    info.Name,
+		InSync:  true,
+		Sites:   make([]siteReplicationPeer, 0, len(info.Sites)),
+	}
+	var status madmin.SRStatusInfo
+	if info.Enabled {
+		status, err = admin.SRStatusInfo(ctx, madmin.SRStatusOptions{Buckets: true, Policies: true, Users: true, Groups: true})
+		if err != nil {
+			return nil, fmt.Errorf("minio binding error. site replication status: %w", err)
+		}
+	}
+	for _, site := range info.Sites {
+		peer := siteReplicationPeer{
+			Name:         site.Name,
+			Endpoint:     site.Endpoint,
+			DeploymentID: site.DeploymentID,
+		}
+		if summary, ok := status.StatsSummary[site.DeploymentID]; ok {
+			peer.Summary = &siteReplicationSummary{
+				Buckets:            summary.TotalBucketsCount,
+				ReplicatedBuckets:  summary.ReplicatedBuckets,
+				Users:              summary.TotalUsersCount,
+				ReplicatedUsers:    summary.ReplicatedUsers,
+				Groups:             summary.TotalGroupsCount,
+				ReplicatedGroups:   summary.ReplicatedGroups,
+				Policies:           summary.TotalIAMPoliciesCount,
+				ReplicatedPolicies: summary.ReplicatedIAMPolicies,
+			}
+		}
+		resp.Sites = append(resp.Sites, peer)
+	}
+	resp.Mismatches = siteReplicationMismatches(status)
+	resp.InSync = len(resp.Mismatches) == 0
+
+	jsonResponse, err := json.Marshal(resp)
+	if err != nil {
+		return nil, err
+	}
+	return &bindings.InvokeResponse{
+		Data: jsonResponse,
+		Metadata: map[string]string{
+			"enabled":    strconv.FormatBool(info.Enabled),
+			"sites":      strconv.Itoa(len(info.Sites)),
+			"inSync":     strconv.FormatBool(resp.InSync),
+			"mismatches": strconv.Itoa(len(resp.Mismatches)),
+		},
+	}, nil
+}
+
+// siteReplicationMismatches lists the entities MinIO reports as missing or
+// differing on some site, ordered by type and name.
+func siteReplicationMismatches(status madmin.SRStatusInfo) []siteReplicationMismatch {
+	var mismatches []siteReplicationMismatch
+	add := func(kind, name string, sites []string) {
+		if len(sites) > 0 {
+			sort.Strings(sites)
+			mismatches = append(mismatches, siteReplicationMismatch{Type: kind, Name: name, Sites: sites})
+		}
+	}
+	for name, stats := range status.BucketStats {
+		var sites []string
+		for _, s := range stats {
+			if !s.HasBucket || s.TagMismatch || s.OLockConfigMismatch || s.PolicyMismatch || s.SSEConfigMismatch || s.ReplicationCfgMismatch || s.QuotaCfgMismatch {
+				sites = append(sites, s.DeploymentID)
+			}
+		}
+		add("bucket", name, sites)
+	}
+	for name, stats := range status.UserStats {
+		var sites []string
+		for _, s := range stats {
+			if !s.HasUser || s.PolicyMismatch || s.UserInfoMismatch {
+				sites = append(sites, s.DeploymentID)
+			}
+		}
+		add("user", name, sites)
+	}
+	for name, stats := range status.GroupStats {
+		var sites []string
+		for _, s := range stats {
+			if !s.HasGroup || s.PolicyMismatch || s.GroupDescMismatch {
+				sites = append(sites, s.DeploymentID)
+			}
+		}
+		add("group", name, sites)
+	}
+	for name, stats := range status.PolicyStats {
+		var sites []string
+		for _, s := range stats {
+			if !s.HasPolicy || s.PolicyMismatch {
+				sites = append(sites, s.DeploymentID)
+			}
+		}
+		add("policy", name, sites)
+	}
+	sort.Slice(mismatches, func(i, j int) bool {
+		if mismatches[i].Type != mismatches[j].Type {
+			return mismatches[i].Type < mismatches[j].Type
+		}
+		return mismatches[i].Name < mismatches[j].Name
+	})
+	return mismatches
+}
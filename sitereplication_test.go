@@ -0,0 +1,72 @@
+package minio
+
+import (
+	"github.com/dapr/components-contrib/bindings"
+	"github.com/minio/madmin-go"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSiteReplicationStatus(t *testing.T) {
+	m, admin := newFakeAdminMinio()
+
+	resp, err := m.Invoke(&bindings.InvokeRequest{Operation: SiteReplicationStatusOperation})
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"enabled":false,"inSync":true,"sites":[]}`, string(resp.Data))
+	assert.Equal(t, map[string]string{"enabled": "false", "sites": "0", "inSync": "true", "mismatches": "0"}, resp.Metadata)
+
+	admin.siteReplication = madmin.SiteReplicationInfo{
+		Enabled: true,
+		Name:    "eu-1",
+		Sites: []madmin.PeerInfo{
+			{Name: "eu-1", Endpoint: "https://minio-eu-1:9000", DeploymentID: "d1"},
+			{Name: "us-1", Endpoint: "https://minio-us-1:9000", DeploymentID: "d2"},
+		},
+		ServiceAccountAccessKey: "site-replicator-0",
+	}
+	admin.srStatus = madmin.SRStatusInfo{
+		Enabled: true,
+		StatsSummary: map[string]madmin.SRSiteSummary{
+			"d1": {TotalBucketsCount: 3, ReplicatedBuckets: 3, TotalUsersCount: 2, ReplicatedUsers: 2},
+			"d2": {TotalBucketsCount: 2, ReplicatedBuckets: 2, TotalUsersCount: 2, ReplicatedUsers: 1},
+		},
+		BucketStats: map[string]map[string]madmin.SRBucketStatsSummary{
+			"orders": {
+				"d1": {DeploymentID: "d1", HasBucket: true},
+				"d2": {DeploymentID: "d2"},
+			},
+			"invoices": {
+				"d1": {DeploymentID: "d1", HasBucket: true},
+				"d2": {DeploymentID: "d2", HasBucket: true},
+			},
+		},
+		UserStats: map[string]map[string]madmin.SRUserStatsSummary{
+			"tenant-a": {
+				"d1": {DeploymentID: "d1", HasUser: true},
+				"d2": {DeploymentID: "d2", HasUser: true, PolicyMismatch: true},
+			},
+		},
+	}
+	resp, err = m.Invoke(&bindings.InvokeRequest{Operation: SiteReplicationStatusOperation})
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{
+		"enabled": true,
+		"name": "eu-1",
+		"inSync": false,
+		"sites": [
+			{"name": "eu-1", "endpoint": "https://minio-eu-1:9000", "deploymentID": "d1", "summary": {
+				"buckets": 3, "replicatedBuckets": 3, "users": 2, "replicatedUsers": 2,
+				"groups": 0, "replicatedGroups": 0, "policies": 0, "replicatedPolicies": 0
+			}},
+			{"name": "us-1", "endpoint": "https://minio-us-1:9000", "deploymentID": "d2", "summary": {
+				"buckets": 2, "replicatedBuckets": 2, "users": 2, "replicatedUsers": 1,
+				"groups": 0, "replicatedGroups": 0, "policies": 0, "replicatedPolicies": 0
+			}}
+		],
+		"mismatches": [
+			{"type": "bucket", "name": "orders", "sites": ["d2"]},
+			{"type": "user", "name": "tenant-a", "sites": ["d2"]}
+		]
+	}`, string(resp.Data))
+	assert.Equal(t, map[string]string{"enabled": "true", "sites": "2", "inSync": "false", "mismatches": "2"}, resp.Metadata)
+}
@@ -0,0 +1,216 @@
+// Package statestore implements a Dapr state store on top of MinIO objects.
+// Each key is stored as one object, and the object's ETag is the state ETag.
+// The component accepts the same connection metadata as the MinIO binding.
+package statestore
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/dapr/components-contrib/bindings"
+	"github.com/dapr/components-contrib/state"
+	"github.com/dapr/components-contrib/state/utils"
+	"github.com/dapr/kit/logger"
+	"github.com/pkg/errors"
+	"github.com/putao520/minio-dapr"
+)
+
+const (
+	KeyPrefixKey = "keyPrefix"
+
+	// BulkConcurrencyKey bounds the concurrent gets of a BulkGet.
+	BulkConcurrencyKey = "bulkConcurrency"
+)
+
+// component properties that would change the shape of the binding's
+// responses, which the store relies on; with oversizedGet=presign a large
+// value would be read back as a URL
+var bindingOnlyProperties = []string{
+	minio.ResponseEnvelopeKey,
+	minio.OversizedGetKey,
+	minio.KeyTemplateKey,
+	minio.GenerateObjectNameKey,
+	minio.DecodeQuotedKey,
+	minio.EncodingKey,
+}
+
+type StateStore struct {
+	state.DefaultBulkStore
+
+	binding         *minio.Minio
+	logger          logger.Logger
+	prefix          string
+	bulkConcurrency string
+}
+
+var _ = state.Store(&StateStore{})
+
+func NewMinioStateStore(logger logger.Logger) *StateStore {
+	s := &StateStore{
+		binding: minio.NewMinio(logger),
+		logger:  logger,
+	}
+	s.DefaultBulkStore = state.NewDefaultBulkStore(s)
+	return s
+}
+
+func (s *StateStore) Init(metadata state.Metadata) error {
+	p := make(map[string]string, len(metadata.Properties))
+	for k, v := range metadata.Properties {
+		p[k] = v
+	}
+	for _, key := range bindingOnlyProperties {
+		if _, ok := p[key]; ok {
+			return errors.Errorf("Minio state store does not support %s", key)
+		}
+	}
+	s.prefix = p[KeyPrefixKey]
+	s.bulkConcurrency = p[BulkConcurrencyKey]
+	delete(p, KeyPrefixKey)
+	delete(p, BulkConcurrencyKey)
+
+	return s.binding.Init(bindings.Metadata{Properties: p})
+}
+
+func (s *StateStore) Features() []state.Feature {
+	return []state.Feature{state.FeatureETag}
+}
+
+func (s *StateStore) Close() error {
+	return s.binding.Close()
+}
+
+func (s *StateStore) Get(req *state.GetRequest) (*state.GetResponse, error) {
+	resp, err := s.binding.Invoke(&bindings.InvokeRequest{
+		Operation: bindings.GetOperation,
+		Metadata: map[string]string{
+			minio.ObjectNameKey:     s.prefix + req.Key,
+			minio.MissingAsEmptyKey: "true",
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return getResponse(resp.Data, resp.Metadata), nil
+}
+
+// getResponse converts the binding's get result; a missing key has no data
+// and no ETag.
+func getResponse(data []byte, metadata map[string]string) *state.GetResponse {
+	if metadata["exists"] == "false" {
+		return &state.GetResponse{}
+	}
+	resp := &state.GetResponse{Data: data}
+	if etag := metadata["etag"]; etag != "" {
+		resp.ETag = &etag
+	}
+	return resp
+}
+
+// Set writes the value. With an ETag the object must still carry it, which
+// MinIO checks atomically with the write; with first-write concurrency and no
+// ETag the key must not exist yet, which is only checked before the write.
+func (s *StateStore) Set(req *state.SetRequest) error {
+	if err := state.CheckRequestOptions(req.Options); err != nil {
+		return err
+	}
+	data, err := utils.Marshal(req.Value, json.Marshal)
+	if err != nil {
+		return fmt.Errorf("minio state store error. marshal value: %w", err)
+	}
+	metadata := map[string]string{minio.ObjectNameKey: s.prefix + req.Key}
+	switch {
+	case req.ETag != nil && *req.ETag != "":
+		metadata[minio.IfMatchKey] = *req.ETag
+	case req.Options.Concurrency == state.FirstWrite:
+		metadata[minio.IfNoneMatchKey] = "*"
+	}
+	_, err = s.binding.Invoke(&bindings.InvokeRequest{
+		Operation: bindings.CreateOperation,
+		Data:      data,
+		Metadata:  metadata,
+	})
+	return etagError(err)
+}
+
+func (s *StateStore) Delete(req *state.DeleteRequest) error {
+	if err := state.CheckRequestOptions(req.Options); err != nil {
+		return err
+	}
+	metadata := map[string]string{minio.ObjectNameKey: s.prefix + req.Key}
+	if req.ETag != nil && *req.ETag != "" {
+		metadata[minio.IfMatchKey] = *req.ETag
+	}
+	_, err := s.binding.Invoke(&bindings.InvokeRequest{
+		Operation: bindings.DeleteOperation,
+		Metadata:  metadata,
+	})
+	return etagError(err)
+}
+
+// etagError reports failed ETag preconditions as the state.ETagError Dapr
+// translates into a conflict for the app.
+func etagError(err error) error {
+	if minio.ErrorCode(err) == minio.ErrCodePreconditionFailed {
+		return state.NewETagError(state.ETagMismatch, err)
+	}
+	return err
+}
+
+// BulkGet fetches the keys concurrently through the binding's getBatch
+// operation. A failure on one key is reported in its response only.
+func (s *StateStore) BulkGet(req []state.GetRequest) (bool, []state.BulkGetResponse, error) {
+	if len(req) == 0 {
+		return true, nil, nil
+	}
+	names := make([]string, len(req))
+	for i, r := range req {
+		names[i] = s.prefix + r.Key
+	}
+	data, err := json.Marshal(names)
+	if err != nil {
+		return false, nil, err
+	}
+	metadata := map[string]string{minio.MissingAsEmptyKey: "true"}
+	if s.bulkConcurrency != "" {
+		metadata[minio.ConcurrencyKey] = s.bulkConcurrency
+	}
+	resp, err := s.binding.Invoke(&bindings.InvokeRequest{
+		Operation: minio.GetBatchOperation,
+		Data:      data,
+		Metadata:  metadata,
+	})
+	if err != nil {
+		return false, nil, err
+	}
+	responses, err := bulkGetResponses(req, s.prefix, resp.Data)
+	if err != nil {
+		return false, nil, err
+	}
+	return true, responses, nil
+}
+
+type batchGetResult struct {
+	Data     []byte            `json:"data"`
+	Metadata map[string]string `json:"metadata"`
+	Error    string            `json:"error"`
+}
+
+// bulkGetResponses maps the getBatch result back to the requested keys, in
+// request order.
+func bulkGetResponses(req []state.GetRequest, prefix string, data []byte) ([]state.BulkGetResponse, error) {
+	var results map[string]batchGetResult
+	if err := json.Unmarshal(data, &results); err != nil {
+		return nil, fmt.Errorf("minio state store error. bulk get response: %w", err)
+	}
+	responses := make([]state.BulkGetResponse, len(req))
+	for i, r := range req {
+		result := results[prefix+r.Key]
+		responses[i] = state.BulkGetResponse{Key: r.Key, Error: result.Error}
+		if result.Error == "" {
+			get := getResponse(result.Data, result.Metadata)
+			responses[i].Data = get.Data
+			responses[i].ETag = get.ETag
+		}
+	}
+	return responses, nil
+}
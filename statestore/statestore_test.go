@@ -0,0 +1,61 @@
+package statestore
+
+import (
+	"errors"
+	"github.com/dapr/components-contrib/state"
+	"github.com/dapr/kit/logger"
+	"github.com/putao520/minio-dapr"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestGetResponse(t *testing.T) {
+	resp := getResponse([]byte(`{"a":1}`), map[string]string{"etag": "abc", "key": "k"})
+	assert.Equal(t, []byte(`{"a":1}`), resp.Data)
+	assert.Equal(t, "abc", *resp.ETag)
+
+	resp = getResponse(nil, map[string]string{"exists": "false"})
+	assert.Nil(t, resp.Data)
+	assert.Nil(t, resp.ETag)
+}
+
+func TestBulkGetResponses(t *testing.T) {
+	req := []state.GetRequest{{Key: "b"}, {Key: "a"}, {Key: "missing"}, {Key: "denied"}}
+	data := []byte(`{
+		"app/a": {"data": "MQ==", "metadata": {"etag": "e1"}},
+		"app/b": {"data": "Mg==", "metadata": {"etag": "e2"}},
+		"app/missing": {"metadata": {"exists": "false"}},
+		"app/denied": {"error": "access denied", "code": "AccessDenied"}
+	}`)
+
+	responses, err := bulkGetResponses(req, "app/", data)
+	assert.Nil(t, err)
+	assert.Len(t, responses, 4)
+	assert.Equal(t, "b", responses[0].Key)
+	assert.Equal(t, []byte("2"), responses[0].Data)
+	assert.Equal(t, "e2", *responses[0].ETag)
+	assert.Equal(t, []byte("1"), responses[1].Data)
+	assert.Nil(t, responses[2].Data)
+	assert.Nil(t, responses[2].ETag)
+	assert.Equal(t, "access denied", responses[3].Error)
+}
+
+func TestInitRejectsBindingOnlyProperties(t *testing.T) {
+	s := NewMinioStateStore(logger.NewLogger("minio"))
+	err := s.Init(state.Metadata{Properties: map[string]string{"responseEnvelope": "v1"}})
+	assert.EqualError(t, err, "Minio state store does not support responseEnvelope")
+
+	err = s.Init(state.Metadata{Properties: map[string]string{"oversizedGet": "presign"}})
+	assert.EqualError(t, err, "Minio state store does not support oversizedGet")
+}
+
+func TestETagError(t *testing.T) {
+	err := etagError(&minio.Error{Code: minio.ErrCodePreconditionFailed, Operation: "create", Err: errors.New("stale")})
+	var etagErr *state.ETagError
+	assert.True(t, errors.As(err, &etagErr))
+	assert.Equal(t, state.ETagMismatch, etagErr.Kind())
+
+	other := &minio.Error{Code: minio.ErrCodeAccessDenied, Operation: "create", Err: errors.New("denied")}
+	assert.Equal(t, error(other), etagError(other))
+	assert.Nil(t, etagError(nil))
+}
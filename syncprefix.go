@@ -0,0 +1,168 @@
+package minio
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/dapr/components-contrib/bindings"
+	"github.com/minio/minio-go/v7"
+	"github.com/pkg/errors"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+const (
+	// SyncPrefixOperation makes destinationPrefix, in destinationBucket or the
+	// binding's bucket, match sourcePrefix. Objects missing from the
+	// destination or differing in ETag or size are copied server-side; with
+	// deleteExtras, destination objects missing from the source are removed.
+	// A multipart object gets a different ETag when copied, so it is copied
+	// again on every sync.
+	SyncPrefixOperation bindings.OperationKind = "syncPrefix"
+
+	DestinationBucketKey = "destinationBucket"
+	DeleteExtrasKey      = "deleteExtras"
+	// DestinationRootKey syncs into the whole of destinationBucket, with no
+	// destinationPrefix. Both prefixes are required otherwise, so a missing
+	// one can't make deleteExtras empty a bucket.
+	DestinationRootKey = "destinationRoot"
+
+	syncActionCopy   = "copy"
+	syncActionDelete = "delete"
+)
+
+type syncResult struct {
+	Key    string `json:"key"`
+	Action string `json:"action"`
+	Error  string `json:"error,omitempty"`
+	Code   string `json:"code,omitempty"`
+}
+
+type syncPrefixResponse struct {
+	SourcePrefix      string       `json:"sourcePrefix"`
+	DestinationBucket string       `json:"destinationBucket"`
+	DestinationPrefix string       `json:"destinationPrefix"`
+	Total             int          `json:"total"`
+	Unchanged         int          `json:"unchanged"`
+	Copied            int64        `json:"copied"`
+	Deleted           int64        `json:"deleted"`
+	Failed            int64        `json:"failed"`
+	Objects           []syncResult `json:"objects"`
+}
+
+func (m *Minio) syncPrefix(ctx context.Context, req *bindings.InvokeRequest) (*bindings.InvokeResponse, error) {
+	p := req.Metadata
+
+	bucket := p[DestinationBucketKey]
+	if bucket == "" {
+		bucket = m.Bucket
+	}
+	// within one bucket, the prefixes mustn't contain one another
+	source, destination := p[SourcePrefixKey], p[DestinationPrefixKey]
+	switch {
+	case bucket == m.Bucket:
+		var err error
+		if source, destination, err = parsePrefixes(p); err != nil {
+			return nil, err
+		}
+	case source == "":
+		return nil, errors.Errorf("missing %s field", SourcePrefixKey)
+	case destination == "" && !propertyToBool(p, DestinationRootKey):
+		return nil, errors.Errorf("missing %s field", DestinationPrefixKey)
+	}
+	concurrency, err := batchConcurrency(p)
+	if err != nil {
+		return nil, err
+	}
+	client, err := m.clientFor(p)
+	if err != nil {
+		return nil, err
+	}
+	client = primaryClient(client)
+	sourceSSE, err := readEncryption(m.properties, p)
+	if err != nil {
+		return nil, err
+	}
+	destSSE, err := serverSideEncryption(m.properties, p)
+	if err != nil {
+		return nil, err
+	}
+
+	sourceObjects, err := m.listPrefix(ctx, client, m.Bucket, source)
+	if err != nil {
+		return nil, err
+	}
+	destObjects, err := m.listPrefix(ctx, client, bucket, destination)
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[string]minio.ObjectInfo, len(destObjects))
+	for _, object := range destObjects {
+		existing[strings.TrimPrefix(object.Key, destination)] = object
+	}
+
+	resp := syncPrefixResponse{
+		SourcePrefix:      source,
+		DestinationBucket: bucket,
+		DestinationPrefix: destination,
+		Total:             len(sourceObjects),
+		Objects:           []syncResult{},
+	}
+	for _, object := range sourceObjects {
+		name := strings.TrimPrefix(object.Key, source)
+		current, ok := existing[name]
+		delete(existing, name)
+		if ok && current.ETag == object.ETag && current.Size == object.Size {
+			resp.Unchanged++
+			continue
+		}
+		resp.Objects = append(resp.Objects, syncResult{Key: destination + name, Action: syncActionCopy})
+	}
+	if propertyToBool(p, DeleteExtrasKey) {
+		extras := make([]string, 0, len(existing))
+		for name := range existing {
+			extras = append(extras, name)
+		}
+		sort.Strings(extras)
+		for _, name := range extras {
+			resp.Objects = append(resp.Objects, syncResult{Key: destination + name, Action: syncActionDelete})
+		}
+	}
+
+	runBounded(concurrency, len(resp.Objects), func(i int) {
+		result := &resp.Objects[i]
+		var err error
+		if result.Action == syncActionCopy {
+			err = m.copyObject(ctx, client, source+strings.TrimPrefix(result.Key, destination), bucket, result.Key, sourceSSE, destSSE)
+		} else {
+			err = m.removeObject(ctx, client, bucket, result.Key)
+		}
+		switch {
+		case err != nil:
+			err = newError(SyncPrefixOperation, err)
+			result.Error, result.Code = err.Error(), ErrorCode(err)
+			atomic.AddInt64(&resp.Failed, 1)
+		case result.Action == syncActionCopy:
+			atomic.AddInt64(&resp.Copied, 1)
+		default:
+			atomic.AddInt64(&resp.Deleted, 1)
+		}
+	})
+	m.logger.Infof("Minio binding synced %s to %s/%s: %d copied, %d deleted, %d failed", source, bucket, destination, resp.Copied, resp.Deleted, resp.Failed)
+
+	jsonResponse, err := json.Marshal(resp)
+	if err != nil {
+		return nil, err
+	}
+	return &bindings.InvokeResponse{
+		Data: jsonResponse,
+		Metadata: map[string]string{
+			"total":     strconv.Itoa(resp.Total),
+			"unchanged": strconv.Itoa(resp.Unchanged),
+			"copied":    strconv.FormatInt(resp.Copied, 10),
+			"deleted":   strconv.FormatInt(resp.Deleted, 10),
+			"failed":    strconv.FormatInt(resp.Failed, 10),
+		},
+	}, nil
+}
@@ -0,0 +1,70 @@
+package minio
+
+import (
+	"encoding/json"
+	"github.com/dapr/components-contrib/bindings"
+	"github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSyncPrefix(t *testing.T) {
+	m, fake := newFakeMinio()
+	fake.buckets["backup"] = map[string]fakeObject{}
+	fake.put("b", "data/same.txt", []byte("same"), minio.ObjectInfo{})
+	fake.put("b", "data/changed.txt", []byte("new"), minio.ObjectInfo{})
+	fake.put("b", "data/sub/added.txt", []byte("added"), minio.ObjectInfo{})
+	fake.put("backup", "same.txt", []byte("same"), minio.ObjectInfo{})
+	fake.put("backup", "changed.txt", []byte("old"), minio.ObjectInfo{})
+	fake.put("backup", "extra.txt", []byte("extra"), minio.ObjectInfo{})
+
+	// an empty destination prefix has to be asked for
+	_, err := m.Invoke(&bindings.InvokeRequest{Operation: SyncPrefixOperation, Metadata: map[string]string{"sourcePrefix": "data/", "destinationBucket": "backup", "deleteExtras": "true"}})
+	assert.EqualError(t, err, "missing destinationPrefix field")
+	_, err = m.Invoke(&bindings.InvokeRequest{Operation: SyncPrefixOperation, Metadata: map[string]string{"destinationPrefix": "data/", "destinationBucket": "backup"}})
+	assert.EqualError(t, err, "missing sourcePrefix field")
+	assert.Len(t, fake.buckets["backup"], 3)
+
+	resp, err := m.Invoke(&bindings.InvokeRequest{Operation: SyncPrefixOperation, Metadata: map[string]string{"sourcePrefix": "data/", "destinationBucket": "backup", "destinationRoot": "true"}})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"total": "3", "unchanged": "1", "copied": "2", "deleted": "0", "failed": "0"}, resp.Metadata)
+	var synced syncPrefixResponse
+	assert.Nil(t, json.Unmarshal(resp.Data, &synced))
+	assert.Equal(t, []syncResult{
+		{Key: "changed.txt", Action: "copy"},
+		{Key: "sub/added.txt", Action: "copy"},
+	}, synced.Objects)
+	assert.Equal(t, "new", string(fake.buckets["backup"]["changed.txt"].data))
+	assert.Equal(t, "added", string(fake.buckets["backup"]["sub/added.txt"].data))
+	assert.Contains(t, fake.buckets["backup"], "extra.txt")
+
+	resp, err = m.Invoke(&bindings.InvokeRequest{Operation: SyncPrefixOperation, Metadata: map[string]string{"sourcePrefix": "data/", "destinationBucket": "backup", "destinationRoot": "true", "deleteExtras": "true"}})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"total": "3", "unchanged": "3", "copied": "0", "deleted": "1", "failed": "0"}, resp.Metadata)
+	assert.NotContains(t, fake.buckets["backup"], "extra.txt")
+	// the source is never changed
+	assert.Len(t, fake.buckets["b"], 3)
+}
+
+func TestSyncPrefixSameBucket(t *testing.T) {
+	m, target, mr := newMirrorMinio()
+	m.minioClient.(*fakeClient).put("b", "live/a.txt", []byte("hello"), minio.ObjectInfo{})
+
+	resp, err := m.Invoke(&bindings.InvokeRequest{Operation: SyncPrefixOperation, Metadata: map[string]string{"sourcePrefix": "live/", "destinationPrefix": "snapshot/"}})
+	assert.Nil(t, err)
+	assert.Equal(t, "1", resp.Metadata["copied"])
+	drain(mr)
+	assert.Equal(t, "hello", string(target.buckets["dr"]["snapshot/a.txt"].data))
+
+	_, err = m.Invoke(&bindings.InvokeRequest{Operation: SyncPrefixOperation, Metadata: map[string]string{"sourcePrefix": "live/", "destinationPrefix": "live/old/"}})
+	assert.EqualError(t, err, "Minio sourcePrefix live/ and destinationPrefix live/old/ overlap")
+}
+
+func TestSyncPrefixMissingBucket(t *testing.T) {
+	m, fake := newFakeMinio()
+	fake.put("b", "data/a.txt", []byte("a"), minio.ObjectInfo{})
+
+	_, err := m.Invoke(&bindings.InvokeRequest{Operation: SyncPrefixOperation, Metadata: map[string]string{"sourcePrefix": "data/", "destinationPrefix": "data/", "destinationBucket": "missing"}})
+	assert.Error(t, err)
+	assert.Equal(t, ErrCodeBucketNotFound, ErrorCode(newError(SyncPrefixOperation, err)))
+}
@@ -0,0 +1,160 @@
+package minio
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/dapr/components-contrib/bindings"
+	"github.com/minio/madmin-go"
+	"github.com/pkg/errors"
+	"strings"
+)
+
+const (
+	AddTierOperation   bindings.OperationKind = "addTier"
+	ListTiersOperation bindings.OperationKind = "listTiers"
+
+	TierTypeS3    = "s3"
+	TierTypeAzure = "azure"
+	TierTypeGCS   = "gcs"
+)
+
+// tierRequest is the request data of addTier. Credentials are sent in the
+// data rather than the metadata, which Dapr may log.
+type tierRequest struct {
+	Name         string          `json:"name"`
+	Type         string          `json:"type"`
+	Endpoint     string          `json:"endpoint,omitempty"`
+	Bucket       string          `json:"bucket"`
+	Prefix       string          `json:"prefix,omitempty"`
+	Region       string          `json:"region,omitempty"`
+	StorageClass string          `json:"storageClass,omitempty"`
+	AccessKey    string          `json:"accessKey,omitempty"`
+	SecretKey    string          `json:"secretKey,omitempty"`
+	AccountName  string          `json:"accountName,omitempty"`
+	AccountKey   string          `json:"accountKey,omitempty"`
+	Credentials  json.RawMessage `json:"credentials,omitempty"`
+}
+
+// tierResponse describes a tier without its credentials.
+type tierResponse struct {
+	Name         string `json:"name"`
+	Type         string `json:"type"`
+	Endpoint     string `json:"endpoint,omitempty"`
+	Bucket       string `json:"bucket"`
+	Prefix       string `json:"prefix,omitempty"`
+	Region       string `json:"region,omitempty"`
+	StorageClass string `json:"storageClass,omitempty"`
+}
+
+// tierConfig validates a tierRequest and builds the admin API configuration.
+// MinIO requires upper-case tier names.
+func tierConfig(req tierRequest) (*madmin.TierConfig, error) {
+	if req.Name == "" {
+		return nil, errors.Errorf("missing tier name")
+	}
+	if req.Name != strings.ToUpper(req.Name) {
+		return nil, errors.Errorf("tier name %s must be upper case", req.Name)
+	}
+	if req.Bucket == "" {
+		return nil, errors.Errorf("missing tier bucket")
+	}
+
+	switch req.Type {
+	case TierTypeS3:
+		if req.AccessKey == "" || req.SecretKey == "" {
+			return nil, errors.Errorf("s3 tier %s requires accessKey and secretKey", req.Name)
+		}
+		opts := []madmin.S3Options{madmin.S3Prefix(req.Prefix), madmin.S3Region(req.Region), madmin.S3StorageClass(req.StorageClass)}
+		if req.Endpoint != "" {
+			opts = append(opts, madmin.S3Endpoint(req.Endpoint))
+		}
+		return madmin.NewTierS3(req.Name, req.AccessKey, req.SecretKey, req.Bucket, opts...)
+	case TierTypeAzure:
+		if req.AccountName == "" || req.AccountKey == "" {
+			return nil, errors.Errorf("azure tier %s requires accountName and accountKey", req.Name)
+		}
+		opts := []madmin.AzureOptions{madmin.AzurePrefix(req.Prefix), madmin.AzureRegion(req.Region), madmin.AzureStorageClass(req.StorageClass)}
+		if req.Endpoint != "" {
+			opts = append(opts, madmin.AzureEndpoint(req.Endpoint))
+		}
+		return madmin.NewTierAzure(req.Name, req.AccountName, req.AccountKey, req.Bucket, opts...)
+	case TierTypeGCS:
+		if len(req.Credentials) == 0 {
+			return nil, errors.Errorf("gcs tier %s requires credentials", req.Name)
+		}
+		if req.Endpoint != "" {
+			return nil, errors.Errorf("gcs tier %s does not support a custom endpoint", req.Name)
+		}
+		return madmin.NewTierGCS(req.Name, req.Credentials, req.Bucket, madmin.GCSPrefix(req.Prefix), madmin.GCSRegion(req.Region), madmin.GCSStorageClass(req.StorageClass))
+	case "":
+		return nil, errors.Errorf("missing tier type")
+	default:
+		return nil, errors.Errorf("unsupported Minio tier type %s", req.Type)
+	}
+}
+
+func newTierResponse(cfg *madmin.TierConfig) tierResponse {
+	resp := tierResponse{Name: cfg.Name, Type: cfg.Type.String()}
+	switch {
+	case cfg.S3 != nil:
+		resp.Endpoint = cfg.S3.Endpoint
+		resp.Bucket = cfg.S3.Bucket
+		resp.Prefix = cfg.S3.Prefix
+		resp.Region = cfg.S3.Region
+		resp.StorageClass = cfg.S3.StorageClass
+	case cfg.Azure != nil:
+		resp.Endpoint = cfg.Azure.Endpoint
+		resp.Bucket = cfg.Azure.Bucket
+		resp.Prefix = cfg.Azure.Prefix
+		resp.Region = cfg.Azure.Region
+		resp.StorageClass = cfg.Azure.StorageClass
+	case cfg.GCS != nil:
+		resp.Endpoint = cfg.GCS.Endpoint
+		resp.Bucket = cfg.GCS.Bucket
+		resp.Prefix = cfg.GCS.Prefix
+		resp.Region = cfg.GCS.Region
+		resp.StorageClass = cfg.GCS.StorageClass
+	}
+	return resp
+}
+
+// addTier registers a remote tier lifecycle rules can transition objects to.
+func addTier(ctx context.Context, admin adminClient, data []byte) (*bindings.InvokeResponse, error) {
+	var req tierRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		return nil, errors.Errorf("tier request is invalid: %s", err)
+	}
+	cfg, err := tierConfig(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := admin.AddTier(ctx, cfg); err != nil {
+		return nil, fmt.Errorf("minio binding error. add tier: %w", err)
+	}
+
+	jsonResponse, err := json.Marshal(newTierResponse(cfg))
+	if err != nil {
+		return nil, err
+	}
+	return &bindings.InvokeResponse{
+		Data:     jsonResponse,
+		Metadata: map[string]string{"tier": cfg.Name},
+	}, nil
+}
+
+func listTiers(ctx context.Context, admin adminClient) (*bindings.InvokeResponse, error) {
+	tiers, err := admin.ListTiers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("minio binding error. list tiers: %w", err)
+	}
+	resp := make([]tierResponse, 0, len(tiers))
+	for _, cfg := range tiers {
+		resp = append(resp, newTierResponse(cfg))
+	}
+	jsonResponse, err := json.Marshal(resp)
+	if err != nil {
+		return nil, err
+	}
+	return &bindings.InvokeResponse{Data: jsonResponse}, nil
+}
@@ -0,0 +1,94 @@
+package minio
+
+import (
+	"encoding/json"
+	"github.com/dapr/components-contrib/bindings"
+	"github.com/minio/madmin-go"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestAddTier(t *testing.T) {
+	m, admin := newFakeAdminMinio()
+
+	resp, err := m.Invoke(&bindings.InvokeRequest{
+		Operation: AddTierOperation,
+		Data: []byte(`{
+			"name": "WARM",
+			"type": "s3",
+			"endpoint": "https://s3.eu-west-1.amazonaws.com",
+			"bucket": "archive",
+			"prefix": "minio/",
+			"region": "eu-west-1",
+			"storageClass": "STANDARD_IA",
+			"accessKey": "AKIA",
+			"secretKey": "s3-secret"
+		}`),
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "WARM", resp.Metadata["tier"])
+	assert.NotContains(t, string(resp.Data), "s3-secret")
+	assert.JSONEq(t, `{
+		"name": "WARM",
+		"type": "s3",
+		"endpoint": "https://s3.eu-west-1.amazonaws.com",
+		"bucket": "archive",
+		"prefix": "minio/",
+		"region": "eu-west-1",
+		"storageClass": "STANDARD_IA"
+	}`, string(resp.Data))
+	assert.Equal(t, &madmin.TierS3{
+		Endpoint:     "https://s3.eu-west-1.amazonaws.com",
+		AccessKey:    "AKIA",
+		SecretKey:    "s3-secret",
+		Bucket:       "archive",
+		Prefix:       "minio/",
+		Region:       "eu-west-1",
+		StorageClass: "STANDARD_IA",
+	}, admin.tiers[0].S3)
+
+	_, err = m.Invoke(&bindings.InvokeRequest{
+		Operation: AddTierOperation,
+		Data:      []byte(`{"name": "COLD", "type": "azure", "bucket": "archive", "accountName": "acct", "accountKey": "a2V5"}`),
+	})
+	assert.Nil(t, err)
+	_, err = m.Invoke(&bindings.InvokeRequest{
+		Operation: AddTierOperation,
+		Data:      []byte(`{"name": "GLACIAL", "type": "gcs", "bucket": "archive", "credentials": {"type": "service_account"}}`),
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, madmin.Azure, admin.tiers[1].Type)
+	assert.Equal(t, "acct", admin.tiers[1].Azure.AccountName)
+	assert.Equal(t, madmin.GCS, admin.tiers[2].Type)
+
+	resp, err = m.Invoke(&bindings.InvokeRequest{Operation: ListTiersOperation})
+	assert.Nil(t, err)
+	var tiers []tierResponse
+	assert.Nil(t, json.Unmarshal(resp.Data, &tiers))
+	assert.Len(t, tiers, 3)
+	assert.Equal(t, "WARM", tiers[0].Name)
+	assert.Equal(t, "azure", tiers[1].Type)
+	assert.Equal(t, "gcs", tiers[2].Type)
+	assert.NotContains(t, string(resp.Data), "a2V5")
+	assert.NotContains(t, string(resp.Data), "service_account")
+}
+
+func TestAddTierValidation(t *testing.T) {
+	m, admin := newFakeAdminMinio()
+
+	for _, data := range []string{
+		``,
+		`{"type": "s3", "bucket": "archive", "accessKey": "a", "secretKey": "s"}`,
+		`{"name": "warm", "type": "s3", "bucket": "archive", "accessKey": "a", "secretKey": "s"}`,
+		`{"name": "WARM", "type": "s3", "accessKey": "a", "secretKey": "s"}`,
+		`{"name": "WARM", "bucket": "archive"}`,
+		`{"name": "WARM", "type": "s3", "bucket": "archive", "accessKey": "a"}`,
+		`{"name": "WARM", "type": "azure", "bucket": "archive", "accountName": "acct"}`,
+		`{"name": "WARM", "type": "gcs", "bucket": "archive"}`,
+		`{"name": "WARM", "type": "hdfs", "bucket": "archive"}`,
+	} {
+		_, err := m.Invoke(&bindings.InvokeRequest{Operation: AddTierOperation, Data: []byte(data)})
+		assert.Error(t, err, data)
+	}
+	assert.Empty(t, admin.tiers)
+}
@@ -0,0 +1,64 @@
+package minio
+
+import (
+	"context"
+	"github.com/dapr/components-contrib/bindings"
+	"github.com/minio/minio-go/v7"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/putao520/minio-dapr"
+
+// SetTracerProvider creates the binding's spans with provider instead of the
+// global OpenTelemetry provider.
+func (m *Minio) SetTracerProvider(provider trace.TracerProvider) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.tracerProvider = provider
+}
+
+// startSpan starts a client span for the operation as a child of the span in
+// ctx, if any, so storage latency shows up in the caller's trace.
+func (m *Minio) startSpan(ctx context.Context, req *bindings.InvokeRequest) (context.Context, trace.Span) {
+	provider := m.tracerProvider
+	if provider == nil {
+		provider = otel.GetTracerProvider()
+	}
+	attributes := []attribute.KeyValue{
+		attribute.String("minio.operation", string(req.Operation)),
+		attribute.String("minio.endpoint", m.endpoint),
+		attribute.String("minio.bucket", m.Bucket),
+		attribute.Int("minio.request_size", len(req.Data)),
+	}
+	if key := objectNameOf(req.Metadata); key != "" {
+		attributes = append(attributes, attribute.String("minio.key", key))
+	}
+	return provider.Tracer(tracerName).Start(ctx, "minio/"+string(req.Operation),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attributes...),
+	)
+}
+
+// endSpan records the outcome; failures carry the binding's error code,
+// since OpenTelemetry span statuses only tell errors from successes.
+func endSpan(span trace.Span, resp *bindings.InvokeResponse, err error) {
+	defer span.End()
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		if code := ErrorCode(err); code != "" {
+			span.SetAttributes(attribute.String("minio.error_code", code))
+		}
+		var resp minio.ErrorResponse
+		if errors.As(err, &resp) && resp.RequestID != "" {
+			span.SetAttributes(attribute.String("minio.request_id", resp.RequestID))
+		}
+		return
+	}
+	if resp != nil {
+		span.SetAttributes(attribute.Int("minio.response_size", len(resp.Data)))
+	}
+}
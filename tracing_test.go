@@ -0,0 +1,51 @@
+package minio
+
+import (
+	"context"
+	"fmt"
+	"github.com/dapr/components-contrib/bindings"
+	"github.com/dapr/kit/logger"
+	"github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"testing"
+)
+
+func TestSpanStatus(t *testing.T) {
+	m := NewMinio(logger.NewLogger("minio"))
+	m.Bucket = "b"
+
+	recorder := tracetest.NewSpanRecorder()
+	m.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	_, span := m.startSpan(context.Background(), &bindings.InvokeRequest{
+		Operation: bindings.GetOperation,
+		Metadata:  map[string]string{"objectName": "a.txt"},
+	})
+	err := newError(bindings.GetOperation, fmt.Errorf("get object error: %w", minio.ErrorResponse{
+		Code:       "NoSuchKey",
+		StatusCode: 404,
+		RequestID:  "16A2B",
+	}))
+	endSpan(span, nil, err)
+
+	// spans are recorded when they end
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	data := spans[0]
+	assert.Equal(t, "minio/get", data.Name())
+	assert.Equal(t, codes.Error, data.Status().Code)
+	assert.Equal(t, trace.SpanKindClient, data.SpanKind())
+	attributes := map[attribute.Key]string{}
+	for _, kv := range data.Attributes() {
+		attributes[kv.Key] = kv.Value.Emit()
+	}
+	assert.Equal(t, ErrCodeNotFound, attributes["minio.error_code"])
+	assert.Equal(t, "16A2B", attributes["minio.request_id"])
+	assert.Equal(t, "a.txt", attributes["minio.key"])
+}
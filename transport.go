@@ -0,0 +1,163 @@
+package minio
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"github.com/minio/minio-go/v7"
+	"github.com/pkg/errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+const (
+	ClientCertKey = "clientCert"
+	ClientKeyKey  = "clientKey"
+	CACertKey     = "caCert"
+	CAPathKey     = "caPath"
+
+	SkipTLSVerifyKey = "skipTLSVerify"
+
+	MaxIdleConnsKey          = "maxIdleConns"
+	MaxIdleConnsPerHostKey   = "maxIdleConnsPerHost"
+	IdleConnTimeoutKey       = "idleConnTimeout"
+	TLSHandshakeTimeoutKey   = "tlsHandshakeTimeout"
+	ResponseHeaderTimeoutKey = "responseHeaderTimeout"
+)
+
+var transportTuningKeys = []string{
+	MaxIdleConnsKey, MaxIdleConnsPerHostKey, IdleConnTimeoutKey, TLSHandshakeTimeoutKey, ResponseHeaderTimeoutKey,
+}
+
+// newTransport returns nil when the default minio-go transport is sufficient.
+func (m *Minio) newTransport(p map[string]string, secure bool) (http.RoundTripper, error) {
+	skipVerify := propertyToBool(p, SkipTLSVerifyKey)
+	if !skipVerify && !hasAnyProperty(p, append([]string{ClientCertKey, ClientKeyKey, CACertKey, CAPathKey}, transportTuningKeys...)...) {
+		return nil, nil
+	}
+
+	tr, err := minio.DefaultTransport(secure)
+	if err != nil {
+		return nil, fmt.Errorf("minio binding error. create transport: %w", err)
+	}
+	if tr.TLSClientConfig == nil {
+		tr.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	if err := tuneTransport(tr, p); err != nil {
+		return nil, err
+	}
+	if err := loadClientCertificate(tr.TLSClientConfig, p); err != nil {
+		return nil, err
+	}
+	if err := loadRootCAs(tr.TLSClientConfig, p); err != nil {
+		return nil, err
+	}
+	if skipVerify {
+		m.logger.Warn("INSECURE: Minio skipTLSVerify is enabled, server certificates will not be verified. Do not use this in production")
+		tr.TLSClientConfig.InsecureSkipVerify = true
+	}
+
+	return tr, nil
+}
+
+// tuneTransport overrides the connection pool settings of the minio-go
+// default transport, leaving unset ones at their defaults.
+func tuneTransport(tr *http.Transport, p map[string]string) error {
+	maxIdle, err := sizeProperty(p, MaxIdleConnsKey, int64(tr.MaxIdleConns))
+	if err != nil {
+		return err
+	}
+	maxIdlePerHost, err := sizeProperty(p, MaxIdleConnsPerHostKey, int64(tr.MaxIdleConnsPerHost))
+	if err != nil {
+		return err
+	}
+	if tr.IdleConnTimeout, err = durationProperty(p, IdleConnTimeoutKey, tr.IdleConnTimeout); err != nil {
+		return err
+	}
+	if tr.TLSHandshakeTimeout, err = durationProperty(p, TLSHandshakeTimeoutKey, tr.TLSHandshakeTimeout); err != nil {
+		return err
+	}
+	if tr.ResponseHeaderTimeout, err = durationProperty(p, ResponseHeaderTimeoutKey, tr.ResponseHeaderTimeout); err != nil {
+		return err
+	}
+	tr.MaxIdleConns = int(maxIdle)
+	tr.MaxIdleConnsPerHost = int(maxIdlePerHost)
+	return nil
+}
+
+func loadClientCertificate(config *tls.Config, p map[string]string) error {
+	certValue, keyValue := p[ClientCertKey], p[ClientKeyKey]
+	if certValue == "" && keyValue == "" {
+		return nil
+	}
+	if certValue == "" || keyValue == "" {
+		return errors.Errorf("Minio clientCert and clientKey must be set together")
+	}
+
+	certPEM, err := readPEM(certValue)
+	if err != nil {
+		return fmt.Errorf("minio binding error. read clientCert: %w", err)
+	}
+	keyPEM, err := readPEM(keyValue)
+	if err != nil {
+		return fmt.Errorf("minio binding error. read clientKey: %w", err)
+	}
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return fmt.Errorf("minio binding error. load client certificate: %w", err)
+	}
+	config.Certificates = []tls.Certificate{cert}
+	return nil
+}
+
+// loadRootCAs adds the configured CA bundle to the system roots, so private
+// CAs are trusted without touching the host trust store.
+func loadRootCAs(config *tls.Config, p map[string]string) error {
+	var bundles [][]byte
+	if v := p[CACertKey]; v != "" {
+		bundles = append(bundles, []byte(v))
+	}
+	if v := p[CAPathKey]; v != "" {
+		b, err := ioutil.ReadFile(v)
+		if err != nil {
+			return fmt.Errorf("minio binding error. read caPath: %w", err)
+		}
+		bundles = append(bundles, b)
+	}
+	if len(bundles) == 0 {
+		return nil
+	}
+
+	pool := config.RootCAs
+	if pool == nil {
+		var err error
+		if pool, err = x509.SystemCertPool(); err != nil {
+			pool = x509.NewCertPool()
+		}
+	}
+	for _, b := range bundles {
+		if !pool.AppendCertsFromPEM(b) {
+			return errors.Errorf("no certificates found in Minio CA bundle")
+		}
+	}
+	config.RootCAs = pool
+	return nil
+}
+
+func hasAnyProperty(p map[string]string, keys ...string) bool {
+	for _, k := range keys {
+		if p[k] != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// readPEM accepts either inline PEM content or a path to a PEM file.
+func readPEM(value string) ([]byte, error) {
+	if strings.Contains(value, "-----BEGIN") {
+		return []byte(value), nil
+	}
+	return ioutil.ReadFile(value)
+}
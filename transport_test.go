@@ -0,0 +1,65 @@
+package minio
+
+import (
+	"github.com/dapr/kit/logger"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestNewTransport(t *testing.T) {
+	m := NewMinio(logger.NewLogger("test"))
+
+	t.Run("default transport when no TLS options are set", func(t *testing.T) {
+		tr, err := m.newTransport(map[string]string{}, true)
+		assert.NoError(t, err)
+		assert.Nil(t, tr)
+	})
+
+	t.Run("clientCert without clientKey", func(t *testing.T) {
+		_, err := m.newTransport(map[string]string{ClientCertKey: "cert.pem"}, true)
+		assert.EqualError(t, err, "Minio clientCert and clientKey must be set together")
+	})
+
+	t.Run("caCert without certificates", func(t *testing.T) {
+		_, err := m.newTransport(map[string]string{CACertKey: "not a certificate"}, true)
+		assert.EqualError(t, err, "no certificates found in Minio CA bundle")
+	})
+
+	t.Run("skipTLSVerify", func(t *testing.T) {
+		tr, err := m.newTransport(map[string]string{SkipTLSVerifyKey: "true"}, true)
+		assert.NoError(t, err)
+		assert.True(t, tr.(*http.Transport).TLSClientConfig.InsecureSkipVerify)
+	})
+
+	t.Run("connection pool tuning", func(t *testing.T) {
+		tr, err := m.newTransport(map[string]string{
+			MaxIdleConnsKey:          "512",
+			MaxIdleConnsPerHostKey:   "128",
+			IdleConnTimeoutKey:       "2m",
+			ResponseHeaderTimeoutKey: "30s",
+		}, false)
+		assert.NoError(t, err)
+		h := tr.(*http.Transport)
+		assert.Equal(t, 512, h.MaxIdleConns)
+		assert.Equal(t, 128, h.MaxIdleConnsPerHost)
+		assert.Equal(t, 2*time.Minute, h.IdleConnTimeout)
+		assert.Equal(t, 30*time.Second, h.ResponseHeaderTimeout)
+	})
+
+	t.Run("invalid tuning value", func(t *testing.T) {
+		_, err := m.newTransport(map[string]string{IdleConnTimeoutKey: "soon"}, false)
+		assert.EqualError(t, err, "idleConnTimeout soon is invalid")
+	})
+}
+
+func TestReadPEM(t *testing.T) {
+	inline := "-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----\n"
+	b, err := readPEM(inline)
+	assert.NoError(t, err)
+	assert.Equal(t, inline, string(b))
+
+	_, err = readPEM("/nonexistent/cert.pem")
+	assert.Error(t, err)
+}
@@ -0,0 +1,224 @@
+package minio
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/dapr/components-contrib/bindings"
+	"github.com/pkg/errors"
+	"io"
+	"io/ioutil"
+	"path"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// UnpackOperation expands the tar or zip in the request data into one object
+// per file, keyed by prefix followed by the file's path in the archive; the
+// component's keyTemplate is not applied. The format is archiveFormat, or
+// detected from the data when unset. Files are uploaded like createBatch
+// items, with the request metadata; one that fails is reported without
+// failing the others.
+const UnpackOperation bindings.OperationKind = "unpack"
+
+const (
+	// MaxUnpackSizeKey caps the total uncompressed size of the files in an
+	// archive; 0 removes the limit. An archive over it is rejected before any
+	// file is uploaded.
+	MaxUnpackSizeKey = "maxUnpackSize"
+
+	defaultMaxUnpackSize = 1 << 30
+	maxUnpackEntries     = 10000
+)
+
+type unpackEntry struct {
+	name string
+	size int64
+	open func() (io.ReadCloser, error)
+}
+
+type unpackResponse struct {
+	Prefix  string              `json:"prefix"`
+	Total   int                 `json:"total"`
+	Created int64               `json:"created"`
+	Failed  int64               `json:"failed"`
+	Objects []batchCreateResult `json:"objects"`
+}
+
+// detectArchiveFormat tells a zip, which starts with a local file header,
+// from a tar.
+func detectArchiveFormat(data []byte) string {
+	if bytes.HasPrefix(data, []byte("PK\x03\x04")) || bytes.HasPrefix(data, []byte("PK\x05\x06")) {
+		return ArchiveFormatZip
+	}
+	return ArchiveFormatTar
+}
+
+// unpackName returns the archive path of a file as a key relative to the
+// prefix, refusing paths that would escape it.
+func unpackName(name string) (string, error) {
+	clean := path.Clean("/" + strings.ReplaceAll(name, "\\", "/"))
+	if strings.Contains("/"+name+"/", "/../") || clean == "/" {
+		return "", errors.Errorf("minio binding error. archive path %s is not allowed", name)
+	}
+	return strings.TrimPrefix(clean, "/"), nil
+}
+
+// unpackLimit counts the files and bytes of an archive against the limits.
+type unpackLimit struct {
+	maxSize int64
+	size    int64
+	entries int
+}
+
+func (l *unpackLimit) add(size int64) error {
+	if l.entries++; l.entries > maxUnpackEntries {
+		return &Error{Code: ErrCodeTooLarge, Operation: UnpackOperation, Err: errors.Errorf("archive has more than %d files", maxUnpackEntries)}
+	}
+	if l.size += size; size < 0 || l.maxSize > 0 && l.size > l.maxSize {
+		return &Error{Code: ErrCodeTooLarge, Operation: UnpackOperation, Err: errors.Errorf("archive is larger than maxUnpackSize %d", l.maxSize)}
+	}
+	return nil
+}
+
+// zipEntries checks the sizes recorded in the archive; archive/zip fails a
+// file that decompresses to more than its recorded size.
+func zipEntries(data []byte, limit *unpackLimit) ([]unpackEntry, error) {
+	r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+	var entries []unpackEntry
+	for _, f := range r.File {
+		if !f.Mode().IsRegular() {
+			continue
+		}
+		name, err := unpackName(f.Name)
+		if err != nil {
+			return nil, err
+		}
+		if err := limit.add(int64(f.UncompressedSize64)); err != nil {
+			return nil, err
+		}
+		entries = append(entries, unpackEntry{name: name, size: int64(f.UncompressedSize64), open: f.Open})
+	}
+	return entries, nil
+}
+
+func tarEntries(data []byte, limit *unpackLimit) ([]unpackEntry, error) {
+	r := tar.NewReader(bytes.NewReader(data))
+	var entries []unpackEntry
+	for {
+		header, err := r.Next()
+		if err == io.EOF {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if header.Typeflag != tar.TypeReg && header.Typeflag != tar.TypeRegA {
+			continue
+		}
+		name, err := unpackName(header.Name)
+		if err != nil {
+			return nil, err
+		}
+		// a tar is read in one pass, so each file is held in memory until
+		// it is uploaded; the header size is checked before reading it
+		if err := limit.add(header.Size); err != nil {
+			return nil, err
+		}
+		content, err := ioutil.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, unpackEntry{name: name, size: int64(len(content)), open: func() (io.ReadCloser, error) {
+			return ioutil.NopCloser(bytes.NewReader(content)), nil
+		}})
+	}
+}
+
+func (m *Minio) unpack(ctx context.Context, req *bindings.InvokeRequest) (*bindings.InvokeResponse, error) {
+	p := req.Metadata
+
+	data, err := m.decodePayload(req.Data, p)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, errors.Errorf("missing data field")
+	}
+	concurrency, err := batchConcurrency(p)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []unpackEntry
+	limit := &unpackLimit{maxSize: m.maxUnpackSize}
+	switch format := p[ArchiveFormatKey]; format {
+	case "":
+		if detectArchiveFormat(data) == ArchiveFormatZip {
+			entries, err = zipEntries(data, limit)
+		} else {
+			entries, err = tarEntries(data, limit)
+		}
+	case ArchiveFormatZip:
+		entries, err = zipEntries(data, limit)
+	case ArchiveFormatTar:
+		entries, err = tarEntries(data, limit)
+	default:
+		return nil, errors.Errorf("unsupported Minio archiveFormat %s", format)
+	}
+	if _, ok := err.(*Error); ok {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("minio binding error. unpack: %w", err)
+	}
+
+	prefix := p[PrefixKey]
+	resp := unpackResponse{Prefix: prefix, Total: len(entries), Objects: make([]batchCreateResult, len(entries))}
+	runBounded(concurrency, len(entries), func(i int) {
+		result := m.unpackOne(ctx, p, prefix+entries[i].name, entries[i])
+		if result.Error != "" {
+			atomic.AddInt64(&resp.Failed, 1)
+		} else {
+			atomic.AddInt64(&resp.Created, 1)
+		}
+		resp.Objects[i] = result
+	})
+
+	jsonResponse, err := json.Marshal(resp)
+	if err != nil {
+		return nil, err
+	}
+	return &bindings.InvokeResponse{
+		Data: jsonResponse,
+		Metadata: map[string]string{
+			"total":   strconv.Itoa(resp.Total),
+			"created": strconv.FormatInt(resp.Created, 10),
+			"failed":  strconv.FormatInt(resp.Failed, 10),
+		},
+	}, nil
+}
+
+// unpackOne uploads an entry to its key; keyTemplate doesn't apply, as it
+// would drop the archive path and put every entry on the same key.
+func (m *Minio) unpackOne(ctx context.Context, p map[string]string, key string, entry unpackEntry) batchCreateResult {
+	r, err := entry.open()
+	if err != nil {
+		return batchCreateResult{Key: key, Error: err.Error()}
+	}
+	defer r.Close()
+
+	resp, err := m.uploadObject(ctx, key, p, r, entry.size)
+	if err != nil {
+		err = newError(bindings.CreateOperation, err)
+		return batchCreateResult{Key: key, Error: err.Error(), Code: ErrorCode(err)}
+	}
+	return batchCreateResult{Key: resp.Metadata["key"], VersionID: resp.Metadata["versionID"], ETag: resp.Metadata["etag"]}
+}
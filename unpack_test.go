@@ -0,0 +1,128 @@
+package minio
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"github.com/dapr/components-contrib/bindings"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func tarOf(files ...string) []byte {
+	var buf bytes.Buffer
+	w := tar.NewWriter(&buf)
+	w.WriteHeader(&tar.Header{Typeflag: tar.TypeDir, Name: "dir/", Mode: 0o755})
+	for _, name := range files {
+		w.WriteHeader(&tar.Header{Typeflag: tar.TypeReg, Name: name, Size: int64(len(name)), Mode: 0o644})
+		w.Write([]byte(name))
+	}
+	w.Close()
+	return buf.Bytes()
+}
+
+func zipOf(files ...string) []byte {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for _, name := range files {
+		f, _ := w.Create(name)
+		f.Write([]byte(name))
+	}
+	w.Close()
+	return buf.Bytes()
+}
+
+func TestUnpackTar(t *testing.T) {
+	m, fake := newFakeMinio()
+
+	resp, err := m.Invoke(&bindings.InvokeRequest{Operation: UnpackOperation, Data: tarOf("a.txt", "dir/b.txt"), Metadata: map[string]string{"prefix": "site/"}})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"total": "2", "created": "2", "failed": "0"}, resp.Metadata)
+	assert.Equal(t, "a.txt", string(fake.buckets["b"]["site/a.txt"].data))
+	assert.Equal(t, "dir/b.txt", string(fake.buckets["b"]["site/dir/b.txt"].data))
+	assert.Len(t, fake.buckets["b"], 2)
+
+	var unpacked unpackResponse
+	assert.Nil(t, json.Unmarshal(resp.Data, &unpacked))
+	assert.Equal(t, "site/a.txt", unpacked.Objects[0].Key)
+	assert.Equal(t, fake.buckets["b"]["site/a.txt"].info.ETag, unpacked.Objects[0].ETag)
+}
+
+func TestUnpackZip(t *testing.T) {
+	m, fake := newFakeMinio()
+
+	// the format is detected, and base64 data decoded like for create
+	data := base64.StdEncoding.EncodeToString(zipOf("a.txt", "./dir/b.txt"))
+	_, err := m.Invoke(&bindings.InvokeRequest{Operation: UnpackOperation, Data: []byte(data), Metadata: map[string]string{"encoding": "base64"}})
+	assert.Nil(t, err)
+	assert.Equal(t, "a.txt", string(fake.buckets["b"]["a.txt"].data))
+	assert.Equal(t, "./dir/b.txt", string(fake.buckets["b"]["dir/b.txt"].data))
+}
+
+func TestUnpackRejectsEscapingPaths(t *testing.T) {
+	m, fake := newFakeMinio()
+
+	_, err := m.Invoke(&bindings.InvokeRequest{Operation: UnpackOperation, Data: zipOf("a.txt", "../b.txt"), Metadata: map[string]string{"prefix": "site/"}})
+	assert.Error(t, err)
+	assert.Empty(t, fake.buckets["b"])
+}
+
+func TestUnpackName(t *testing.T) {
+	for name, key := range map[string]string{"a.txt": "a.txt", "/abs/a.txt": "abs/a.txt", "x/./y": "x/y", `win\dir\a.txt`: "win/dir/a.txt"} {
+		got, err := unpackName(name)
+		assert.Nil(t, err)
+		assert.Equal(t, key, got)
+	}
+	for _, name := range []string{"..", "../a", "a/../../b", "/", "."} {
+		_, err := unpackName(name)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestUnpackValidation(t *testing.T) {
+	m, _ := newFakeMinio()
+
+	_, err := m.Invoke(&bindings.InvokeRequest{Operation: UnpackOperation})
+	assert.EqualError(t, err, "missing data field")
+	_, err = m.Invoke(&bindings.InvokeRequest{Operation: UnpackOperation, Data: tarOf("a.txt"), Metadata: map[string]string{"archiveFormat": "rar"}})
+	assert.EqualError(t, err, "unsupported Minio archiveFormat rar")
+	_, err = m.Invoke(&bindings.InvokeRequest{Operation: UnpackOperation, Data: []byte("not an archive"), Metadata: map[string]string{"archiveFormat": "zip"}})
+	assert.Error(t, err)
+}
+
+func TestUnpackIgnoresKeyTemplate(t *testing.T) {
+	m, fake := newFakeMinio()
+	m.properties = map[string]string{KeyTemplateKey: "uploads/{yyyy}/{uuid}"}
+
+	_, err := m.Invoke(&bindings.InvokeRequest{Operation: UnpackOperation, Data: tarOf("a.txt", "dir/b.txt"), Metadata: map[string]string{"prefix": "site/"}})
+	assert.Nil(t, err)
+	assert.Equal(t, "a.txt", string(fake.buckets["b"]["site/a.txt"].data))
+	assert.Equal(t, "dir/b.txt", string(fake.buckets["b"]["site/dir/b.txt"].data))
+	assert.Len(t, fake.buckets["b"], 2)
+}
+
+func TestUnpackLimits(t *testing.T) {
+	m, fake := newFakeMinio()
+	m.maxUnpackSize = 10
+
+	// "a.txt" and "dir/b.txt" are 14 bytes together
+	for _, data := range [][]byte{tarOf("a.txt", "dir/b.txt"), zipOf("a.txt", "dir/b.txt")} {
+		_, err := m.Invoke(&bindings.InvokeRequest{Operation: UnpackOperation, Data: data})
+		assert.EqualError(t, err, "minio binding error. unpack TooLarge: archive is larger than maxUnpackSize 10")
+		assert.Equal(t, ErrCodeTooLarge, ErrorCode(err))
+		assert.Empty(t, fake.buckets["b"])
+	}
+
+	m.maxUnpackSize = 14
+	_, err := m.Invoke(&bindings.InvokeRequest{Operation: UnpackOperation, Data: zipOf("a.txt", "dir/b.txt")})
+	assert.Nil(t, err)
+	assert.Len(t, fake.buckets["b"], 2)
+
+	limit := &unpackLimit{}
+	for i := 0; i < maxUnpackEntries; i++ {
+		assert.Nil(t, limit.add(1))
+	}
+	assert.EqualError(t, limit.add(1), "minio binding error. unpack TooLarge: archive has more than 10000 files")
+}
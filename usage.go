@@ -0,0 +1,108 @@
+package minio
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/dapr/components-contrib/bindings"
+	"github.com/minio/madmin-go"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const DataUsageOperation bindings.OperationKind = "dataUsage"
+
+type dataUsageResponse struct {
+	LastUpdate time.Time          `json:"lastUpdate"`
+	Since      *time.Time         `json:"since,omitempty"`
+	Buckets    uint64             `json:"buckets"`
+	Objects    uint64             `json:"objects"`
+	Size       uint64             `json:"size"`
+	Usage      []bucketUsageEntry `json:"usage"`
+}
+
+type bucketUsageEntry struct {
+	Bucket        string            `json:"bucket"`
+	Size          uint64            `json:"size"`
+	Objects       uint64            `json:"objects"`
+	SizeGrowth    *int64            `json:"sizeGrowth,omitempty"`
+	ObjectsGrowth *int64            `json:"objectsGrowth,omitempty"`
+	SizeHistogram map[string]uint64 `json:"sizeHistogram,omitempty"`
+}
+
+// usageHistory keeps the previous data usage snapshot, so growth can be
+// reported without a metrics store.
+type usageHistory struct {
+	mu       sync.Mutex
+	previous *madmin.DataUsageInfo
+	current  *madmin.DataUsageInfo
+}
+
+// observe records info and returns the snapshot to compare it with. MinIO
+// refreshes data usage in the background, so a snapshot seen before is
+// compared with the one preceding it.
+func (h *usageHistory) observe(info madmin.DataUsageInfo) *madmin.DataUsageInfo {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.current == nil || !info.LastUpdate.Equal(h.current.LastUpdate) {
+		h.previous, h.current = h.current, &info
+	}
+	return h.previous
+}
+
+// dataUsage reports MinIO's per-bucket sizes and object counts, with their
+// growth since the previous data usage scan the binding saw. bucket limits
+// the result to one bucket.
+func (m *Minio) dataUsage(ctx context.Context, admin adminClient, p map[string]string) (*bindings.InvokeResponse, error) {
+	info, err := admin.DataUsageInfo(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("minio binding error. data usage: %w", err)
+	}
+	previous := m.usage.observe(info)
+
+	resp := dataUsageResponse{
+		LastUpdate: info.LastUpdate,
+		Buckets:    info.BucketsCount,
+		Objects:    info.ObjectsTotalCount,
+		Size:       info.ObjectsTotalSize,
+		Usage:      make([]bucketUsageEntry, 0, len(info.BucketsUsage)),
+	}
+	if previous != nil {
+		resp.Since = &previous.LastUpdate
+	}
+	only := p[BucketKey]
+	for bucket, usage := range info.BucketsUsage {
+		if only != "" && bucket != only {
+			continue
+		}
+		entry := bucketUsageEntry{
+			Bucket:        bucket,
+			Size:          usage.Size,
+			Objects:       usage.ObjectsCount,
+			SizeHistogram: usage.ObjectSizesHistogram,
+		}
+		if previous != nil {
+			before := previous.BucketsUsage[bucket]
+			sizeGrowth := int64(usage.Size) - int64(before.Size)
+			objectsGrowth := int64(usage.ObjectsCount) - int64(before.ObjectsCount)
+			entry.SizeGrowth, entry.ObjectsGrowth = &sizeGrowth, &objectsGrowth
+		}
+		resp.Usage = append(resp.Usage, entry)
+	}
+	sort.Slice(resp.Usage, func(i, j int) bool { return resp.Usage[i].Bucket < resp.Usage[j].Bucket })
+
+	jsonResponse, err := json.Marshal(resp)
+	if err != nil {
+		return nil, err
+	}
+	return &bindings.InvokeResponse{
+		Data: jsonResponse,
+		Metadata: map[string]string{
+			"lastUpdate": info.LastUpdate.Format(time.RFC3339),
+			"objects":    strconv.FormatUint(info.ObjectsTotalCount, 10),
+			"size":       strconv.FormatUint(info.ObjectsTotalSize, 10),
+		},
+	}, nil
+}
@@ -0,0 +1,65 @@
+package minio
+
+import (
+	"github.com/dapr/components-contrib/bindings"
+	"github.com/minio/madmin-go"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestDataUsage(t *testing.T) {
+	m, admin := newFakeAdminMinio()
+	scan := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	admin.usage = madmin.DataUsageInfo{
+		LastUpdate:        scan,
+		ObjectsTotalCount: 3,
+		ObjectsTotalSize:  300,
+		BucketsCount:      2,
+		BucketsUsage: map[string]madmin.BucketUsageInfo{
+			"tenant-b": {Size: 100, ObjectsCount: 1},
+			"tenant-a": {Size: 200, ObjectsCount: 2, ObjectSizesHistogram: map[string]uint64{"LESS_THAN_1024_B": 2}},
+		},
+	}
+
+	resp, err := m.Invoke(&bindings.InvokeRequest{Operation: DataUsageOperation})
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{
+		"lastUpdate": "2021-06-01T12:00:00Z",
+		"buckets": 2,
+		"objects": 3,
+		"size": 300,
+		"usage": [
+			{"bucket": "tenant-a", "size": 200, "objects": 2, "sizeHistogram": {"LESS_THAN_1024_B": 2}},
+			{"bucket": "tenant-b", "size": 100, "objects": 1}
+		]
+	}`, string(resp.Data))
+	assert.Equal(t, map[string]string{"lastUpdate": "2021-06-01T12:00:00Z", "objects": "3", "size": "300"}, resp.Metadata)
+
+	// the next scan reports growth since the first one, also when queried again
+	admin.usage = madmin.DataUsageInfo{
+		LastUpdate:        scan.Add(time.Hour),
+		ObjectsTotalCount: 2,
+		ObjectsTotalSize:  250,
+		BucketsCount:      2,
+		BucketsUsage: map[string]madmin.BucketUsageInfo{
+			"tenant-b": {Size: 50, ObjectsCount: 0},
+			"tenant-a": {Size: 200, ObjectsCount: 2},
+		},
+	}
+	for i := 0; i < 2; i++ {
+		resp, err = m.Invoke(&bindings.InvokeRequest{
+			Operation: DataUsageOperation,
+			Metadata:  map[string]string{"bucket": "tenant-b"},
+		})
+		assert.Nil(t, err)
+		assert.JSONEq(t, `{
+			"lastUpdate": "2021-06-01T13:00:00Z",
+			"since": "2021-06-01T12:00:00Z",
+			"buckets": 2,
+			"objects": 2,
+			"size": 250,
+			"usage": [{"bucket": "tenant-b", "size": 50, "objects": 0, "sizeGrowth": -50, "objectsGrowth": -1}]
+		}`, string(resp.Data))
+	}
+}
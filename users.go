@@ -0,0 +1,119 @@
+package minio
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/dapr/components-contrib/bindings"
+	"github.com/minio/madmin-go"
+	"github.com/pkg/errors"
+)
+
+const (
+	UserAccessKeyKey = "userAccessKey"
+	UserSecretKeyKey = "userSecretKey"
+	UserStatusKey    = "status"
+
+	CreateUserOperation    bindings.OperationKind = "createUser"
+	SetUserSecretOperation bindings.OperationKind = "setUserSecret"
+	SetUserStatusOperation bindings.OperationKind = "setUserStatus"
+	DeleteUserOperation    bindings.OperationKind = "deleteUser"
+)
+
+type userResponse struct {
+	User   string `json:"user"`
+	Status string `json:"status,omitempty"`
+}
+
+func userAccessKey(p map[string]string) (string, error) {
+	user := p[UserAccessKeyKey]
+	if user == "" {
+		return "", errors.Errorf("missing %s field", UserAccessKeyKey)
+	}
+	return user, nil
+}
+
+func userSecretKey(p map[string]string) (string, error) {
+	secret := p[UserSecretKeyKey]
+	if secret == "" {
+		return "", errors.Errorf("missing %s field", UserSecretKeyKey)
+	}
+	return secret, nil
+}
+
+func userResult(user string, status madmin.AccountStatus) (*bindings.InvokeResponse, error) {
+	jsonResponse, err := json.Marshal(userResponse{User: user, Status: string(status)})
+	if err != nil {
+		return nil, err
+	}
+	return &bindings.InvokeResponse{
+		Data:     jsonResponse,
+		Metadata: map[string]string{"user": user},
+	}, nil
+}
+
+// createUser adds an enabled user, or resets the secret key of an existing one.
+func createUser(ctx context.Context, admin adminClient, p map[string]string) (*bindings.InvokeResponse, error) {
+	user, err := userAccessKey(p)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := userSecretKey(p)
+	if err != nil {
+		return nil, err
+	}
+	if err := admin.AddUser(ctx, user, secret); err != nil {
+		return nil, fmt.Errorf("minio binding error. create user: %w", err)
+	}
+	return userResult(user, madmin.AccountEnabled)
+}
+
+// setUserSecret changes the secret key of a user, keeping its status.
+func setUserSecret(ctx context.Context, admin adminClient, p map[string]string) (*bindings.InvokeResponse, error) {
+	user, err := userAccessKey(p)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := userSecretKey(p)
+	if err != nil {
+		return nil, err
+	}
+	info, err := admin.GetUserInfo(ctx, user)
+	if err != nil {
+		return nil, fmt.Errorf("minio binding error. user info: %w", err)
+	}
+	if err := admin.SetUser(ctx, user, secret, info.Status); err != nil {
+		return nil, fmt.Errorf("minio binding error. set user secret: %w", err)
+	}
+	return userResult(user, info.Status)
+}
+
+func setUserStatus(ctx context.Context, admin adminClient, p map[string]string) (*bindings.InvokeResponse, error) {
+	user, err := userAccessKey(p)
+	if err != nil {
+		return nil, err
+	}
+	status := madmin.AccountStatus(p[UserStatusKey])
+	switch status {
+	case madmin.AccountEnabled, madmin.AccountDisabled:
+	case "":
+		return nil, errors.Errorf("missing %s field", UserStatusKey)
+	default:
+		return nil, errors.Errorf("unsupported Minio user status %s", status)
+	}
+	if err := admin.SetUserStatus(ctx, user, status); err != nil {
+		return nil, fmt.Errorf("minio binding error. set user status: %w", err)
+	}
+	return userResult(user, status)
+}
+
+func deleteUser(ctx context.Context, admin adminClient, p map[string]string) (*bindings.InvokeResponse, error) {
+	user, err := userAccessKey(p)
+	if err != nil {
+		return nil, err
+	}
+	if err := admin.RemoveUser(ctx, user); err != nil {
+		return nil, fmt.Errorf("minio binding error. delete user: %w", err)
+	}
+	return userResult(user, "")
+}
@@ -0,0 +1,83 @@
+package minio
+
+import (
+	"github.com/dapr/components-contrib/bindings"
+	"github.com/minio/madmin-go"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestUserLifecycle(t *testing.T) {
+	m, admin := newFakeAdminMinio()
+
+	resp, err := m.Invoke(&bindings.InvokeRequest{
+		Operation: CreateUserOperation,
+		Metadata:  map[string]string{"userAccessKey": "tenant-a", "userSecretKey": "secret-1"},
+	})
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"user":"tenant-a","status":"enabled"}`, string(resp.Data))
+	assert.NotContains(t, string(resp.Data), "secret-1")
+	assert.Equal(t, madmin.UserInfo{SecretKey: "secret-1", Status: madmin.AccountEnabled}, admin.users["tenant-a"])
+
+	resp, err = m.Invoke(&bindings.InvokeRequest{
+		Operation: SetUserStatusOperation,
+		Metadata:  map[string]string{"userAccessKey": "tenant-a", "status": "disabled"},
+	})
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"user":"tenant-a","status":"disabled"}`, string(resp.Data))
+
+	// changing the secret keeps the user disabled
+	_, err = m.Invoke(&bindings.InvokeRequest{
+		Operation: SetUserSecretOperation,
+		Metadata:  map[string]string{"userAccessKey": "tenant-a", "userSecretKey": "secret-2"},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, madmin.UserInfo{SecretKey: "secret-2", Status: madmin.AccountDisabled}, admin.users["tenant-a"])
+
+	resp, err = m.Invoke(&bindings.InvokeRequest{
+		Operation: DeleteUserOperation,
+		Metadata:  map[string]string{"userAccessKey": "tenant-a"},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "tenant-a", resp.Metadata["user"])
+	assert.Empty(t, admin.users)
+
+	_, err = m.Invoke(&bindings.InvokeRequest{
+		Operation: SetUserSecretOperation,
+		Metadata:  map[string]string{"userAccessKey": "tenant-a", "userSecretKey": "secret-3"},
+	})
+	assert.Error(t, err)
+}
+
+func TestUserValidation(t *testing.T) {
+	m, _ := newFakeAdminMinio()
+
+	tests := []struct {
+		operation bindings.OperationKind
+		metadata  map[string]string
+	}{
+		{CreateUserOperation, map[string]string{"userSecretKey": "s"}},
+		{CreateUserOperation, map[string]string{"userAccessKey": "u"}},
+		{SetUserSecretOperation, map[string]string{"userAccessKey": "u"}},
+		{SetUserStatusOperation, map[string]string{"userAccessKey": "u"}},
+		{SetUserStatusOperation, map[string]string{"userAccessKey": "u", "status": "locked"}},
+		{DeleteUserOperation, map[string]string{}},
+	}
+	for _, tt := range tests {
+		_, err := m.Invoke(&bindings.InvokeRequest{Operation: tt.operation, Metadata: tt.metadata})
+		assert.Error(t, err, "%s %v", tt.operation, tt.metadata)
+	}
+}
+
+func TestUserOperationsAudited(t *testing.T) {
+	m, _ := newFakeAdminMinio()
+	req := &bindings.InvokeRequest{
+		Operation: CreateUserOperation,
+		Metadata:  map[string]string{"userAccessKey": "tenant-a", "userSecretKey": "secret-1"},
+	}
+	assert.True(t, auditedOperations[req.Operation])
+
+	record := m.newAuditRecord(req, nil, nil, time.Now())
+	assert.Equal(t, "tenant-a", record.User)
+}
@@ -0,0 +1,172 @@
+package minio
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/pkg/errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	VaultAddrKey      = "vaultAddr"
+	VaultTokenKey     = "vaultToken"
+	VaultTokenFileKey = "vaultTokenFile"
+	VaultPathKey      = "vaultPath"
+	VaultNamespaceKey = "vaultNamespace"
+
+	AuthModeVault = "vault"
+
+	vaultRequestTimeout = 30 * time.Second
+)
+
+// vaultProvider leases credentials from a Vault AWS or MinIO secrets engine.
+// minio-go calls Retrieve again once the lease is about to run out; renewable
+// leases are extended first and reissued only when renewal is refused.
+type vaultProvider struct {
+	credentials.Expiry
+
+	client    *http.Client
+	addr      string
+	path      string
+	token     string
+	tokenFile string
+	namespace string
+
+	leaseID   string
+	renewable bool
+	value     credentials.Value
+}
+
+type vaultSecret struct {
+	LeaseID       string                 `json:"lease_id"`
+	LeaseDuration int                    `json:"lease_duration"`
+	Renewable     bool                   `json:"renewable"`
+	Data          map[string]interface{} `json:"data"`
+}
+
+func vaultCredentials(p map[string]string) (*credentials.Credentials, error) {
+	addr, ok := p[VaultAddrKey]
+	if !ok || addr == "" {
+		return nil, errors.Errorf("missing Minio vaultAddr string")
+	}
+	path, ok := p[VaultPathKey]
+	if !ok || path == "" {
+		return nil, errors.Errorf("missing Minio vaultPath string")
+	}
+	if p[VaultTokenKey] == "" && p[VaultTokenFileKey] == "" {
+		return nil, errors.Errorf("missing Minio vaultToken or vaultTokenFile string")
+	}
+
+	return credentials.New(&vaultProvider{
+		client:    &http.Client{Timeout: vaultRequestTimeout},
+		addr:      strings.TrimSuffix(addr, "/"),
+		path:      strings.Trim(path, "/"),
+		token:     p[VaultTokenKey],
+		tokenFile: p[VaultTokenFileKey],
+		namespace: p[VaultNamespaceKey],
+	}), nil
+}
+
+func (v *vaultProvider) Retrieve() (credentials.Value, error) {
+	if v.leaseID != "" && v.renewable {
+		if err := v.renew(); err == nil {
+			return v.value, nil
+		}
+	}
+	return v.issue()
+}
+
+func (v *vaultProvider) issue() (credentials.Value, error) {
+	var secret vaultSecret
+	if err := v.do(http.MethodGet, v.path, nil, &secret); err != nil {
+		return credentials.Value{}, err
+	}
+
+	value := credentials.Value{
+		AccessKeyID:     stringField(secret.Data, "access_key", "accessKeyId"),
+		SecretAccessKey: stringField(secret.Data, "secret_key", "secretAccessKey"),
+		SessionToken:    stringField(secret.Data, "security_token", "sessionToken"),
+		SignerType:      credentials.SignatureV4,
+	}
+	if value.AccessKeyID == "" || value.SecretAccessKey == "" {
+		return credentials.Value{}, errors.Errorf("Vault secret %s has no access key", v.path)
+	}
+
+	v.leaseID = secret.LeaseID
+	v.renewable = secret.Renewable
+	v.value = value
+	v.setLease(secret.LeaseDuration)
+	return value, nil
+}
+
+func (v *vaultProvider) renew() error {
+	var secret vaultSecret
+	body := map[string]string{"lease_id": v.leaseID}
+	if err := v.do(http.MethodPut, "sys/leases/renew", body, &secret); err != nil {
+		return err
+	}
+	v.renewable = secret.Renewable
+	v.setLease(secret.LeaseDuration)
+	return nil
+}
+
+// setLease schedules the next Retrieve a tenth of the lease before it ends.
+// Leases without a duration are re-read daily.
+func (v *vaultProvider) setLease(seconds int) {
+	d := time.Duration(seconds) * time.Second
+	if d <= 0 {
+		d = 24 * time.Hour
+	}
+	v.SetExpiration(time.Now().Add(d), d/10)
+}
+
+func (v *vaultProvider) do(method, path string, body interface{}, out interface{}) error {
+	token := v.token
+	if v.tokenFile != "" {
+		b, err := ioutil.ReadFile(v.tokenFile)
+		if err != nil {
+			return fmt.Errorf("minio binding error. read vault token: %w", err)
+		}
+		token = strings.TrimSpace(string(b))
+	}
+
+	var reqBody []byte
+	if body != nil {
+		var err error
+		if reqBody, err = json.Marshal(body); err != nil {
+			return err
+		}
+	}
+	req, err := http.NewRequest(method, v.addr+"/v1/"+path, bytes.NewReader(reqBody))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Vault-Token", token)
+	if v.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", v.namespace)
+	}
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("minio binding error. vault request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("Vault %s %s returned status %d", method, path, resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func stringField(data map[string]interface{}, names ...string) string {
+	for _, name := range names {
+		if s, ok := data[name].(string); ok && s != "" {
+			return s
+		}
+	}
+	return ""
+}
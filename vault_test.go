@@ -0,0 +1,78 @@
+package minio
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestVaultProvider(t *testing.T) {
+	var issued int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "vault-token", r.Header.Get("X-Vault-Token"))
+		switch r.URL.Path {
+		case "/v1/aws/creds/minio":
+			issued++
+			_ = json.NewEncoder(w).Encode(vaultSecret{
+				LeaseID:       "aws/creds/minio/1",
+				LeaseDuration: 3600,
+				Renewable:     true,
+				Data:          map[string]interface{}{"access_key": "leased", "secret_key": "leased-secret"},
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	c, err := vaultCredentials(map[string]string{
+		VaultAddrKey:  server.URL,
+		VaultPathKey:  "aws/creds/minio",
+		VaultTokenKey: "vault-token",
+	})
+	assert.NoError(t, err)
+
+	v, err := c.Get()
+	assert.NoError(t, err)
+	assert.Equal(t, "leased", v.AccessKeyID)
+	assert.Equal(t, "leased-secret", v.SecretAccessKey)
+	assert.Equal(t, 1, issued)
+}
+
+func TestVaultProviderRenew(t *testing.T) {
+	var issued, renewed int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/minio/keys/app":
+			issued++
+			_ = json.NewEncoder(w).Encode(vaultSecret{
+				LeaseID:       "minio/keys/app/1",
+				LeaseDuration: 60,
+				Renewable:     true,
+				Data:          map[string]interface{}{"accessKeyId": "key", "secretAccessKey": "secret"},
+			})
+		case "/v1/sys/leases/renew":
+			renewed++
+			_ = json.NewEncoder(w).Encode(vaultSecret{LeaseID: "minio/keys/app/1", LeaseDuration: 60, Renewable: true})
+		}
+	}))
+	defer server.Close()
+
+	provider := &vaultProvider{client: server.Client(), addr: server.URL, path: "minio/keys/app", token: "t"}
+	v, err := provider.Retrieve()
+	assert.NoError(t, err)
+	assert.Equal(t, "key", v.AccessKeyID)
+
+	v, err = provider.Retrieve()
+	assert.NoError(t, err)
+	assert.Equal(t, "key", v.AccessKeyID)
+	assert.Equal(t, 1, issued)
+	assert.Equal(t, 1, renewed)
+}
+
+func TestVaultCredentialsValidation(t *testing.T) {
+	_, err := vaultCredentials(map[string]string{VaultAddrKey: "http://vault:8200", VaultPathKey: "aws/creds/minio"})
+	assert.EqualError(t, err, "missing Minio vaultToken or vaultTokenFile string")
+}
@@ -0,0 +1,54 @@
+package minio
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+const TraceRequestsKey = "traceRequests"
+
+// headers whose values must never reach the logs
+var redactedHeaders = []string{
+	"authorization",
+	"x-amz-security-token",
+	"x-amz-server-side-encryption-customer-key",
+	"x-amz-copy-source-server-side-encryption-customer-key",
+}
+
+var signatureParam = regexp.MustCompile(`(?i)(X-Amz-Signature|X-Amz-Credential|X-Amz-Security-Token)=[^&\s]*`)
+
+// redactingWriter receives minio-go's HTTP dumps and forwards them line by
+// line with credentials, session tokens and SSE-C keys removed.
+type redactingWriter struct {
+	mu   sync.Mutex
+	buf  bytes.Buffer
+	emit func(line string)
+}
+
+func (w *redactingWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.buf.Write(p)
+	for {
+		i := bytes.IndexByte(w.buf.Bytes(), '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		line := strings.TrimRight(string(w.buf.Next(i+1)), "\r\n")
+		w.emit(redactLine(line))
+	}
+}
+
+func redactLine(line string) string {
+	if i := strings.IndexByte(line, ':'); i > 0 {
+		name := strings.ToLower(strings.TrimSpace(line[:i]))
+		for _, h := range redactedHeaders {
+			if name == h {
+				return line[:i+1] + " **REDACTED**"
+			}
+		}
+	}
+	return signatureParam.ReplaceAllString(line, "$1=**REDACTED**")
+}
@@ -0,0 +1,24 @@
+package minio
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestRedactingWriter(t *testing.T) {
+	var lines []string
+	w := &redactingWriter{emit: func(line string) { lines = append(lines, line) }}
+
+	w.Write([]byte("GET /b/a.txt?X-Amz-Credential=AKIA%2F2021&X-Amz-Signature=abc123&x=1 HTTP/1.1\r\nHost: minio:9000\r\nAuthor"))
+	w.Write([]byte("ization: AWS4-HMAC-SHA256 Credential=AKIA/2021, Signature=abc123\r\n"))
+	w.Write([]byte("X-Amz-Server-Side-Encryption-Customer-Key: c2VjcmV0\r\nX-Amz-Security-Token: token\r\n\r\n"))
+
+	assert.Equal(t, []string{
+		"GET /b/a.txt?X-Amz-Credential=**REDACTED**&X-Amz-Signature=**REDACTED**&x=1 HTTP/1.1",
+		"Host: minio:9000",
+		"Authorization: **REDACTED**",
+		"X-Amz-Server-Side-Encryption-Customer-Key: **REDACTED**",
+		"X-Amz-Security-Token: **REDACTED**",
+		"",
+	}, lines)
+}